@@ -0,0 +1,134 @@
+package quadtree
+
+// Aggregator summarizes the objects of a subtree into a value: Zero returns the value of an empty
+// subtree, Add folds an object into a value, and Merge folds the value of a child subtree into a value.
+// Add and Merge may modify and return acc
+type Aggregator struct {
+	Zero  func() interface{}
+	Add   func(acc interface{}, obj PhysicalObject) interface{}
+	Merge func(acc, child interface{}) interface{}
+}
+
+// namedAggregator is an aggregator registered with WithAggregator
+type namedAggregator struct {
+	name string
+	agg  Aggregator
+}
+
+// cachedAggregate is the value of an aggregator for a subtree, valid while the subtree is unchanged
+type cachedAggregate struct {
+	value interface{}
+	stamp uint64 // change stamp of the subtree when the value was computed
+	epoch uint64 // aggregate epoch of the tree when the value was computed
+	valid bool
+}
+
+// WithAggregator registers an aggregator under name. Aggregates are cached per node and only
+// recomputed for subtrees whose objects changed (see ChangeStamp), so that a query costs
+// O(log n) folds once the tree settles. Changes the tree does not see, such as an object attribute
+// changing in place, require the object to be removed and inserted again.
+// With StraddleDuplicate, an object counts once per node storing it
+func WithAggregator(name string, agg Aggregator) Option {
+	return func(c *config) {
+		c.aggregators = append(c.aggregators, namedAggregator{name, agg})
+	}
+}
+
+// aggregatorIndex returns the index of the aggregator registered under name, -1 if there is none
+func (c *config) aggregatorIndex(name string) int {
+	for i, a := range c.aggregators {
+		if a.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Aggregate returns the value of the named aggregator over the objects of the subtree,
+// nil if no aggregator is registered under name
+func (qt *Quadtree) Aggregate(name string) interface{} {
+	if !qt.ready() {
+		return nil
+	}
+	index := qt.m_config.aggregatorIndex(name)
+	if index < 0 {
+		return nil
+	}
+	return qt.aggregate(index)
+}
+
+// aggregate returns the value of the aggregator at index over the subtree, computing it if needed
+func (qt *Quadtree) aggregate(index int) interface{} {
+	c := qt.m_config
+	if len(qt.m_aggregates) < len(c.aggregators) {
+		qt.m_aggregates = make([]cachedAggregate, len(c.aggregators))
+	}
+	cached := &qt.m_aggregates[index]
+	if cached.valid && cached.stamp == qt.m_stamp && cached.epoch == c.aggregateEpoch {
+		return cached.value
+	}
+
+	agg := c.aggregators[index].agg
+	value := agg.Zero()
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if obj := ele.Value.(PhysicalObject); !qt.buried(obj) {
+			value = agg.Add(value, obj)
+		}
+	}
+	flags := qt.m_ActiveNodes
+	child := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			value = agg.Merge(value, qt.Nodes[child].aggregate(index))
+		}
+		flags >>= 1
+		child += 1
+	}
+	*cached = cachedAggregate{value: value, stamp: qt.m_stamp, epoch: c.aggregateEpoch, valid: true}
+	return value
+}
+
+// AggregateInRegion returns the value of the named aggregator over the objects whose bounds overlap
+// region, touching borders count as overlap. The cached values of the nodes region fully covers are
+// merged, and only the nodes on the border of region are searched.
+// It returns nil if no aggregator is registered under name
+func (qt *Quadtree) AggregateInRegion(name string, region *Bounds) interface{} {
+	if !qt.ready() {
+		return nil
+	}
+	index := qt.m_config.aggregatorIndex(name)
+	if index < 0 {
+		return nil
+	}
+	agg := qt.m_config.aggregators[index].agg
+	return qt.aggregateInRegion(index, agg, region, agg.Zero())
+}
+
+func (qt *Quadtree) aggregateInRegion(index int, agg Aggregator, region *Bounds, value interface{}) interface{} {
+	// objects of the root may lie outside of its bounds, the root is never taken as a whole
+	if qt.m_parent != nil && covers(region, qt.searchBounds()) {
+		return agg.Merge(value, qt.aggregate(index))
+	}
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		obj := ele.Value.(PhysicalObject)
+		if !qt.buried(obj) && region.Intersects(boundsOf(obj)) {
+			value = agg.Add(value, obj)
+		}
+	}
+	flags := qt.m_ActiveNodes
+	child := 0
+	for flags > 0 {
+		if flags&1 == 1 && qt.Nodes[child].searchBounds().Intersects(region) {
+			value = qt.Nodes[child].aggregateInRegion(index, agg, region, value)
+		}
+		flags >>= 1
+		child += 1
+	}
+	return value
+}
+
+// covers tells whether region completely contains b
+func covers(region, b *Bounds) bool {
+	return b.X >= region.X && b.Y >= region.Y &&
+		b.X+b.Width <= region.X+region.Width && b.Y+b.Height <= region.Y+region.Height
+}
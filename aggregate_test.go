@@ -0,0 +1,68 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func countingAggregator(adds *int) quadtree.Aggregator {
+	return quadtree.Aggregator{
+		Zero: func() interface{} { return 0 },
+		Add: func(acc interface{}, obj quadtree.PhysicalObject) interface{} {
+			*adds += 1
+			return acc.(int) + 1
+		},
+		Merge: func(acc, child interface{}) interface{} { return acc.(int) + child.(int) },
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	adds := 0
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10,
+		quadtree.WithAggregator("count", countingAggregator(&adds)))
+	for _, obj := range []*TestPhysicalObject{{1, 1, 1, 1}, {2.5, 2.5, 1, 1}, {6, 6, 1, 1}, {3, 3, 2, 2}} {
+		qt.Insert(obj)
+	}
+
+	if got := qt.Aggregate("count"); got != 4 {
+		t.Errorf("expects 4 objects in the tree, got %v", got)
+	}
+	if got := qt.Nodes[0].Aggregate("count"); got != 2 {
+		t.Errorf("expects 2 objects in the top left quadrant, got %v", got)
+	}
+	if got := qt.Aggregate("missing"); got != nil {
+		t.Errorf("expects nil for an unknown aggregator, got %v", got)
+	}
+
+	adds = 0
+	qt.Aggregate("count")
+	if adds != 0 {
+		t.Errorf("expects cached aggregates for an unchanged tree, got %d objects added", adds)
+	}
+	qt.Insert(&TestPhysicalObject{7, 7, 0.5, 0.5})
+	if got := qt.Aggregate("count"); got != 5 || adds >= 5 {
+		t.Errorf("expects 5 objects recomputed along the changed path only, got %v after %d adds", got, adds)
+	}
+}
+
+func TestAggregateInRegion(t *testing.T) {
+	adds := 0
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10,
+		quadtree.WithAggregator("count", countingAggregator(&adds)))
+	straddling := &TestPhysicalObject{3, 3, 2, 2}
+	for _, obj := range []*TestPhysicalObject{{1, 1, 1, 1}, {2.5, 2.5, 1, 1}, {6, 6, 1, 1}, straddling} {
+		qt.Insert(obj)
+	}
+
+	if got := qt.AggregateInRegion("count", &quadtree.Bounds{0, 0, 4, 4}); got != 3 {
+		t.Errorf("expects the top left quadrant and the straddling object, got %v", got)
+	}
+	qt.MarkRemoved(straddling)
+	if got := qt.AggregateInRegion("count", &quadtree.Bounds{0, 0, 8, 8}); got != 3 {
+		t.Errorf("expects removed objects left out, got %v", got)
+	}
+	if got := qt.AggregateInRegion("count", &quadtree.Bounds{6.5, 0, 1, 1}); got != 0 {
+		t.Errorf("expects no object in an empty region, got %v", got)
+	}
+}
@@ -0,0 +1,119 @@
+package quadtree
+
+import (
+	"container/list"
+)
+
+// Allocator provides the nodes created by a tree when it splits, and the lists storing the objects of
+// those nodes. It does not provide slots for the objects themselves: every object stored, or moved to
+// another node, still costs the tree a list element allocated by container/list, so that an Allocator
+// removes the allocations of splits and rebuilds, not those of inserts and moves
+type Allocator interface {
+	NewNode() *Quadtree        // returns a node for the tree to initialize, its fields are overwritten
+	FreeNode(node *Quadtree)   // takes back a node dropped from the tree, children are freed first
+	NewObjectList() *list.List // returns an empty list to store the objects of a node
+}
+
+// WithAllocator makes the tree take the nodes it creates from a, and give them back to a once pruned
+// or cleared, e.g. to back the tree with a NodeArena reset at every level load. The root node is
+// never taken from a. Nodes given back must not be used any longer, including by Frozen views
+func WithAllocator(a Allocator) Option {
+	return func(c *config) {
+		c.allocator = a
+	}
+}
+
+// newNode returns a node from the allocator of the tree, or a new node
+func (c *config) newNode() *Quadtree {
+	if c.allocator != nil {
+		return c.allocator.NewNode()
+	}
+	return &Quadtree{}
+}
+
+// newObjectList returns an empty list from the allocator of the tree, or a new list
+func (c *config) newObjectList() *list.List {
+	if c.allocator != nil {
+		return c.allocator.NewObjectList()
+	}
+	return list.New()
+}
+
+// free gives the subtree back to the allocator of the tree, if any
+func (qt *Quadtree) free() {
+	a := qt.m_config.allocator
+	if a == nil {
+		return
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			qt.Nodes[index].free()
+		}
+		flags >>= 1
+		index += 1
+	}
+	a.FreeNode(qt)
+}
+
+// arenaSlabSize is the number of nodes or lists allocated at once by a NodeArena
+const arenaSlabSize = 64
+
+// NodeArena is an Allocator handing out nodes and lists from slabs it keeps across Resets, so that
+// a tree rebuilt at every level load stops allocating nodes once the arena has grown large enough.
+// The list elements of the objects are not part of the arena, see Allocator. Freed nodes are only
+// reused after Reset
+type NodeArena struct {
+	nodes     [][]Quadtree
+	lists     [][]list.List
+	usedNodes int
+	usedLists int
+}
+
+// NewNodeArena returns an arena with room for capacity nodes before growing
+func NewNodeArena(capacity int) *NodeArena {
+	a := &NodeArena{}
+	for n := 0; n < capacity; n += arenaSlabSize {
+		a.nodes = append(a.nodes, make([]Quadtree, arenaSlabSize))
+		a.lists = append(a.lists, make([]list.List, arenaSlabSize))
+	}
+	return a
+}
+
+// NewNode returns the next free node of the arena
+func (a *NodeArena) NewNode() *Quadtree {
+	slab, slot := a.usedNodes/arenaSlabSize, a.usedNodes%arenaSlabSize
+	if slab == len(a.nodes) {
+		a.nodes = append(a.nodes, make([]Quadtree, arenaSlabSize))
+	}
+	a.usedNodes += 1
+	return &a.nodes[slab][slot]
+}
+
+// FreeNode does nothing, nodes are reclaimed all at once by Reset
+func (a *NodeArena) FreeNode(node *Quadtree) {}
+
+// NewObjectList returns the next free list of the arena
+func (a *NodeArena) NewObjectList() *list.List {
+	slab, slot := a.usedLists/arenaSlabSize, a.usedLists%arenaSlabSize
+	if slab == len(a.lists) {
+		a.lists = append(a.lists, make([]list.List, arenaSlabSize))
+	}
+	a.usedLists += 1
+	return a.lists[slab][slot].Init()
+}
+
+// Len returns the number of nodes handed out since the last Reset
+func (a *NodeArena) Len() int {
+	return a.usedNodes
+}
+
+// Reset makes every node and list of the arena free again. The trees using the arena must have been
+// discarded or cleared before
+func (a *NodeArena) Reset() {
+	for i := 0; i < a.usedNodes; i += 1 {
+		a.nodes[i/arenaSlabSize][i%arenaSlabSize] = Quadtree{}
+	}
+	a.usedNodes, a.usedLists = 0, 0
+}
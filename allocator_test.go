@@ -0,0 +1,51 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// countingAllocator counts the nodes taken from and given back to an arena
+type countingAllocator struct {
+	*quadtree.NodeArena
+	freed int
+}
+
+func (a *countingAllocator) FreeNode(node *quadtree.Quadtree) {
+	a.freed += 1
+	a.NodeArena.FreeNode(node)
+}
+
+func TestAllocator(t *testing.T) {
+	a := &countingAllocator{NodeArena: quadtree.NewNodeArena(4)}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 3, quadtree.WithAllocator(a), quadtree.WithEagerCollapse())
+	near := &TestPhysicalObject{0, 0, 1, 1}
+	far := &TestPhysicalObject{7, 7, 1, 1}
+	qt.Insert(near)
+	qt.Insert(far)
+	if a.Len() != qt.Stats().Nodes-1 {
+		t.Errorf("expects every node below the root to come from the arena, got %d for %+v", a.Len(), qt.Stats())
+	}
+	if found := qt.FindObject(far); found == nil || found == qt {
+		t.Errorf("expects the object in a node of the arena, got %v", found)
+	}
+
+	qt.Remove(far)
+	if a.freed != 1 {
+		t.Errorf("expects the collapsed node to be freed, got %d", a.freed)
+	}
+	qt.UpdateTree(listOf())
+	if a.freed != a.Len() {
+		t.Errorf("expects every node to be freed once cleared, got %d of %d", a.freed, a.Len())
+	}
+
+	a.Reset()
+	if a.Len() != 0 {
+		t.Errorf("expects an empty arena after Reset, got %d", a.Len())
+	}
+	qt.UpdateTree(listOf(near, far))
+	if got := qt.GetIntersectedObjects(far); len(got) != 0 || qt.FindObject(near) == nil {
+		t.Errorf("expects the tree to work with reused nodes, got %v", got)
+	}
+}
@@ -0,0 +1,52 @@
+package quadtree
+
+import (
+	"sort"
+)
+
+// SoundSource is implemented by objects emitting sound, Volume is the gain of the source at zero distance
+type SoundSource interface {
+	Volume() float64
+}
+
+// SourceGain is a sound source heard by a listener
+type SourceGain struct {
+	Source   PhysicalObject
+	Distance float64 // distance from the listener to the center of the source
+	Gain     float64 // volume of the source attenuated by the distance
+}
+
+type byGain []SourceGain
+
+func (s byGain) Len() int           { return len(s) }
+func (s byGain) Less(i, j int) bool { return s[i].Gain > s[j].Gain }
+func (s byGain) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// AudibleSources returns the objects implementing SoundSource whose centers lie within maxRange of the
+// listener, loudest first, with their gain attenuated linearly from their Volume at the listener down
+// to 0 at maxRange
+func (qt *Quadtree) AudibleSources(listenerX, listenerY, maxRange float64) []SourceGain {
+	if !qt.ready() || maxRange <= 0 {
+		return nil
+	}
+	listener := &RectMinMax{listenerX, listenerY, listenerX, listenerY}
+	var sources []SourceGain
+	for _, obj := range qt.QueryRing(listenerX, listenerY, 0, maxRange) {
+		source, ok := obj.(SoundSource)
+		if !ok {
+			continue
+		}
+		cx, cy := center(obj)
+		d := Distance(listener, &RectMinMax{cx, cy, cx, cy})
+		if d > maxRange {
+			continue
+		}
+		sources = append(sources, SourceGain{
+			Source:   obj,
+			Distance: d,
+			Gain:     source.Volume() * (1 - d/maxRange),
+		})
+	}
+	sort.Stable(byGain(sources))
+	return sources
+}
@@ -0,0 +1,44 @@
+package quadtree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// speaker is an object emitting sound
+type speaker struct {
+	TestPhysicalObject
+	volume float64
+}
+
+func (s *speaker) Volume() float64 { return s.volume }
+
+func TestAudibleSources(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 32, 32}, 1, 4)
+	near := &speaker{TestPhysicalObject{4, 0, 2, 2}, 1}
+	loud := &speaker{TestPhysicalObject{0, 7, 2, 2}, 4}
+	far := &speaker{TestPhysicalObject{20, 20, 2, 2}, 1}
+	silent := &TestPhysicalObject{2, 2, 1, 1}
+	for _, obj := range []quadtree.PhysicalObject{near, loud, far, silent} {
+		qt.Insert(obj)
+	}
+
+	sources := qt.AudibleSources(1, 1, 10)
+	if len(sources) != 2 {
+		t.Fatalf("expects the 2 sources in range, got %v", sources)
+	}
+	if sources[0].Source != quadtree.PhysicalObject(loud) || sources[1].Source != quadtree.PhysicalObject(near) {
+		t.Errorf("expects the loudest source first, got %v", sources)
+	}
+	if sources[1].Distance != 4 || math.Abs(sources[1].Gain-0.6) > 1e-9 {
+		t.Errorf("expects a gain of 0.6 at distance 4, got %+v", sources[1])
+	}
+	if sources[0].Distance != 7 || math.Abs(sources[0].Gain-1.2) > 1e-9 {
+		t.Errorf("expects a gain of 1.2 at distance 7, got %+v", sources[0])
+	}
+	if got := qt.AudibleSources(1, 1, 0); len(got) != 0 {
+		t.Errorf("expects nothing without range, got %v", got)
+	}
+}
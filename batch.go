@@ -0,0 +1,186 @@
+package quadtree
+
+import (
+	"container/list"
+)
+
+// Tx buffers the mutations of a Batch, they are applied when the batch function returns
+type Tx struct {
+	inserts []PhysicalObject
+	removes []PhysicalObject
+	moves   []PhysicalObject
+}
+
+// Insert buffers the insertion of the object
+func (tx *Tx) Insert(obj PhysicalObject) {
+	tx.inserts = append(tx.inserts, obj)
+}
+
+// Remove buffers the removal of the object
+func (tx *Tx) Remove(obj PhysicalObject) {
+	tx.removes = append(tx.removes, obj)
+}
+
+// Move buffers the relocation of an object of the tree which moved
+func (tx *Tx) Move(obj PhysicalObject) {
+	tx.moves = append(tx.moves, obj)
+}
+
+// Batch calls fn with a Tx buffering mutations, and applies them once fn returns: removals first,
+// then moves and insertions, which are redistributed from the root in a single Build pass.
+// Queries made while fn runs see the tree as it was before the batch.
+// The first error an Insert would have returned is returned, the other mutations are still applied
+func (qt *Quadtree) Batch(fn func(tx *Tx)) error {
+	if !qt.ready() {
+		return ErrNilQuadtree
+	}
+	tx := &Tx{}
+	fn(tx)
+
+	root := qt.root()
+	detached := root.detach(append(tx.removes[:len(tx.removes):len(tx.removes)], tx.moves...))
+	for _, removed := range detached[:len(tx.removes)] {
+		if removed != nil {
+			root.forget(removed)
+			root.notifyRemoved(removed, RemovedExplicitly)
+		}
+	}
+
+	var err error
+	var added []PhysicalObject
+	pending := &batchSet{c: root.m_config, objects: make(map[PhysicalObject]bool)}
+	for _, moved := range detached[len(tx.removes):] {
+		if moved != nil {
+			root.syncHandle(moved)
+			root.logMove(moved)
+			added = append(added, moved)
+			pending.add(moved)
+		}
+	}
+	for _, obj := range tx.inserts {
+		root.exhume(obj)
+		if !validCoordinates(obj) {
+			if e := root.handleInvalid(obj); err == nil {
+				err = e
+			}
+			continue
+		}
+		if root.m_config.rejectDuplicates && (root.FindObject(obj) != nil || pending.has(obj)) {
+			if err == nil {
+				err = ErrDuplicateObject
+			}
+			continue
+		}
+		added = append(added, obj)
+		pending.add(obj)
+	}
+
+	pushed := 0
+	for _, obj := range added {
+		if !validCoordinates(obj) {
+			root.handleInvalid(obj)
+			continue
+		}
+		root.m_Objects.PushBack(obj)
+		root.assignID(obj)
+		root.logInsert(obj)
+		pushed += 1
+	}
+	root.touch(pushed)
+	root.profile("Build", root.build)
+	root.enforceCapacity()
+	return err
+}
+
+// batchSet holds the objects a Batch adds to the tree, so that WithRejectDuplicates also rejects an
+// object inserted twice in a batch
+type batchSet struct {
+	c       *config
+	objects map[PhysicalObject]bool
+	equal   []PhysicalObject // objects compared WithEqual, which cannot be looked up
+}
+
+func (s *batchSet) add(obj PhysicalObject) {
+	if s.c.equal != nil {
+		s.equal = append(s.equal, obj)
+		return
+	}
+	s.objects[obj] = true
+}
+
+// has tells whether the batch already adds obj, as identified by WithEqual if set
+func (s *batchSet) has(obj PhysicalObject) bool {
+	for _, another := range s.equal {
+		if s.c.same(another, obj) {
+			return true
+		}
+	}
+	return s.objects[obj]
+}
+
+// batchTarget is an object a Batch removes or moves, and what a traversal found of it
+type batchTarget struct {
+	obj    PhysicalObject
+	stored PhysicalObject // object stored in the tree for obj, nil until found
+	nodes  []*Quadtree    // nodes storing a copy of obj
+	elems  []*list.Element
+}
+
+// detach removes the objects of targets from the subtree in a single traversal, as remove would one
+// by one, and returns the stored objects in the order of targets, nil for those not found. Nodes left
+// empty are collapsed at once for trees collapsing eagerly
+func (qt *Quadtree) detach(targets []PhysicalObject) []PhysicalObject {
+	detached := make([]PhysicalObject, len(targets))
+	if len(targets) == 0 {
+		return detached
+	}
+	c := qt.m_config
+	all := c.straddlePolicy == StraddleDuplicate
+	found := make([]batchTarget, len(targets))
+	byObject := make(map[PhysicalObject][]int, len(targets))
+	for i, obj := range targets {
+		found[i].obj = obj
+		byObject[obj] = append(byObject[obj], i)
+	}
+	// match records the element for the first target wanting it, every copy with StraddleDuplicate
+	match := func(node *Quadtree, ele *list.Element, i int) bool {
+		t := &found[i]
+		if t.stored != nil && !all {
+			return false
+		}
+		t.stored = ele.Value.(PhysicalObject)
+		t.nodes = append(t.nodes, node)
+		t.elems = append(t.elems, ele)
+		return true
+	}
+	qt.eachNode(func(node *Quadtree) {
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			one := ele.Value.(PhysicalObject)
+			if c.equal == nil {
+				for _, i := range byObject[one] {
+					if match(node, ele, i) {
+						break
+					}
+				}
+				continue
+			}
+			for i := range found {
+				if c.same(one, found[i].obj) && match(node, ele, i) {
+					break
+				}
+			}
+		}
+	})
+	for i := range found {
+		t := &found[i]
+		for j, node := range t.nodes {
+			node.m_Objects.Remove(t.elems[j])
+			node.touch(-1)
+			if c.eagerCollapse {
+				node.collapseUp()
+			}
+		}
+		detached[i] = t.stored
+	}
+	return detached
+}
@@ -0,0 +1,103 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestBatch(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	mover := &TestPhysicalObject{1, 1, 1, 1}
+	doomed := &TestPhysicalObject{6, 6, 1, 1}
+	qt.Insert(mover)
+	qt.Insert(doomed)
+	newcomer := &TestPhysicalObject{6, 1, 1, 1}
+
+	err := qt.Batch(func(tx *quadtree.Tx) {
+		tx.Remove(doomed)
+		mover.x, mover.y = 1, 6
+		tx.Move(mover)
+		tx.Insert(newcomer)
+		if qt.FindObject(doomed) == nil || qt.FindObject(newcomer) != nil {
+			t.Errorf("expects the batch not to be applied before it ends")
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if qt.FindObject(doomed) != nil {
+		t.Errorf("expects the removed object to be gone")
+	}
+	if qt.FindObject(mover) != qt.Nodes[2] || qt.FindObject(newcomer) != qt.Nodes[1] {
+		t.Errorf("expects moved and inserted objects in the bottom left and top right quadrants:\n%s", qt.String())
+	}
+	if objects, _ := qt.Size(); objects != 2 {
+		t.Errorf("expects 2 objects, got %d", objects)
+	}
+}
+
+func TestBatchCopies(t *testing.T) {
+	for _, opts := range [][]quadtree.Option{nil, {quadtree.WithEagerCollapse()}} {
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, opts...)
+		stacked := &TestPhysicalObject{1, 1, 1, 1}
+		straddling := &TestPhysicalObject{3, 3, 2, 2}
+		kept := &TestPhysicalObject{6, 6, 1, 1}
+		for _, obj := range []*TestPhysicalObject{stacked, stacked, stacked, straddling, kept} {
+			qt.Insert(obj)
+		}
+		// removing an object twice removes two of its copies, as two Removes would
+		qt.Batch(func(tx *quadtree.Tx) {
+			tx.Remove(stacked)
+			tx.Remove(stacked)
+			tx.Remove(straddling)
+		})
+		var left []quadtree.PhysicalObject
+		qt.Walk(func(obj quadtree.PhysicalObject) { left = append(left, obj) })
+		if !sameObjects(left, stacked, kept) {
+			t.Errorf("expects a copy of the stacked object and the kept one to stay, got %v", left)
+		}
+	}
+
+	// trees duplicating straddling objects remove every copy at once
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithStraddlePolicy(quadtree.StraddleDuplicate))
+	straddling := &TestPhysicalObject{3, 3, 2, 2}
+	kept := &TestPhysicalObject{6, 6, 1, 1}
+	qt.Insert(straddling)
+	qt.Insert(kept)
+	qt.Batch(func(tx *quadtree.Tx) {
+		tx.Remove(straddling)
+	})
+	if found := qt.Retrieve(&quadtree.Bounds{0, 0, 8, 8}); !sameObjects(found, kept) {
+		t.Errorf("expects every copy of the straddling object to be removed, got %v", found)
+	}
+}
+
+func TestBatchRejectsDuplicates(t *testing.T) {
+	equalBounds := func(a, b quadtree.PhysicalObject) bool {
+		return a.X() == b.X() && a.Y() == b.Y() && a.Width() == b.Width() && a.Height() == b.Height()
+	}
+	for _, opts := range [][]quadtree.Option{
+		{quadtree.WithRejectDuplicates()},
+		{quadtree.WithRejectDuplicates(), quadtree.WithEqual(equalBounds)},
+	} {
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, opts...)
+		mover := &TestPhysicalObject{1, 1, 1, 1}
+		qt.Insert(mover)
+		twice := &TestPhysicalObject{6, 6, 1, 1}
+		err := qt.Batch(func(tx *quadtree.Tx) {
+			tx.Insert(twice)
+			tx.Insert(twice)
+			mover.x = 2
+			tx.Move(mover)
+			tx.Insert(mover)
+		})
+		if err != quadtree.ErrDuplicateObject {
+			t.Errorf("expects ErrDuplicateObject, got %v", err)
+		}
+		if objects, _ := qt.Size(); objects != 2 {
+			t.Errorf("expects the object inserted twice and the moved one to be stored once, got %d objects", objects)
+		}
+	}
+}
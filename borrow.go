@@ -0,0 +1,60 @@
+package quadtree
+
+import (
+	"time"
+)
+
+// Borrow makes the query append its results to a buffer the tree reuses, rather than allocating a
+// new slice. The results are only valid until the next borrowing query or the next change of the
+// tree, including objects moving during Update. In builds with the race detector, objects of stale
+// results panic when used
+func Borrow() QueryOption {
+	return func(qc *queryConfig) {
+		qc.borrow = true
+	}
+}
+
+// Copy makes the query return its results in a slice of its own, which stays valid whatever happens
+// to the tree afterwards. This is the default
+func Copy() QueryOption {
+	return func(qc *queryConfig) {
+		qc.borrow = false
+	}
+}
+
+// resultBuffer returns the slice the query appends its results to
+func (qc *queryConfig) resultBuffer(c *config) []PhysicalObject {
+	if !qc.borrow {
+		return nil
+	}
+	return c.borrowed[:0]
+}
+
+// lend records the results of a borrowing query, so that the buffer is reused by the next one
+func (qc *queryConfig) lend(c *config, objects []PhysicalObject) {
+	if qc.borrow {
+		c.borrowed = objects
+	}
+}
+
+// expireBorrowed makes borrowed results unusable once the tree changed, in race detector builds
+func (c *config) expireBorrowed() {
+	if !raceEnabled || len(c.borrowed) == 0 {
+		return
+	}
+	for i := range c.borrowed {
+		c.borrowed[i] = staleObject{}
+	}
+	c.borrowed = c.borrowed[:0]
+}
+
+// staleObject replaces the objects of borrowed results once the tree changed
+type staleObject struct{}
+
+const staleMessage = "quadtree: borrowed query result used after the tree changed"
+
+func (staleObject) X() float64                { panic(staleMessage) }
+func (staleObject) Y() float64                { panic(staleMessage) }
+func (staleObject) Width() float64            { panic(staleMessage) }
+func (staleObject) Height() float64           { panic(staleMessage) }
+func (staleObject) Update(time.Duration) bool { panic(staleMessage) }
@@ -0,0 +1,7 @@
+//go:build race
+// +build race
+
+package quadtree
+
+// raceEnabled tells whether the race detector is on, borrowed query results are then checked
+const raceEnabled = true
@@ -0,0 +1,24 @@
+//go:build race
+// +build race
+
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestBorrowExpires(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 4)
+	qt.Insert(&TestPhysicalObject{1, 1, 1, 1})
+	borrowed := qt.Retrieve(&quadtree.Bounds{0, 0, 3, 3}, quadtree.Borrow())
+	qt.Insert(&TestPhysicalObject{2, 2, 1, 1})
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Borrowed results expect to panic once the tree changed")
+		}
+	}()
+	borrowed[0].X()
+}
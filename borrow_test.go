@@ -0,0 +1,29 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestBorrow(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 4)
+	one := &TestPhysicalObject{1, 1, 1, 1}
+	another := &TestPhysicalObject{5, 5, 1, 1}
+	qt.Insert(one)
+	qt.Insert(another)
+
+	first := qt.Retrieve(&quadtree.Bounds{0, 0, 3, 3}, quadtree.Borrow())
+	if len(first) != 1 || first[0] != one {
+		t.Fatalf("Retrieve expects to borrow the first object, got %v", first)
+	}
+	second := qt.QueryRing(5.5, 5.5, 0, 1, quadtree.Borrow())
+	if len(second) != 1 || second[0] != another || &first[0] != &second[0] {
+		t.Errorf("Borrowing queries expect to reuse the same buffer")
+	}
+	copied := qt.Retrieve(&quadtree.Bounds{0, 0, 3, 3}, quadtree.Copy())
+	third := qt.Retrieve(&quadtree.Bounds{4, 4, 3, 3}, quadtree.Borrow())
+	if copied[0] != one || &copied[0] == &third[0] {
+		t.Errorf("Copied results expect to stay valid")
+	}
+}
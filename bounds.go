@@ -0,0 +1,85 @@
+package quadtree
+
+import (
+	"image"
+	"math"
+	"time"
+)
+
+// HalfExtents returns the center of the bounds and how far they extend on each side of it
+func (b *Bounds) HalfExtents() (cx, cy, halfWidth, halfHeight float64) {
+	cx, cy = b.Center()
+	return cx, cy, b.Width / 2, b.Height / 2
+}
+
+// NewCentered initialize an empty quadtree covering the area centered at (cx, cy) and extending
+// halfWidth and halfHeight on each side, configured by the given options. HalfExtents returns these values
+func NewCentered(cx, cy, halfWidth, halfHeight float64,
+	maxObjectsBeforeSplit,
+	maxLevelsToSplit int,
+	opts ...Option) *Quadtree {
+
+	bounds := &Bounds{cx - halfWidth, cy - halfHeight, 2 * halfWidth, 2 * halfHeight}
+	return NewQuadtree(bounds, maxObjectsBeforeSplit, maxLevelsToSplit, opts...)
+}
+
+// Intersects checks whether the bounds overlap another, touching borders count as overlap
+func (b *Bounds) Intersects(another *Bounds) bool {
+	return b.X <= another.X+another.Width && another.X <= b.X+b.Width &&
+		b.Y <= another.Y+another.Height && another.Y <= b.Y+b.Height
+}
+
+// Union returns the smallest bounds containing both bounds
+func (b *Bounds) Union(another *Bounds) Bounds {
+	x := math.Min(b.X, another.X)
+	y := math.Min(b.Y, another.Y)
+	return Bounds{
+		x,
+		y,
+		math.Max(b.X+b.Width, another.X+another.Width) - x,
+		math.Max(b.Y+b.Height, another.Y+another.Height) - y,
+	}
+}
+
+// Expand returns the bounds grown by margin on every side, a negative margin shrinks them
+func (b *Bounds) Expand(margin float64) Bounds {
+	return Bounds{b.X - margin, b.Y - margin, b.Width + 2*margin, b.Height + 2*margin}
+}
+
+// ContainsPoint checks whether (x, y) lies within the bounds, borders included
+func (b *Bounds) ContainsPoint(x, y float64) bool {
+	return x >= b.X && x <= b.X+b.Width && y >= b.Y && y <= b.Y+b.Height
+}
+
+// Center returns the center of the bounds
+func (b *Bounds) Center() (float64, float64) {
+	return b.X + b.Width/2, b.Y + b.Height/2
+}
+
+// BoundsFromRect converts an image.Rectangle, whose Max corner is exclusive, into Bounds
+func BoundsFromRect(r image.Rectangle) *Bounds {
+	r = r.Canon()
+	return &Bounds{float64(r.Min.X), float64(r.Min.Y), float64(r.Dx()), float64(r.Dy())}
+}
+
+// RectMinMax is a rectangle given by its min and max corners. It can be inserted into the tree
+// as a motionless PhysicalObject, and converted to the Bounds taken by queries
+type RectMinMax struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// Bounds converts the rectangle into Bounds
+func (r *RectMinMax) Bounds() *Bounds {
+	return &Bounds{r.MinX, r.MinY, r.MaxX - r.MinX, r.MaxY - r.MinY}
+}
+
+// MinMax converts the bounds into a RectMinMax
+func (b *Bounds) MinMax() RectMinMax {
+	return RectMinMax{b.X, b.Y, b.X + b.Width, b.Y + b.Height}
+}
+
+func (r *RectMinMax) X() float64                { return r.MinX }
+func (r *RectMinMax) Y() float64                { return r.MinY }
+func (r *RectMinMax) Width() float64            { return r.MaxX - r.MinX }
+func (r *RectMinMax) Height() float64           { return r.MaxY - r.MinY }
+func (r *RectMinMax) Update(time.Duration) bool { return false }
@@ -0,0 +1,69 @@
+package quadtree_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestNewCentered(t *testing.T) {
+	b := quadtree.NewCentered(10, 20, 2, 3, 1, 10).Bounds
+	if *b != (quadtree.Bounds{8, 17, 4, 6}) {
+		t.Errorf("expects bounds (8, 17, 4, 6), got %v", *b)
+	}
+	if cx, cy, hw, hh := b.HalfExtents(); cx != 10 || cy != 20 || hw != 2 || hh != 3 {
+		t.Errorf("expects half extents (10, 20, 2, 3), got (%v, %v, %v, %v)", cx, cy, hw, hh)
+	}
+
+	qt := quadtree.NewCentered(0, 0, 4, 4, 1, 10)
+	if *qt.Bounds != *quadtree.OriginCenteredBounds(8, 8) {
+		t.Errorf("expects tree bounds (-4, -4, 8, 8), got %v", *qt.Bounds)
+	}
+	qt.Insert(&TestPhysicalObject{-3, -3, 1, 1})
+	qt.Insert(&TestPhysicalObject{2, 2, 1, 1})
+	if qt.Nodes[0] == nil || qt.Nodes[3] == nil {
+		t.Errorf("expects objects in the top left and bottom right quadrants around the center")
+	}
+}
+
+func TestBoundsHelpers(t *testing.T) {
+	a := &quadtree.Bounds{0, 0, 2, 2}
+	b := &quadtree.Bounds{2, 1, 3, 3}
+	if !a.Intersects(b) || !b.Intersects(a) {
+		t.Errorf("touching bounds expect to intersect")
+	}
+	if a.Intersects(&quadtree.Bounds{3, 3, 1, 1}) {
+		t.Errorf("disjoint bounds expect not to intersect")
+	}
+	if u := a.Union(b); u != (quadtree.Bounds{0, 0, 5, 4}) {
+		t.Errorf("expects union (0, 0, 5, 4), got %v", u)
+	}
+	if e := a.Expand(1); e != (quadtree.Bounds{-1, -1, 4, 4}) {
+		t.Errorf("expects expanded bounds (-1, -1, 4, 4), got %v", e)
+	}
+	if !a.ContainsPoint(2, 0) || a.ContainsPoint(2.1, 1) {
+		t.Errorf("expects points on the border to be contained and points outside not")
+	}
+	if cx, cy := b.Center(); cx != 3.5 || cy != 2.5 {
+		t.Errorf("expects center (3.5, 2.5), got (%v, %v)", cx, cy)
+	}
+}
+
+func TestRectConversions(t *testing.T) {
+	if b := quadtree.BoundsFromRect(image.Rect(4, 6, 1, 2)); *b != (quadtree.Bounds{1, 2, 3, 4}) {
+		t.Errorf("expects bounds (1, 2, 3, 4), got %v", *b)
+	}
+
+	r := &quadtree.RectMinMax{MinX: 1, MinY: 1, MaxX: 2, MaxY: 3}
+	if b := r.Bounds(); *b != (quadtree.Bounds{1, 1, 1, 2}) || b.MinMax() != *r {
+		t.Errorf("expects bounds (1, 1, 1, 2) converting back to %v, got %v", *r, *b)
+	}
+
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	qt.Insert(r)
+	found := qt.Retrieve((&quadtree.RectMinMax{MinX: 0, MinY: 0, MaxX: 1.5, MaxY: 1.5}).Bounds())
+	if len(found) != 1 || found[0] != r {
+		t.Errorf("expects the inserted rectangle to be retrieved, got %v", found)
+	}
+}
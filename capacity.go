@@ -0,0 +1,206 @@
+package quadtree
+
+import (
+	"math"
+	"sort"
+)
+
+// Evictor is called when the tree exceeds its capacity, with the root of the tree and the current
+// number of stored objects and nodes. It is expected to Remove objects until the tree fits again
+type Evictor func(tree *Quadtree, objects, nodes int)
+
+// WithCapacity bounds the tree to maxObjects stored objects and maxNodes nodes, 0 meaning no limit.
+// Objects stored in several nodes count once per copy. After every Insert, Update or rebuild leaving
+// the tree over capacity, evict is called. Removing objects only frees nodes with WithEagerCollapse,
+// EvictFarthest collapses the nodes it empties
+func WithCapacity(maxObjects, maxNodes int, evict Evictor) Option {
+	return func(c *config) {
+		c.maxObjects = maxObjects
+		c.maxNodes = maxNodes
+		c.evict = evict
+	}
+}
+
+// Size returns the number of objects stored in the tree, copies included, and its number of nodes
+func (qt *Quadtree) Size() (objects, nodes int) {
+	if !qt.ready() {
+		return 0, 0
+	}
+	root := qt.root()
+	return root.m_objectCount, root.m_nodeCount + 1
+}
+
+// overCapacity tells whether the tree holds more objects or nodes than allowed
+func (qt *Quadtree) overCapacity() bool {
+	c := qt.m_config
+	objects, nodes := qt.Size()
+	return (c.maxObjects > 0 && objects > c.maxObjects) || (c.maxNodes > 0 && nodes > c.maxNodes)
+}
+
+// enforceCapacity calls the evictor if the tree is over capacity, unless it is already running
+func (qt *Quadtree) enforceCapacity() {
+	root := qt.root()
+	if root.m_config.evict == nil || root.m_evicting || !root.overCapacity() {
+		return
+	}
+	root.m_evicting = true
+	defer func() {
+		root.m_evicting = false
+	}()
+	objects, nodes := root.Size()
+	root.m_config.evict(root, objects, nodes)
+}
+
+// EvictFarthest returns an Evictor removing the objects farthest from (x, y), measured to their center,
+// until the tree fits its capacity. Nodes emptied by the eviction are collapsed, so that a tree over its
+// node capacity shrinks as well, and empty leaves are collapsed before any object is removed
+func EvictFarthest(x, y float64) Evictor {
+	return func(tree *Quadtree, objects, nodes int) {
+		if tree.m_config.maxNodes > 0 {
+			tree.pruneEmpty()
+		}
+		var candidates []PhysicalObject
+		var holders []*Quadtree
+		tree.eachNode(func(node *Quadtree) {
+			for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+				if obj := ele.Value.(PhysicalObject); !node.buried(obj) {
+					candidates = append(candidates, obj)
+					holders = append(holders, node)
+				}
+			}
+		})
+		distances := make([]float64, len(candidates))
+		for i, obj := range candidates {
+			cx, cy := center(obj)
+			distances[i] = math.Hypot(cx-x, cy-y)
+		}
+		sort.Stable(sort.Reverse(byDistance{distances, candidates, holders}))
+		for i, obj := range candidates {
+			if !tree.overCapacity() {
+				return
+			}
+			if tree.m_config.straddlePolicy == StraddleDuplicate {
+				// copies of the object live in other nodes as well
+				tree.Remove(obj)
+				continue
+			}
+			holders[i].evict(obj)
+		}
+	}
+}
+
+// eachNode calls fn with current node and every node of its subtrees, parents first
+func (qt *Quadtree) eachNode(fn func(node *Quadtree)) {
+	fn(qt)
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			qt.Nodes[index].eachNode(fn)
+		}
+		flags >>= 1
+		index += 1
+	}
+}
+
+// evict removes obj from current node, where it is stored, then collapses the emptied nodes up the tree
+func (qt *Quadtree) evict(obj PhysicalObject) {
+	root := qt.root()
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if ele.Value.(PhysicalObject) == obj {
+			qt.m_Objects.Remove(ele)
+			qt.touch(-1)
+			break
+		}
+	}
+	qt.forget(obj)
+	qt.collapseUp()
+	root.notifyRemoved(obj, RemovedEvicted)
+}
+
+// collapseUp drops current node if it holds no objects and no children, then its emptied ancestors
+func (qt *Quadtree) collapseUp() {
+	for node := qt; node.m_parent != nil && node.m_Objects.Len() == 0 && node.m_ActiveNodes == 0; {
+		parent := node.m_parent
+		for index, child := range parent.Nodes {
+			if child == node {
+				parent.dropChild(index)
+			}
+		}
+		node = parent
+	}
+}
+
+// pruneEmpty collapses the nodes of the subtree holding no objects and no children
+func (qt *Quadtree) pruneEmpty() {
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			qt.Nodes[index].pruneEmpty()
+			qt.collapseChild(index)
+		}
+		flags >>= 1
+		index += 1
+	}
+}
+
+// byDistance sorts objects, and the nodes holding them, by their distances
+type byDistance struct {
+	distances []float64
+	objects   []PhysicalObject
+	holders   []*Quadtree
+}
+
+func (s byDistance) Len() int           { return len(s.distances) }
+func (s byDistance) Less(i, j int) bool { return s.distances[i] < s.distances[j] }
+func (s byDistance) Swap(i, j int) {
+	s.distances[i], s.distances[j] = s.distances[j], s.distances[i]
+	s.objects[i], s.objects[j] = s.objects[j], s.objects[i]
+	s.holders[i], s.holders[j] = s.holders[j], s.holders[i]
+}
+
+// clear removes every object and child node of current node
+func (qt *Quadtree) clear() {
+	objects, nodes := qt.subtreeSize()
+	root := qt.root()
+	root.m_objectCount -= objects
+	root.m_nodeCount -= nodes - 1
+	for index, child := range qt.Nodes {
+		if qt.m_ActiveNodes&(1<<uint(index)) != 0 {
+			child.free()
+		}
+	}
+	qt.m_ActiveNodes = 0
+	qt.Nodes = [4]*Quadtree{}
+	qt.m_splitSet = false
+	qt.m_Objects = qt.m_config.newObjectList()
+}
+
+// dropChild removes the child node at index along with its subtrees
+func (qt *Quadtree) dropChild(index int) {
+	objects, nodes := qt.Nodes[index].subtreeSize()
+	root := qt.root()
+	root.m_objectCount -= objects
+	root.m_nodeCount -= nodes
+	qt.Nodes[index].free()
+	qt.Nodes[index] = nil
+	qt.m_ActiveNodes &^= 1 << uint(index)
+}
+
+// subtreeSize returns the number of objects stored in current node and its subtrees, and the number of nodes
+func (qt *Quadtree) subtreeSize() (objects, nodes int) {
+	objects, nodes = qt.m_Objects.Len(), 1
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			o, n := qt.Nodes[index].subtreeSize()
+			objects += o
+			nodes += n
+		}
+		flags >>= 1
+		index += 1
+	}
+	return objects, nodes
+}
@@ -0,0 +1,76 @@
+package quadtree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/gen"
+)
+
+func countNodes(qt *quadtree.Quadtree) int {
+	nodes := 1
+	for _, node := range qt.Nodes {
+		if node != nil {
+			nodes += countNodes(node)
+		}
+	}
+	return nodes
+}
+
+func TestSize(t *testing.T) {
+	bounds := quadtree.Bounds{0, 0, 100, 100}
+	objects := gen.Scene(3, 200, gen.WithBounds(bounds), gen.WithMovingRatio(0.5))
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 100, 100}, 4, 6, quadtree.WithLifespan(1, 0))
+	qt.UpdateTree(listOf(objects[:100]...))
+	for _, obj := range objects[100:] {
+		qt.Insert(obj)
+	}
+	for i := 0; i < 20; i += 1 {
+		qt.Update(100 * time.Millisecond)
+		if i%5 == 0 {
+			qt.Remove(objects[i])
+		}
+
+		walked := 0
+		qt.Walk(func(quadtree.PhysicalObject) { walked += 1 })
+		if objects, nodes := qt.Size(); objects != walked || nodes != countNodes(qt) {
+			t.Fatalf("update %d: Size expects %d objects and %d nodes, got %d and %d", i, walked, countNodes(qt), objects, nodes)
+		}
+	}
+}
+
+func TestEvictFarthest(t *testing.T) {
+	near := &TestPhysicalObject{0, 0, 1, 1}
+	far := &TestPhysicalObject{7, 7, 1, 1}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithCapacity(2, 0, quadtree.EvictFarthest(0, 0)))
+	qt.Insert(near)
+	qt.Insert(far)
+	qt.Insert(&TestPhysicalObject{2, 2, 1, 1})
+
+	if objects, _ := qt.Size(); objects != 2 {
+		t.Errorf("expects the tree to be evicted down to 2 objects, got %d", objects)
+	}
+	if qt.FindObject(far) != nil || qt.FindObject(near) == nil {
+		t.Errorf("expects the object farthest from the focus to be evicted")
+	}
+}
+
+func TestEvictFarthestNodes(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 16, 16}, 1, 4, quadtree.WithCapacity(0, 3, quadtree.EvictFarthest(0, 0)))
+	for i := 0; i < 10; i += 1 {
+		qt.Insert(&TestPhysicalObject{float64(i) * 1.5, float64(i) * 1.5, 1, 1})
+	}
+	objects, nodes := qt.Size()
+	if nodes > 3 {
+		t.Errorf("expects the tree to shrink to 3 nodes, got %d", nodes)
+	}
+	if objects == 0 {
+		t.Errorf("expects the eviction to stop once the tree fits, got no objects left")
+	}
+	count := 0
+	qt.Walk(func(quadtree.PhysicalObject) { count += 1 })
+	if count != objects {
+		t.Errorf("expects Size to count %d objects, got %d", count, objects)
+	}
+}
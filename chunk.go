@@ -0,0 +1,157 @@
+package quadtree
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// ErrChunkAttached is returned by AttachChunk when a chunk is already attached under the key
+var ErrChunkAttached = errors.New("quadtree: chunk already attached")
+
+// Streamer tells which cells of a grid of nodes at a fixed level should be loaded around a moving
+// focus point. Cells are addressed by NodeKey, assuming nodes split at their midpoint
+type Streamer struct {
+	Root       Bounds  // bounds of the root of the tree
+	Level      int     // level of the streamed cells
+	Hysteresis float64 // extra distance a loaded cell must be beyond the radius before it is unloaded
+	loaded     map[NodeKey]bool
+}
+
+// NewStreamer returns a streamer of the cells at level of a tree with the given root bounds
+func NewStreamer(root Bounds, level int, hysteresis float64) *Streamer {
+	return &Streamer{Root: root, Level: level, Hysteresis: hysteresis, loaded: make(map[NodeKey]bool)}
+}
+
+// Update moves the focus to (x, y) and returns the cells to load, which are within radius of the
+// focus and not loaded yet, and the cells to unload, which are loaded and farther than radius plus
+// the hysteresis. Both lists are sorted, and the streamer considers them done
+func (s *Streamer) Update(x, y, radius float64) (load, unload []NodeKey) {
+	if s.loaded == nil {
+		s.loaded = make(map[NodeKey]bool)
+	}
+	for _, key := range s.cellsWithin(x, y, radius) {
+		if !s.loaded[key] {
+			s.loaded[key] = true
+			load = append(load, key)
+		}
+	}
+	keep := make(map[NodeKey]bool)
+	for _, key := range s.cellsWithin(x, y, radius+s.Hysteresis) {
+		keep[key] = true
+	}
+	for key := range s.loaded {
+		if !keep[key] {
+			delete(s.loaded, key)
+			unload = append(unload, key)
+		}
+	}
+	sort.Sort(byKey(unload))
+	return load, unload
+}
+
+// Loaded returns the loaded cells, sorted
+func (s *Streamer) Loaded() []NodeKey {
+	keys := make([]NodeKey, 0, len(s.loaded))
+	for key := range s.loaded {
+		keys = append(keys, key)
+	}
+	sort.Sort(byKey(keys))
+	return keys
+}
+
+// cellsWithin returns the cells of the grid within radius of (x, y), sorted
+func (s *Streamer) cellsWithin(x, y, radius float64) []NodeKey {
+	cells := 1 << uint(s.Level)
+	cellWidth, cellHeight := s.Root.Width/float64(cells), s.Root.Height/float64(cells)
+	clamp := func(v float64) int {
+		return int(math.Max(0, math.Min(float64(cells-1), math.Floor(v))))
+	}
+	minCol, maxCol := clamp((x-radius-s.Root.X)/cellWidth), clamp((x+radius-s.Root.X)/cellWidth)
+	minRow, maxRow := clamp((y-radius-s.Root.Y)/cellHeight), clamp((y+radius-s.Root.Y)/cellHeight)
+
+	var keys []NodeKey
+	for row := minRow; row <= maxRow; row += 1 {
+		for col := minCol; col <= maxCol; col += 1 {
+			key := cellKey(col, row, s.Level)
+			b := key.Bounds(&s.Root)
+			if pointBoundsDistance(x, y, &b) <= radius {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Sort(byKey(keys))
+	return keys
+}
+
+// byKey orders node keys
+type byKey []NodeKey
+
+func (s byKey) Len() int           { return len(s) }
+func (s byKey) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byKey) Less(i, j int) bool { return s[i] < s[j] }
+
+// cellKey returns the key of the cell at col and row of the grid of nodes at level
+func cellKey(col, row, level int) NodeKey {
+	path := make([]byte, level)
+	for i := 0; i < level; i += 1 {
+		bit := uint(level - 1 - i)
+		path[i] = '0' + byte((row>>bit)&1)*2 + byte((col>>bit)&1)
+	}
+	return NodeKey(path)
+}
+
+// AttachChunk inserts the objects of a chunk, remembered under key for DetachChunk.
+// It returns the first error Insert returned, the other objects are still inserted
+func (qt *Quadtree) AttachChunk(key NodeKey, objects []PhysicalObject) error {
+	if !qt.ready() {
+		return ErrNilQuadtree
+	}
+	c := qt.m_config
+	if c.chunks == nil {
+		c.chunks = make(map[NodeKey][]PhysicalObject)
+	}
+	if _, ok := c.chunks[key]; ok {
+		return ErrChunkAttached
+	}
+	var err error
+	attached := make([]PhysicalObject, 0, len(objects))
+	for _, obj := range objects {
+		if e := qt.root().Insert(obj); e != nil {
+			if err == nil {
+				err = e
+			}
+			continue
+		}
+		attached = append(attached, obj)
+	}
+	c.chunks[key] = attached
+	return err
+}
+
+// DetachChunk removes the objects of the chunk attached under key which are still in the tree,
+// and returns them. The node addressed by key is searched first
+func (qt *Quadtree) DetachChunk(key NodeKey) []PhysicalObject {
+	if !qt.ready() {
+		return nil
+	}
+	c := qt.m_config
+	objects, ok := c.chunks[key]
+	if !ok {
+		return nil
+	}
+	delete(c.chunks, key)
+
+	root := qt.root()
+	var detached []PhysicalObject
+	for _, obj := range objects {
+		removed := false
+		if node := root.NodeAt(key); node != nil {
+			removed = node.Remove(obj)
+		}
+		if removed || root.Remove(obj) {
+			detached = append(detached, obj)
+		}
+	}
+	return detached
+}
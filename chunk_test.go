@@ -0,0 +1,62 @@
+package quadtree_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestStreamer(t *testing.T) {
+	s := quadtree.NewStreamer(quadtree.Bounds{0, 0, 100, 100}, 2, 10)
+
+	load, unload := s.Update(10, 10, 5)
+	if !reflect.DeepEqual(load, []quadtree.NodeKey{"00"}) || len(unload) != 0 {
+		t.Errorf("expects the top left cell loaded, got %v and %v", load, unload)
+	}
+	load, unload = s.Update(30, 10, 10)
+	if !reflect.DeepEqual(load, []quadtree.NodeKey{"01"}) || len(unload) != 0 {
+		t.Errorf("expects the cell to the right loaded, got %v and %v", load, unload)
+	}
+	// the top left cell is 10 away from the focus, within the hysteresis
+	load, unload = s.Update(35, 10, 5)
+	if len(load) != 0 || len(unload) != 0 {
+		t.Errorf("expects no change within the hysteresis, got %v and %v", load, unload)
+	}
+	load, unload = s.Update(45, 10, 1)
+	if len(load) != 0 || !reflect.DeepEqual(unload, []quadtree.NodeKey{"00"}) {
+		t.Errorf("expects the top left cell unloaded, got %v and %v", load, unload)
+	}
+	if loaded := s.Loaded(); !reflect.DeepEqual(loaded, []quadtree.NodeKey{"01"}) {
+		t.Errorf("expects one loaded cell, got %v", loaded)
+	}
+}
+
+func TestAttachChunk(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 100, 100}, 1, 4)
+	chunk := []quadtree.PhysicalObject{&TestPhysicalObject{5, 5, 2, 2}, &TestPhysicalObject{15, 15, 2, 2}}
+	other := &TestPhysicalObject{80, 80, 2, 2}
+	qt.Insert(other)
+
+	if err := qt.AttachChunk("00", chunk); err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.AttachChunk("00", chunk); err != quadtree.ErrChunkAttached {
+		t.Errorf("expects ErrChunkAttached attaching a chunk twice, got %v", err)
+	}
+	if objects, _ := qt.Size(); objects != 3 {
+		t.Errorf("expects 3 objects, got %d", objects)
+	}
+
+	qt.Remove(chunk[1])
+	detached := qt.DetachChunk("00")
+	if len(detached) != 1 || detached[0] != chunk[0] {
+		t.Errorf("expects the object of the chunk still in the tree detached, got %v", detached)
+	}
+	if qt.FindObject(other) == nil || qt.FindObject(chunk[0]) != nil {
+		t.Errorf("expects only the chunk detached:\n%s", qt.String())
+	}
+	if detached := qt.DetachChunk("00"); detached != nil {
+		t.Errorf("expects nothing to detach twice, got %v", detached)
+	}
+}
@@ -0,0 +1,67 @@
+// quadtree-compress compresses an image into a region quadtree, merging areas whose colors differ by no
+// more than a tolerance, and writes the image painted back from the tree. Running it with increasing
+// tolerances gives versions of a sprite, such as a hit-mask, at decreasing resolutions.
+//
+// Usage:
+//
+//	quadtree-compress [-tolerance T] [-out FILE] [FILE]
+//
+// The image (PNG, GIF or JPEG) is read from FILE, or from standard input
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+
+	"github.com/gmlewis/quadtree/regiontree"
+)
+
+func main() {
+	tolerance := flag.Float64("tolerance", 0.05, "largest difference of the channels, in [0, 1], of merged pixels")
+	out := flag.String("out", "", "PNG file to write the compressed image to, nothing is written if empty")
+	flag.Parse()
+
+	var in io.Reader = os.Stdin
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+	if err := compress(in, os.Stdout, *tolerance, *out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// compress reads an image from in, prints the size of its tree to w and writes it back to the file out
+func compress(in io.Reader, w io.Writer, tolerance float64, out string) error {
+	img, _, err := image.Decode(in)
+	if err != nil {
+		return err
+	}
+	tree := regiontree.FromImage(img, tolerance)
+	pixels := img.Bounds().Dx() * img.Bounds().Dy()
+	fmt.Fprintf(w, "%d leaves for %d pixels (%.1f%%)\n", tree.Len(), pixels, 100*float64(tree.Len())/float64(pixels))
+	if out == "" {
+		return nil
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(f, tree.ToImage()); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompress(t *testing.T) {
+	// left half red, right half blue, with a slightly different red pixel
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 0; y < 8; y += 1 {
+		for x := 0; x < 8; x += 1 {
+			c := color.NRGBA{0xff, 0, 0, 0xff}
+			if x >= 4 {
+				c = color.NRGBA{0, 0, 0xff, 0xff}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	img.SetNRGBA(1, 1, color.NRGBA{0xf8, 0, 0, 0xff})
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, img); err != nil {
+		t.Fatal(err)
+	}
+
+	dir, err := ioutil.TempDir("", "quadtree-compress")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "out.png")
+
+	var printed bytes.Buffer
+	if err := compress(bytes.NewReader(encoded.Bytes()), &printed, 0.05, path); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "4 leaves for 64 pixels (6.2%)\n"; printed.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, printed.String())
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	written, err := png.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written.Bounds() != img.Bounds() {
+		t.Fatalf("Expected an image of bounds %v, got %v", img.Bounds(), written.Bounds())
+	}
+	for y := 0; y < 8; y += 1 {
+		for x := 0; x < 8; x += 1 {
+			got := color.NRGBAModel.Convert(written.At(x, y)).(color.NRGBA)
+			if x < 4 && (got.R < 0xf8 || got.B != 0) || x >= 4 && got != (color.NRGBA{0, 0, 0xff, 0xff}) {
+				t.Errorf("Expected pixel (%d, %d) to keep its color, got %v", x, y, got)
+			}
+		}
+	}
+
+	printed.Reset()
+	if err := compress(bytes.NewReader(encoded.Bytes()), &printed, 0, ""); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(printed.String(), "10 leaves") {
+		t.Errorf("Expected the different pixel to split its quadrant without tolerance, got %q", printed.String())
+	}
+
+	if err := compress(strings.NewReader("not an image"), &printed, 0, ""); err == nil {
+		t.Error("Expected an error compressing something which is not an image")
+	}
+}
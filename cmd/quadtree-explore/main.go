@@ -0,0 +1,285 @@
+// quadtree-explore is an interactive terminal explorer for quadtrees, built from a snapshot written by
+// Quadtree.Save, a scene file (see gen.WriteScene) or a generated scene.
+//
+// Usage:
+//
+//	quadtree-explore [-snapshot FILE | -scene FILE | -seed N -n COUNT] [-max-objects N] [-max-levels N] [-lines]
+//
+// On a terminal the explorer takes the whole screen: arrows or hjkl pan, + and - zoom, 0 shows the
+// whole tree, space steps an Update, clicking a cell lists the objects overlapping it and q quits.
+// Objects of snapshots are JSON objects with x, y, width and height fields, as written by the save
+// command. With -lines, when standard input is not a terminal, or on systems other than Unix, where
+// stty cannot put the terminal in raw mode, commands are read one per line instead, type "help" to
+// list them
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/gen"
+)
+
+const help = `commands:
+  show                 draw the current view
+  pan DX DY            move the view by DX, DY columns and rows
+  zoom FACTOR          zoom in (FACTOR > 1) or out (FACTOR < 1) around the center of the view
+  reset                show the whole tree
+  cell COL ROW         list the objects overlapping a cell of the view
+  step [N] [DELTA]     call Update N times (default 1) with DELTA (default 16ms)
+  tree                 print the canonical dump of the tree
+  save FILE            write a snapshot of the tree, which -snapshot loads
+  help                 print this help
+  quit                 exit
+`
+
+type explorer struct {
+	qt         *quadtree.Quadtree
+	view       quadtree.Bounds
+	cols, rows int
+	out        io.Writer
+	selected   *quadtree.Bounds // cell whose objects are listed, nil for none
+	listing    []string         // objects of the selected cell
+}
+
+func main() {
+	snapshotFile := flag.String("snapshot", "", "snapshot to load, as written by Quadtree.Save or the save command")
+	sceneFile := flag.String("scene", "", "scene file to load, as written by gen.WriteScene")
+	seed := flag.Int64("seed", 1, "seed of the generated scene when no file is given")
+	count := flag.Int("n", 200, "number of objects of the generated scene")
+	maxObjects := flag.Int("max-objects", 8, "maximum objects a node can hold before splitting")
+	maxLevels := flag.Int("max-levels", 6, "maximum number of times the tree can split")
+	cols := flag.Int("cols", 80, "width of the view in characters")
+	rows := flag.Int("rows", 32, "height of the view in characters")
+	lines := flag.Bool("lines", false, "read commands line by line instead of running the terminal UI")
+	flag.Parse()
+
+	qt, err := loadTree(*snapshotFile, *sceneFile, *seed, *count, *maxObjects, *maxLevels)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	e := &explorer{qt: qt, view: *qt.Bounds, cols: *cols, rows: *rows, out: os.Stdout}
+	if !*lines && isTerminal(os.Stdin) {
+		if err := e.runScreen(os.Stdin, os.Stdout); err == nil {
+			return
+		}
+		// without a raw terminal, fall back to commands
+	}
+	e.run(os.Stdin)
+}
+
+// loadTree builds the tree from a snapshot, a scene file or a generated scene, in that order of preference
+func loadTree(snapshotFile, sceneFile string, seed int64, count, maxObjects, maxLevels int) (*quadtree.Quadtree, error) {
+	if snapshotFile != "" {
+		f, err := os.Open(snapshotFile)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return quadtree.Load(f, decodeBox)
+	}
+
+	bounds := quadtree.Bounds{X: 0, Y: 0, Width: 1000, Height: 1000}
+	var objects []quadtree.PhysicalObject
+	if sceneFile != "" {
+		f, err := os.Open(sceneFile)
+		if err != nil {
+			return nil, err
+		}
+		bounds, objects, err = gen.ReadScene(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		objects = gen.Scene(seed, count, gen.WithBounds(bounds))
+	}
+	qt := quadtree.CreateQuadtree(&bounds, maxObjects, maxLevels)
+	for _, obj := range objects {
+		qt.Insert(obj)
+	}
+	return qt, nil
+}
+
+// box is the encoding of the objects of the snapshots the explorer reads and writes
+type box struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func decodeBox(data json.RawMessage) (quadtree.PhysicalObject, error) {
+	var b box
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, err
+	}
+	return gen.NewObject(b.X, b.Y, b.Width, b.Height), nil
+}
+
+func encodeBox(obj quadtree.PhysicalObject) (json.RawMessage, error) {
+	return json.Marshal(box{obj.X(), obj.Y(), obj.Width(), obj.Height()})
+}
+
+func (e *explorer) run(in io.Reader) {
+	e.show()
+	scanner := bufio.NewScanner(in)
+	fmt.Fprint(e.out, "> ")
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 0 {
+			if fields[0] == "quit" || fields[0] == "exit" {
+				return
+			}
+			if err := e.execute(fields[0], fields[1:]); err != nil {
+				fmt.Fprintln(e.out, "error:", err)
+			}
+		}
+		fmt.Fprint(e.out, "> ")
+	}
+}
+
+func parseFloats(args []string, defaults ...float64) ([]float64, error) {
+	values := append([]float64(nil), defaults...)
+	for i, arg := range args {
+		if i >= len(values) {
+			return nil, fmt.Errorf("too many arguments")
+		}
+		v, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+func (e *explorer) execute(command string, args []string) error {
+	switch command {
+	case "show":
+		e.show()
+	case "pan":
+		v, err := parseFloats(args, 0, 0)
+		if err != nil {
+			return err
+		}
+		e.pan(v[0], v[1])
+		e.show()
+	case "zoom":
+		v, err := parseFloats(args, 2)
+		if err != nil {
+			return err
+		}
+		if v[0] <= 0 {
+			return fmt.Errorf("zoom factor must be positive")
+		}
+		e.zoom(v[0])
+		e.show()
+	case "reset":
+		e.view = *e.qt.Bounds
+		e.show()
+	case "cell":
+		v, err := parseFloats(args, 0, 0)
+		if err != nil {
+			return err
+		}
+		e.selectCell(v[0], v[1])
+		for _, line := range e.listing {
+			fmt.Fprintln(e.out, line)
+		}
+	case "step":
+		v, err := parseFloats(args, 1, 16)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(e.out, e.step(int(v[0]), time.Duration(v[1]*float64(time.Millisecond))))
+		e.show()
+	case "tree":
+		fmt.Fprint(e.out, e.qt.String())
+	case "save":
+		if len(args) != 1 {
+			return fmt.Errorf("save expects a file name")
+		}
+		return e.save(args[0])
+	case "help":
+		fmt.Fprint(e.out, help)
+	default:
+		return fmt.Errorf("unknown command %q, type help to list commands", command)
+	}
+	return nil
+}
+
+// pan moves the view by dx, dy columns and rows
+func (e *explorer) pan(dx, dy float64) {
+	e.view.X += dx * e.view.Width / float64(e.cols-1)
+	e.view.Y += dy * e.view.Height / float64(e.rows-1)
+}
+
+// zoom scales the view by 1/factor around its center
+func (e *explorer) zoom(factor float64) {
+	cx, cy := e.view.X+e.view.Width/2, e.view.Y+e.view.Height/2
+	e.view.Width /= factor
+	e.view.Height /= factor
+	e.view.X, e.view.Y = cx-e.view.Width/2, cy-e.view.Height/2
+}
+
+// step calls Update n times and describes how long it took
+func (e *explorer) step(n int, delta time.Duration) string {
+	start := time.Now()
+	for i := 0; i < n; i += 1 {
+		e.qt.Update(delta)
+	}
+	return fmt.Sprintf("%d updates in %v", n, time.Since(start))
+}
+
+// save writes a snapshot of the tree to path
+func (e *explorer) save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := e.qt.Save(f, encodeBox); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func (e *explorer) show() {
+	fmt.Fprintf(e.out, "view (%g, %g, %g, %g)\n", e.view.X, e.view.Y, e.view.Width, e.view.Height)
+	e.qt.RenderASCIIView(e.out, &e.view, e.cols, e.rows)
+}
+
+// selectCell selects the area covered by a cell of the view, and lists the objects overlapping it
+func (e *explorer) selectCell(col, row float64) {
+	cellWidth := e.view.Width / float64(e.cols-1)
+	cellHeight := e.view.Height / float64(e.rows-1)
+	cell := quadtree.Bounds{
+		X:      e.view.X + (col-0.5)*cellWidth,
+		Y:      e.view.Y + (row-0.5)*cellHeight,
+		Width:  cellWidth,
+		Height: cellHeight,
+	}
+	e.selected = &cell
+	e.listing = e.listing[:0]
+	found := e.qt.Retrieve(&cell)
+	for _, obj := range found {
+		level := -1
+		if node := e.qt.FindObject(obj); node != nil {
+			level = node.Level
+		}
+		e.listing = append(e.listing, fmt.Sprintf("%T (%g, %g, %g, %g) in node at level %d",
+			obj, obj.X(), obj.Y(), obj.Width(), obj.Height(), level))
+	}
+	e.listing = append(e.listing, fmt.Sprintf("%d objects in cell (%g, %g, %g, %g)", len(found), cell.X, cell.Y, cell.Width, cell.Height))
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/gen"
+)
+
+func newExplorer(objects ...quadtree.PhysicalObject) (*explorer, *bytes.Buffer) {
+	bounds := quadtree.Bounds{X: 0, Y: 0, Width: 100, Height: 100}
+	qt := quadtree.CreateQuadtree(&bounds, 2, 4)
+	for _, obj := range objects {
+		qt.Insert(obj)
+	}
+	var out bytes.Buffer
+	return &explorer{qt: qt, view: bounds, cols: 11, rows: 11, out: &out}, &out
+}
+
+func TestDecodeInput(t *testing.T) {
+	events, rest := decodeInput([]byte("q\x1b[A\x1b[D\x1b[<0;3;4M\x1b[<0;3;4m\x1b[<64;1;1M\x1b[<0;1"))
+	expected := []event{
+		{key: "q"},
+		{key: "up"},
+		{key: "left"},
+		{click: true, col: 2, row: 3},
+		{wheel: -1},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("Expected events %+v, got %+v", expected, events)
+	}
+	if string(rest) != "\x1b[<0;1" {
+		t.Errorf("Expected the incomplete mouse report to be kept, got %q", rest)
+	}
+
+	events, rest = decodeInput(append(rest, []byte(";2M")...))
+	if len(events) != 1 || !events[0].click || events[0].col != 0 || events[0].row != 1 || len(rest) != 0 {
+		t.Errorf("Expected the completed report to be a click at 0, 1, got %+v, rest %q", events, rest)
+	}
+}
+
+func TestClickSelectsCell(t *testing.T) {
+	inside := gen.NewObject(48, 48, 4, 4)
+	e, _ := newExplorer(inside, gen.NewObject(5, 5, 2, 2), gen.NewObject(90, 90, 2, 2))
+
+	// the view starts on the second screen row, below the header
+	if _, quit := e.handle(event{click: true, col: 5, row: 6}); quit {
+		t.Fatal("Expected a click not to quit")
+	}
+	if e.selected == nil {
+		t.Fatal("Expected the click to select a cell")
+	}
+	if len(e.listing) != 2 || !strings.HasPrefix(e.listing[1], "1 objects") {
+		t.Errorf("Expected the cell at the center to list one object, got %q", e.listing)
+	}
+	if screen := e.screen(""); !strings.Contains(screen, "1 objects") || strings.Contains(strings.Replace(screen, "\r\n", "", -1), "\n") {
+		t.Errorf("Expected the screen to list the cell with raw line endings, got %q", screen)
+	}
+
+	e.handle(event{click: true, col: 50, row: 50})
+	if len(e.listing) != 2 {
+		t.Errorf("Expected a click outside the view to keep the selection, got %q", e.listing)
+	}
+	if _, quit := e.handle(event{key: "q"}); !quit {
+		t.Error("Expected q to quit")
+	}
+}
+
+func TestKeysMoveView(t *testing.T) {
+	e, _ := newExplorer()
+	e.handle(event{key: "+"})
+	if e.view != (quadtree.Bounds{X: 25, Y: 25, Width: 50, Height: 50}) {
+		t.Errorf("Expected + to zoom in around the center, got %+v", e.view)
+	}
+	e.handle(event{key: "right"})
+	if e.view.X <= 25 || e.view.Y != 25 {
+		t.Errorf("Expected right to pan right, got %+v", e.view)
+	}
+	e.handle(event{key: "0"})
+	if e.view != *e.qt.Bounds {
+		t.Errorf("Expected 0 to show the whole tree, got %+v", e.view)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	e, out := newExplorer(gen.NewObject(48, 48, 4, 4))
+	e.run(strings.NewReader("zoom 2\ncell 5 5\nbogus\nquit\nshow\n"))
+	got := out.String()
+	if !strings.Contains(got, "view (25, 25, 50, 50)") {
+		t.Errorf("Expected zoom to show the zoomed view, got %q", got)
+	}
+	if !strings.Contains(got, "1 objects in cell") {
+		t.Errorf("Expected cell to list the object, got %q", got)
+	}
+	if !strings.Contains(got, `unknown command "bogus"`) {
+		t.Errorf("Expected an error for an unknown command, got %q", got)
+	}
+	if strings.Count(got, "view (") != 2 {
+		t.Errorf("Expected commands after quit to be ignored, got %q", got)
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "quadtree-explore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tree.json")
+
+	e, out := newExplorer(gen.NewObject(10, 10, 5, 5), gen.NewObject(60, 70, 8, 3), gen.NewObject(80, 20, 1, 1))
+	e.run(strings.NewReader("save " + path + "\n"))
+	if strings.Contains(out.String(), "error") {
+		t.Fatalf("Expected save to succeed, got %q", out.String())
+	}
+
+	qt, err := loadTree(path, "", 0, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qt.String() != e.qt.String() {
+		t.Errorf("Expected the loaded tree\n%v\nto match the saved tree\n%v", qt, e.qt)
+	}
+}
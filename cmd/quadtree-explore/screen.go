@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+const (
+	enterScreen = "\x1b[?1049h\x1b[?25l\x1b[?1000h\x1b[?1006h" // alternate screen, hidden cursor, SGR mouse reports
+	leaveScreen = "\x1b[?1006l\x1b[?1000l\x1b[?25h\x1b[?1049l"
+	keysHint    = "arrows/hjkl pan  +/- zoom  0 whole tree  space step  click a cell  q quit"
+	listedLines = 8 // objects of the selected cell shown below the view
+)
+
+// event is a key press or a mouse click read from the terminal
+type event struct {
+	key      string // name of the key, empty for mouse events
+	click    bool   // left button press
+	wheel    int    // -1 for the wheel rolled up, 1 for down
+	col, row int    // 0-based screen position of mouse events
+}
+
+// isTerminal tells whether f is a character device, such as an interactive terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// handle applies an event to the explorer, and returns the status line to show and whether to quit
+func (e *explorer) handle(ev event) (string, bool) {
+	panCols, panRows := float64(e.cols/8+1), float64(e.rows/8+1)
+	switch {
+	case ev.click:
+		col, row := ev.col, ev.row-1 // the view starts below the header line
+		if col < 0 || col >= e.cols || row < 0 || row >= e.rows {
+			return "", false
+		}
+		e.selectCell(float64(col), float64(row))
+	case ev.wheel < 0:
+		e.zoom(2)
+	case ev.wheel > 0:
+		e.zoom(0.5)
+	case ev.key == "q" || ev.key == "\x03":
+		return "", true
+	case ev.key == "left" || ev.key == "h":
+		e.pan(-panCols, 0)
+	case ev.key == "right" || ev.key == "l":
+		e.pan(panCols, 0)
+	case ev.key == "up" || ev.key == "k":
+		e.pan(0, -panRows)
+	case ev.key == "down" || ev.key == "j":
+		e.pan(0, panRows)
+	case ev.key == "+" || ev.key == "=":
+		e.zoom(2)
+	case ev.key == "-":
+		e.zoom(0.5)
+	case ev.key == "0":
+		e.view = *e.qt.Bounds
+	case ev.key == " " || ev.key == "s":
+		return e.step(1, 16*time.Millisecond), false
+	}
+	return "", false
+}
+
+// screen renders the header, the view with the selected cell highlighted, the objects of the cell and
+// the status line, with the line endings of a raw terminal
+func (e *explorer) screen(status string) string {
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H\x1b[2J")
+	fmt.Fprintf(&buf, "view (%g, %g, %g, %g)  %s\n", e.view.X, e.view.Y, e.view.Width, e.view.Height, keysHint)
+	var opts []quadtree.RenderOption
+	if e.selected != nil {
+		opts = append(opts, quadtree.HighlightQuery(e.selected, quadtree.ColorYellow))
+	}
+	e.qt.RenderASCIIView(&buf, &e.view, e.cols, e.rows, opts...)
+	listing := e.listing
+	if len(listing) > listedLines {
+		// keep the summary line
+		listing = append(listing[:listedLines-1:listedLines-1], "...", listing[len(listing)-1])
+	}
+	for _, line := range listing {
+		buf.WriteString(line + "\n")
+	}
+	buf.WriteString(status + "\n")
+	return strings.Replace(buf.String(), "\n", "\r\n", -1)
+}
+
+// decodeInput splits the bytes read from a raw terminal into events, and returns the bytes of an
+// incomplete escape sequence to be completed by the next read
+func decodeInput(data []byte) ([]event, []byte) {
+	var events []event
+	for len(data) > 0 {
+		if data[0] != 0x1b {
+			events = append(events, event{key: string(data[:1])})
+			data = data[1:]
+			continue
+		}
+		if len(data) < 3 {
+			return events, data
+		}
+		if data[1] != '[' {
+			events = append(events, event{key: "escape"})
+			data = data[1:]
+			continue
+		}
+		if arrow, ok := map[byte]string{'A': "up", 'B': "down", 'C': "right", 'D': "left"}[data[2]]; ok {
+			events = append(events, event{key: arrow})
+			data = data[3:]
+			continue
+		}
+		if data[2] != '<' {
+			events = append(events, event{key: "escape"})
+			data = data[1:]
+			continue
+		}
+		// SGR mouse report: ESC [ < button ; column ; row, then M for a press or m for a release
+		end := bytes.IndexAny(data, "Mm")
+		if end < 0 {
+			return events, data
+		}
+		fields := strings.Split(string(data[3:end]), ";")
+		press := data[end] == 'M'
+		data = data[end+1:]
+		if len(fields) != 3 {
+			continue
+		}
+		button, err1 := strconv.Atoi(fields[0])
+		col, err2 := strconv.Atoi(fields[1])
+		row, err3 := strconv.Atoi(fields[2])
+		if err1 != nil || err2 != nil || err3 != nil || !press {
+			continue
+		}
+		switch button {
+		case 0:
+			events = append(events, event{click: true, col: col - 1, row: row - 1})
+		case 64:
+			events = append(events, event{wheel: -1, col: col - 1, row: row - 1})
+		case 65:
+			events = append(events, event{wheel: 1, col: col - 1, row: row - 1})
+		}
+	}
+	return events, nil
+}
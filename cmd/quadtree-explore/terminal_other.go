@@ -0,0 +1,15 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// runScreen needs stty to put the terminal in raw mode, elsewhere the explorer reads commands
+func (e *explorer) runScreen(in *os.File, out io.Writer) error {
+	return errors.New("the full screen explorer needs a Unix terminal")
+}
@@ -0,0 +1,52 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package main
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// stty runs stty on the terminal of in and returns its output
+func stty(in *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = in
+	out, err := cmd.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// runScreen runs the explorer full screen on a raw terminal until the user quits
+func (e *explorer) runScreen(in *os.File, out io.Writer) error {
+	state, err := stty(in, "-g")
+	if err != nil {
+		return err
+	}
+	if _, err := stty(in, "raw", "-echo"); err != nil {
+		return err
+	}
+	defer stty(in, state)
+	io.WriteString(out, enterScreen)
+	defer io.WriteString(out, leaveScreen)
+
+	status := ""
+	buf := make([]byte, 256)
+	var pending []byte
+	for {
+		io.WriteString(out, e.screen(status))
+		n, err := in.Read(buf)
+		if err != nil {
+			return nil
+		}
+		var events []event
+		events, pending = decodeInput(append(pending, buf[:n]...))
+		for _, ev := range events {
+			var quit bool
+			if status, quit = e.handle(ev); quit {
+				return nil
+			}
+		}
+	}
+}
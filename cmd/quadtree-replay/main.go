@@ -0,0 +1,316 @@
+// quadtree-replay runs a recording made by Quadtree.Record against the quadtree package it is built with,
+// printing the results of the recorded queries and the canonical dump of the final tree.
+// Comparing the output of builds of different versions of the package shows where their behavior diverges.
+//
+// Usage:
+//
+//	quadtree-replay [-dump=false] [FILE]
+//
+// The recording is read from FILE, or from standard input
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// object is a recorded object, moved by Update to the position recorded for it
+type object struct {
+	id                  uint64
+	x, y, width, height float64
+	next                []float64 // position recorded for the next Update, nil if it does not move
+}
+
+func (o *object) X() float64      { return o.x }
+func (o *object) Y() float64      { return o.y }
+func (o *object) Width() float64  { return o.width }
+func (o *object) Height() float64 { return o.height }
+
+func (o *object) Update(time.Duration) bool {
+	if o.next == nil {
+		return false
+	}
+	o.place(o.next)
+	o.next = nil
+	return true
+}
+
+func (o *object) place(values []float64) {
+	o.x, o.y, o.width, o.height = values[0], values[1], values[2], values[3]
+}
+
+type replayer struct {
+	qt      *quadtree.Quadtree
+	options []quadtree.Option // recorded before the tree
+	objects map[uint64]*object
+	out     io.Writer
+}
+
+func main() {
+	dump := flag.Bool("dump", true, "print the canonical dump of the final tree")
+	flag.Parse()
+
+	in := io.Reader(os.Stdin)
+	if flag.NArg() > 0 {
+		f, err := os.Open(flag.Arg(0))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		defer f.Close()
+		in = f
+	}
+
+	r := &replayer{objects: make(map[uint64]*object), out: os.Stdout}
+	if err := r.run(in); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if *dump && r.qt != nil {
+		fmt.Fprint(r.out, r.qt.String())
+	}
+}
+
+func (r *replayer) run(in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber += 1 {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 2 {
+			return fmt.Errorf("line %d: missing operation", lineNumber)
+		}
+		if err := r.execute(fields[1], fields[2:]); err != nil {
+			return fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func parseFloats(args []string, count int) ([]float64, error) {
+	if len(args) != count {
+		return nil, fmt.Errorf("expects %d values, got %d", count, len(args))
+	}
+	values := make([]float64, count)
+	for i, arg := range args {
+		v, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// parseObject parses ID X Y WIDTH HEIGHT
+func parseObject(args []string) (uint64, []float64, error) {
+	if len(args) == 0 {
+		return 0, nil, fmt.Errorf("missing object ID")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return 0, nil, err
+	}
+	values, err := parseFloats(args[1:], 4)
+	return id, values, err
+}
+
+// parseOption parses NAME VALUE... into the Option it was recorded from
+func parseOption(args []string) (quadtree.Option, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing option name")
+	}
+	counts := map[string]int{
+		"straddle-policy": 1, "child-overlap": 1, "epsilon": 1, "lifespan": 2, "eager-collapse": 0,
+		"integer-grid": 0, "fixed-point": 1, "reject-duplicates": 0, "invalid-coordinates-policy": 1,
+		"relocation-budget": 1, "y-axis": 1, "curve-order": 1,
+	}
+	name := args[0]
+	count, ok := counts[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown option %q", name)
+	}
+	v, err := parseFloats(args[1:], count)
+	if err != nil {
+		return nil, fmt.Errorf("option %s: %v", name, err)
+	}
+	switch name {
+	case "straddle-policy":
+		return quadtree.WithStraddlePolicy(quadtree.StraddlePolicy(v[0])), nil
+	case "child-overlap":
+		return quadtree.WithChildOverlap(v[0]), nil
+	case "epsilon":
+		return quadtree.WithEpsilon(v[0]), nil
+	case "lifespan":
+		return quadtree.WithLifespan(int(v[0]), int(v[1])), nil
+	case "eager-collapse":
+		return quadtree.WithEagerCollapse(), nil
+	case "integer-grid":
+		return quadtree.WithIntegerGrid(), nil
+	case "fixed-point":
+		return quadtree.WithFixedPoint(uint(v[0])), nil
+	case "reject-duplicates":
+		return quadtree.WithRejectDuplicates(), nil
+	case "invalid-coordinates-policy":
+		return quadtree.WithInvalidCoordinatesPolicy(quadtree.InvalidCoordinatesPolicy(v[0]), nil), nil
+	case "relocation-budget":
+		return quadtree.WithRelocationBudget(int(v[0])), nil
+	case "y-axis":
+		return quadtree.WithYAxis(quadtree.YAxis(v[0])), nil
+	default: // curve-order
+		return quadtree.WithCurveOrder(quadtree.CurveOrder(v[0])), nil
+	}
+}
+
+func (r *replayer) object(args []string) (*object, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("missing object ID")
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := r.objects[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown object %d", id)
+	}
+	return obj, nil
+}
+
+func (r *replayer) execute(op string, args []string) error {
+	if r.qt == nil && op != "tree" && op != "version" && op != "option" {
+		return fmt.Errorf("%s before tree", op)
+	}
+	switch op {
+	case "version":
+		if len(args) != 1 {
+			return fmt.Errorf("expects a version")
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+		if version < 1 || version > quadtree.RecordingVersion {
+			return fmt.Errorf("unsupported recording version %d, this build reads up to version %d", version, quadtree.RecordingVersion)
+		}
+	case "option":
+		if r.qt != nil {
+			return fmt.Errorf("option after tree")
+		}
+		option, err := parseOption(args)
+		if err != nil {
+			return err
+		}
+		r.options = append(r.options, option)
+	case "tree":
+		v, err := parseFloats(args, 6)
+		if err != nil {
+			return err
+		}
+		r.qt = quadtree.NewQuadtree(&quadtree.Bounds{X: v[0], Y: v[1], Width: v[2], Height: v[3]}, int(v[4]), int(v[5]), r.options...)
+	case "insert":
+		id, v, err := parseObject(args)
+		if err != nil {
+			return err
+		}
+		obj := &object{id: id}
+		obj.place(v)
+		r.objects[id] = obj
+		return r.qt.Insert(obj)
+	case "move":
+		_, v, err := parseObject(args)
+		if err != nil {
+			return err
+		}
+		obj, err := r.object(args)
+		if err != nil {
+			return err
+		}
+		r.qt.Remove(obj)
+		obj.place(v)
+		return r.qt.Insert(obj)
+	case "updated":
+		_, v, err := parseObject(args)
+		if err != nil {
+			return err
+		}
+		obj, err := r.object(args)
+		if err != nil {
+			return err
+		}
+		obj.next = v
+	case "update":
+		if len(args) != 1 {
+			return fmt.Errorf("expects a duration")
+		}
+		delta, err := time.ParseDuration(args[0])
+		if err != nil {
+			return err
+		}
+		r.qt.Update(delta)
+	case "remove":
+		obj, err := r.object(args)
+		if err != nil {
+			return err
+		}
+		r.qt.Remove(obj)
+		delete(r.objects, obj.id)
+	case "reset":
+		for id, obj := range r.objects {
+			r.qt.Remove(obj)
+			delete(r.objects, id)
+		}
+	case "retrieve":
+		v, err := parseFloats(args, 4)
+		if err != nil {
+			return err
+		}
+		found := r.qt.Retrieve(&quadtree.Bounds{X: v[0], Y: v[1], Width: v[2], Height: v[3]})
+		fmt.Fprintf(r.out, "retrieve %s: %s\n", strings.Join(args, " "), ids(found))
+	case "intersected":
+		var found []quadtree.PhysicalObject
+		if obj, err := r.object(args); err == nil {
+			found = r.qt.GetIntersectedObjects(obj)
+		}
+		fmt.Fprintf(r.out, "intersected %s: %s\n", args[0], ids(found))
+	case "intersections":
+		var pairs []string
+		for ele := r.qt.GetIntersection(nil, nil).Front(); ele != nil; ele = ele.Next() {
+			record := ele.Value.(*quadtree.IntersectionRecord)
+			one, another := record.One.(*object).id, record.Another.(*object).id
+			if one > another {
+				one, another = another, one
+			}
+			pairs = append(pairs, fmt.Sprintf("%d-%d", one, another))
+		}
+		sort.Strings(pairs)
+		fmt.Fprintf(r.out, "intersections: %s\n", strings.Join(pairs, " "))
+	default:
+		return fmt.Errorf("unknown operation %q", op)
+	}
+	return nil
+}
+
+// ids returns the sorted IDs of the objects
+func ids(objects []quadtree.PhysicalObject) string {
+	values := make([]int, len(objects))
+	for i, obj := range objects {
+		values[i] = int(obj.(*object).id)
+	}
+	sort.Ints(values)
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, " ")
+}
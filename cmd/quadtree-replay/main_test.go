@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func replay(recording string) (*replayer, string, error) {
+	var out bytes.Buffer
+	r := &replayer{objects: make(map[uint64]*object), out: &out}
+	err := r.run(strings.NewReader(recording))
+	return r, out.String(), err
+}
+
+func TestReplayRecording(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{X: 0, Y: 0, Width: 64, Height: 64}, 1, 6,
+		quadtree.WithStraddlePolicy(quadtree.StraddleDuplicate), quadtree.WithChildOverlap(2))
+	var recording bytes.Buffer
+	if err := qt.Record(&recording); err != nil {
+		t.Fatal(err)
+	}
+	a := &object{x: 30, y: 30, width: 4, height: 4}
+	b := &object{x: 2, y: 2, width: 4, height: 4}
+	c := &object{x: 40, y: 10, width: 8, height: 8}
+	for _, obj := range []*object{a, b, c} {
+		qt.Insert(obj)
+	}
+	qt.Retrieve(&quadtree.Bounds{X: 0, Y: 0, Width: 32, Height: 32})
+	c.x, c.y = 31, 31
+	qt.InsertOrMove(c)
+	qt.GetIntersectedObjects(a)
+	qt.GetIntersection(nil, nil)
+	qt.Remove(b)
+	if err := qt.Record(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	r, out, err := replay(recording.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.qt.String() != qt.String() {
+		t.Errorf("Expected the replayed tree\n%s\nto match the recorded tree\n%s", r.qt.String(), qt.String())
+	}
+	expected := "retrieve 0 0 32 32: 1 2\nintersected 1: 3\nintersections: 1-3\n"
+	if out != expected {
+		t.Errorf("Expected the query results\n%s\ngot\n%s", expected, out)
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	for _, recording := range []string{
+		"0 insert 1 1 1 1 1",
+		"0 tree 0 0 8 8 1 4\n1 jump",
+		"0 version 2",
+		"0 option spin 1",
+		"0 option lifespan 4",
+		"0 tree 0 0 8 8 1 4\n1 option epsilon 1",
+		"0 tree 0 0 8 8 1 4\n1 remove 1",
+	} {
+		if _, _, err := replay(recording); err == nil {
+			t.Errorf("Expected an error replaying %q", recording)
+		}
+	}
+}
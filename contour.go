@@ -0,0 +1,202 @@
+package quadtree
+
+import (
+	"math"
+)
+
+// maxContourLevel bounds the grid of OccupiedContours to 4096 cells per side whatever MaxLevels is
+const maxContourLevel = 12
+
+// gridVertex is a corner of the cells of OccupiedContours, in cell units
+type gridVertex struct {
+	col, row int
+}
+
+// contourEdge is a side between an occupied cell and an empty one, directed so that the occupied
+// cell lies on its right, with Y growing downwards
+type contourEdge struct {
+	from, to gridVertex
+}
+
+// OccupiedContours divides the bounds of the tree into the cells of the nodes at the given level,
+// that is 2 to the level cells per side, marks the cells overlapped by objects, and traces the
+// outlines of the occupied regions, e.g. for minimap blobs or fog of war meshes. Every outline is a
+// closed polygon whose first point is not repeated, its interior on the right with Y growing downwards:
+// outer outlines run clockwise and holes counterclockwise. Cells touching by a corner only belong to
+// separate outlines. Levels deeper than MaxLevels are clamped to it
+func (qt *Quadtree) OccupiedContours(level int) [][]Point {
+	if !qt.ready() || level < 0 {
+		return nil
+	}
+	if deepest := qt.MaxLevels - qt.Level; level > deepest {
+		level = deepest
+	}
+	if level > maxContourLevel {
+		level = maxContourLevel
+	}
+	if level < 0 {
+		level = 0
+	}
+	size := 1 << uint(level)
+	occupied := qt.occupiedCells(level)
+
+	var edges []contourEdge
+	outgoing := make(map[gridVertex][]int)
+	isOccupied := func(col, row int) bool {
+		return col >= 0 && row >= 0 && col < size && row < size && occupied[row*size+col]
+	}
+	add := func(from, to gridVertex) {
+		outgoing[from] = append(outgoing[from], len(edges))
+		edges = append(edges, contourEdge{from, to})
+	}
+	for row := 0; row < size; row += 1 {
+		for col := 0; col < size; col += 1 {
+			if !occupied[row*size+col] {
+				continue
+			}
+			if !isOccupied(col, row-1) {
+				add(gridVertex{col, row}, gridVertex{col + 1, row})
+			}
+			if !isOccupied(col+1, row) {
+				add(gridVertex{col + 1, row}, gridVertex{col + 1, row + 1})
+			}
+			if !isOccupied(col, row+1) {
+				add(gridVertex{col + 1, row + 1}, gridVertex{col, row + 1})
+			}
+			if !isOccupied(col-1, row) {
+				add(gridVertex{col, row + 1}, gridVertex{col, row})
+			}
+		}
+	}
+
+	cellWidth := qt.Width / float64(size)
+	cellHeight := qt.Height / float64(size)
+	used := make([]bool, len(edges))
+	var contours [][]Point
+	for first := range edges {
+		if used[first] {
+			continue
+		}
+		var loop []gridVertex
+		current := first
+		for !used[current] {
+			used[current] = true
+			edge := edges[current]
+			loop = append(loop, edge.from)
+			current = nextContourEdge(edges, outgoing[edge.to], edge, used)
+			if current < 0 {
+				break
+			}
+		}
+		contour := make([]Point, 0, len(loop))
+		for i, v := range loop {
+			prev, next := loop[(i+len(loop)-1)%len(loop)], loop[(i+1)%len(loop)]
+			if (prev.col == v.col && v.col == next.col) || (prev.row == v.row && v.row == next.row) {
+				// drop the points in the middle of a straight side
+				continue
+			}
+			contour = append(contour, Point{qt.X + float64(v.col)*cellWidth, qt.Y + float64(v.row)*cellHeight})
+		}
+		contours = append(contours, contour)
+	}
+	return contours
+}
+
+// nextContourEdge picks the unused edge following edge among the edges leaving its end. Where two
+// outlines touch by a corner, the right turn keeps them apart
+func nextContourEdge(edges []contourEdge, candidates []int, edge contourEdge, used []bool) int {
+	dx, dy := edge.to.col-edge.from.col, edge.to.row-edge.from.row
+	best, bestTurn := -1, math.MinInt32
+	for _, i := range candidates {
+		if used[i] {
+			continue
+		}
+		ex, ey := edges[i].to.col-edges[i].from.col, edges[i].to.row-edges[i].from.row
+		// with Y growing downwards, a positive cross product is a right turn
+		turn := dx*ey - dy*ex
+		if turn > bestTurn {
+			best, bestTurn = i, turn
+		}
+	}
+	return best
+}
+
+// occupiedCells returns, row by row, whether objects overlap each cell of the grid dividing the bounds
+// of the tree into the nodes at the given level. The nodes at that level mark their own cell as soon
+// as their subtree holds an object, only the objects stored above them are checked one by one
+func (qt *Quadtree) occupiedCells(level int) []bool {
+	size := 1 << uint(level)
+	occupied := make([]bool, size*size)
+	cellWidth := qt.Width / float64(size)
+	cellHeight := qt.Height / float64(size)
+	cell := func(v, origin, extent float64) int {
+		index := int(math.Floor((v - origin) / extent))
+		if index < 0 {
+			return 0
+		}
+		if index >= size {
+			return size - 1
+		}
+		return index
+	}
+	mark := func(b *Bounds) {
+		if !qt.Bounds.Intersects(b) {
+			return
+		}
+		left, top := cell(b.X, qt.X, cellWidth), cell(b.Y, qt.Y, cellHeight)
+		right := cell(math.Nextafter(b.X+b.Width, math.Inf(-1)), qt.X, cellWidth)
+		bottom := cell(math.Nextafter(b.Y+b.Height, math.Inf(-1)), qt.Y, cellHeight)
+		for row := top; row <= bottom; row += 1 {
+			for col := left; col <= right; col += 1 {
+				occupied[row*size+col] = true
+			}
+		}
+	}
+	// objects only stay within the bounds of their node without loose bounds or margins
+	tight := qt.m_config.straddlePolicy != StraddleLoose && qt.m_config.childOverlap <= 0
+	var visit func(node *Quadtree, depth int)
+	visit = func(node *Quadtree, depth int) {
+		if depth == level && depth > 0 && tight {
+			if node.holdsLive() {
+				x, y := node.Center()
+				occupied[cell(y, qt.Y, cellHeight)*size+cell(x, qt.X, cellWidth)] = true
+			}
+			return
+		}
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			if obj := ele.Value.(PhysicalObject); !node.buried(obj) {
+				mark(node.m_config.storedBounds(obj))
+			}
+		}
+		flags := node.m_ActiveNodes
+		index := 0
+		for flags > 0 {
+			if flags&1 == 1 {
+				visit(node.Nodes[index], depth+1)
+			}
+			flags >>= 1
+			index += 1
+		}
+	}
+	visit(qt, 0)
+	return occupied
+}
+
+// holdsLive tells whether the subtree stores an object that is not flagged as removed
+func (qt *Quadtree) holdsLive() bool {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if !qt.buried(ele.Value.(PhysicalObject)) {
+			return true
+		}
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 && qt.Nodes[index].holdsLive() {
+			return true
+		}
+		flags >>= 1
+		index += 1
+	}
+	return false
+}
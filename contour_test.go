@@ -0,0 +1,59 @@
+package quadtree_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestOccupiedContours(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 4)
+	if contours := qt.OccupiedContours(2); len(contours) != 0 {
+		t.Errorf("expects no outline without objects, got %v", contours)
+	}
+	qt.Insert(&TestPhysicalObject{0, 0, 3, 3})
+	qt.Insert(&TestPhysicalObject{4.5, 4.5, 1, 1})
+	qt.Insert(&TestPhysicalObject{4.5, 0, 3.5, 1})
+	qt.Insert(&TestPhysicalObject{6.5, 0, 1, 3})
+
+	// cells touching by a corner only belong to separate outlines
+	expected := []string{
+		"[{0 0} {8 0} {8 4} {6 4} {6 2} {4 2} {4 4} {0 4}]",
+		"[{4 4} {6 4} {6 6} {4 6}]",
+	}
+	contours := qt.OccupiedContours(2)
+	if len(contours) != len(expected) {
+		t.Fatalf("expects %d outlines, got %v", len(expected), contours)
+	}
+	// objects of loose nodes may overflow their cell
+	loose := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 4, quadtree.WithStraddlePolicy(quadtree.StraddleLoose))
+	for _, obj := range []*TestPhysicalObject{{0, 0, 3, 3}, {4.5, 4.5, 1, 1}, {4.5, 0, 3.5, 1}, {6.5, 0, 1, 3}} {
+		loose.Insert(obj)
+	}
+	if got := fmt.Sprint(loose.OccupiedContours(2)); got != fmt.Sprint(contours) {
+		t.Errorf("expects loose bounds to give %v, got %s", contours, got)
+	}
+	for i, contour := range contours {
+		if got := fmt.Sprint(contour); got != expected[i] {
+			t.Errorf("outline %d expects %s, got %s", i, expected[i], got)
+		}
+	}
+
+	// a ring has an outer outline and a hole running the other way
+	ring := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 4)
+	for _, obj := range []*TestPhysicalObject{{0, 0, 3, 1}, {0, 2, 3, 1}, {0, 1, 1, 1}, {2, 1, 1, 1}} {
+		ring.Insert(obj)
+	}
+	holes := ring.OccupiedContours(2)
+	if len(holes) != 2 || fmt.Sprint(holes[1]) != "[{2 1} {1 1} {1 2} {2 2}]" {
+		t.Errorf("expects an outline and a hole, got %v", holes)
+	}
+
+	// levels beyond MaxLevels are clamped instead of allocating huge grids
+	for _, level := range []int{4, 20, 32, 64} {
+		if got, want := fmt.Sprint(qt.OccupiedContours(level)), fmt.Sprint(qt.OccupiedContours(4)); got != want {
+			t.Errorf("level %d expects the outlines of MaxLevels %s, got %s", level, want, got)
+		}
+	}
+}
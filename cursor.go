@@ -0,0 +1,196 @@
+package quadtree
+
+import (
+	"container/list"
+)
+
+// Retrieve returns the objects whose bounds overlap region, touching borders count as overlap.
+// With MaxDepth, objects stored deeper than the given level are left out, see RetrieveClusters
+func (qt *Quadtree) Retrieve(region *Bounds, opts ...QueryOption) IntersectedObjects {
+	if !qt.ready() {
+		return nil
+	}
+	qt.recordQuery("retrieve", formatFields(region.X, region.Y, region.Width, region.Height))
+	if len(opts) == 0 && qt.m_config.results != nil {
+		qt.m_config.queryTruncated = false
+		return qt.cachedRetrieve(region)
+	}
+	objects, _ := qt.RetrieveClusters(region, opts...)
+	return objects
+}
+
+// Cluster summarizes the objects of a subtree a query did not descend into
+type Cluster struct {
+	Bounds Bounds // bounds of the node
+	Level  int    // level of the node
+	Count  int    // number of objects of the node and its subtrees the query would have returned
+}
+
+// RetrieveClusters is Retrieve returning, when limited by MaxDepth, the objects stored at shallower
+// levels individually, and a Cluster for every node at the maximum depth overlapping region
+func (qt *Quadtree) RetrieveClusters(region *Bounds, opts ...QueryOption) (IntersectedObjects, []Cluster) {
+	if !qt.ready() {
+		return nil, nil
+	}
+	qc := newQueryConfig(opts)
+	objects := qc.resultBuffer(qt.m_config)
+	var clusters []Cluster
+	var visit func(node *Quadtree)
+	visit = func(node *Quadtree) {
+		if !qc.enter() {
+			return
+		}
+		if qc.maxDepth >= 0 && node.Level >= qc.maxDepth {
+			if count := node.countMatches(region, qc); count > 0 {
+				clusters = append(clusters, Cluster{Bounds: *node.Bounds, Level: node.Level, Count: count})
+			}
+			return
+		}
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			obj := ele.Value.(PhysicalObject)
+			if node.buried(obj) || !qc.accepts(node, obj) {
+				continue
+			}
+			if !qc.test() {
+				return
+			}
+			if region.Intersects(node.m_config.storedBounds(obj)) {
+				objects = append(objects, obj)
+			}
+		}
+		flags := node.m_ActiveNodes
+		index := 0
+		for flags > 0 {
+			if flags&1 == 1 && node.Nodes[index].searchBounds().Intersects(region) {
+				visit(node.Nodes[index])
+			}
+			flags >>= 1
+			index += 1
+		}
+	}
+	visit(qt)
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		objects = dedupeObjects(objects)
+	}
+	qc.lend(qt.m_config, objects)
+	qc.report(qt.m_config)
+	return objects, clusters
+}
+
+// countMatches counts the live objects of the subtree the query would return for region, copies of an
+// object duplicated across quadrants counting once. The subtree root was already entered by the query
+func (qt *Quadtree) countMatches(region *Bounds, qc *queryConfig) int {
+	count := 0
+	qt.visitWhere(func(b *Bounds) bool {
+		return b.Intersects(region) && qc.enter()
+	}, func(obj PhysicalObject) {
+		if qc.accepts(qt, obj) && qc.test() && region.Intersects(qt.m_config.storedBounds(obj)) {
+			count += 1
+		}
+	})
+	return count
+}
+
+// dedupeObjects removes repeated objects, keeping the order of their first occurrence
+func dedupeObjects(objects []PhysicalObject) []PhysicalObject {
+	var unique []PhysicalObject
+	keep := dedupe(func(obj PhysicalObject) {
+		unique = append(unique, obj)
+	})
+	for _, obj := range objects {
+		keep(obj)
+	}
+	return unique
+}
+
+// Cursor is a resumable query returning the objects overlapping a region batch by batch,
+// without materializing the whole result. The tree must not be mutated while a cursor is in use
+type Cursor struct {
+	region  *Bounds
+	pending []*Quadtree   // nodes left to visit
+	node    *Quadtree     // node being visited
+	ele     *list.Element // next element of node to test
+	next    PhysicalObject
+	seen    map[PhysicalObject]bool // objects already returned, for trees duplicating straddling objects
+	query   *queryConfig
+}
+
+// Query returns a cursor over the objects Retrieve would return for region
+func (qt *Quadtree) Query(region *Bounds, opts ...QueryOption) *Cursor {
+	if !qt.ready() {
+		return &Cursor{region: region}
+	}
+	c := &Cursor{region: region, query: newQueryConfig(opts)}
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		c.seen = make(map[PhysicalObject]bool)
+	}
+	c.query.enter()
+	c.enter(qt)
+	c.next = c.advance()
+	return c
+}
+
+func (c *Cursor) enter(node *Quadtree) {
+	c.node = node
+	c.ele = node.m_Objects.Front()
+	// push children in reverse order so that they are visited in quadrant order
+	for index := 3; index >= 0; index -= 1 {
+		if node.m_ActiveNodes&(1<<uint(index)) != 0 && node.Nodes[index].searchBounds().Intersects(c.region) {
+			c.pending = append(c.pending, node.Nodes[index])
+		}
+	}
+}
+
+// advance returns the next object overlapping the region, nil once the traversal is over
+func (c *Cursor) advance() PhysicalObject {
+	for c.node != nil {
+		for c.ele != nil {
+			obj := c.ele.Value.(PhysicalObject)
+			c.ele = c.ele.Next()
+			if c.node.buried(obj) || !c.query.accepts(c.node, obj) {
+				continue
+			}
+			if !c.query.test() {
+				c.node, c.pending = nil, nil
+				return nil
+			}
+			if !c.region.Intersects(c.node.m_config.storedBounds(obj)) {
+				continue
+			}
+			if c.seen != nil {
+				if c.seen[obj] {
+					continue
+				}
+				c.seen[obj] = true
+			}
+			return obj
+		}
+		c.node = nil
+		if last := len(c.pending) - 1; last >= 0 {
+			if !c.query.enter() {
+				c.pending = nil
+				break
+			}
+			node := c.pending[last]
+			c.pending = c.pending[:last]
+			c.enter(node)
+		}
+	}
+	return nil
+}
+
+// Truncated tells whether the cursor ran out of the budget of its query, see WithBudget
+func (c *Cursor) Truncated() bool {
+	return c.query != nil && c.query.truncated
+}
+
+// Next fills batch with the following objects and returns how many were written,
+// done reports that no objects remain after them
+func (c *Cursor) Next(batch []PhysicalObject) (n int, done bool) {
+	for n < len(batch) && c.next != nil {
+		batch[n] = c.next
+		n += 1
+		c.next = c.advance()
+	}
+	return n, c.next == nil
+}
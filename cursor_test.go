@@ -0,0 +1,275 @@
+package quadtree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/quadtreetest"
+)
+
+func TestQueryCursor(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 100, 100}, 4, 8)
+	for i := 0; i < 300; i += 1 {
+		qt.Insert(&TestPhysicalObject{rng.Float64() * 98, rng.Float64() * 98, 1 + rng.Float64(), 1 + rng.Float64()})
+	}
+
+	region := &quadtree.Bounds{20, 30, 40, 25}
+	expected := qt.Retrieve(region)
+	if len(expected) == 0 {
+		t.Fatalf("expects Retrieve to find objects in %+v", *region)
+	}
+
+	var paged quadtree.IntersectedObjects
+	cursor := qt.Query(region)
+	batch := make([]quadtree.PhysicalObject, 7)
+	for pages := 0; ; pages += 1 {
+		if pages > len(expected) {
+			t.Fatalf("cursor never reports done")
+		}
+		n, done := cursor.Next(batch)
+		paged = append(paged, batch[:n]...)
+		if done {
+			break
+		}
+		if n != len(batch) {
+			t.Fatalf("cursor returns a partial batch of %d objects before being done", n)
+		}
+	}
+	if !quadtreetest.SameObjects(paged, expected) {
+		t.Errorf("paged query expects objects:\n%s\nBut got:\n%s", quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(paged))
+	}
+	if n, done := cursor.Next(batch); n != 0 || !done {
+		t.Errorf("exhausted cursor returns %d objects, done %v", n, done)
+	}
+
+	var all int
+	qt.Walk(func(obj quadtree.PhysicalObject) {
+		if obj.X() <= region.X+region.Width && region.X <= obj.X()+obj.Width() &&
+			obj.Y() <= region.Y+region.Height && region.Y <= obj.Y()+obj.Height() {
+			all += 1
+		}
+	})
+	if all != len(expected) {
+		t.Errorf("Retrieve returns %d objects, brute force finds %d", len(expected), all)
+	}
+}
+
+func TestRetrieveClusters(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	straddler := &TestPhysicalObject{3.5, 3.5, 1, 1}
+	qt.UpdateTree(listOf(
+		straddler,
+		&TestPhysicalObject{0, 0, 1, 1},
+		&TestPhysicalObject{1, 1, 1, 1},
+		&TestPhysicalObject{3, 3, 0.5, 0.5},
+		&TestPhysicalObject{6, 6, 1, 1},
+	))
+
+	objects, clusters := qt.RetrieveClusters(&quadtree.Bounds{0, 0, 3.9, 3.9}, quadtree.MaxDepth(1))
+	expected := quadtree.IntersectedObjects{straddler}
+	if !quadtreetest.SameObjects(objects, expected) {
+		t.Errorf("expects objects:\n%s\nBut got:\n%s", quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(objects))
+	}
+	if len(clusters) != 1 || clusters[0].Count != 3 || clusters[0].Level != 1 || clusters[0].Bounds != (quadtree.Bounds{0, 0, 4, 4}) {
+		t.Errorf("expects a single cluster of 3 objects in the top left quadrant, got %+v", clusters)
+	}
+
+	if objects := qt.Retrieve(&quadtree.Bounds{0, 0, 5, 5}); len(objects) != 4 {
+		t.Errorf("unrestricted Retrieve expects 4 objects, got %d", len(objects))
+	}
+
+	// only the live objects overlapping the region count
+	qt.MarkRemoved(straddler)
+	removed := &TestPhysicalObject{0.5, 0.5, 0.2, 0.2}
+	qt.Insert(removed)
+	qt.MarkRemoved(removed)
+	if _, clusters := qt.RetrieveClusters(&quadtree.Bounds{0, 0, 1.5, 1.5}, quadtree.MaxDepth(1)); len(clusters) != 1 || clusters[0].Count != 2 {
+		t.Errorf("expects a cluster of the 2 live objects overlapping the region, got %+v", clusters)
+	}
+
+	duplicating := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithStraddlePolicy(quadtree.StraddleDuplicate))
+	duplicating.Insert(&TestPhysicalObject{1.5, 1.5, 1, 1})
+	duplicating.Insert(&TestPhysicalObject{0, 0, 1, 1})
+	if _, clusters := duplicating.RetrieveClusters(&quadtree.Bounds{0, 0, 4, 4}, quadtree.MaxDepth(1)); len(clusters) != 1 || clusters[0].Count != 2 {
+		t.Errorf("expects the copies of an object to count once, got %+v", clusters)
+	}
+}
+
+func TestQueryStats(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	qt.UpdateTree(listOf(
+		&TestPhysicalObject{0, 0, 1, 1},
+		&TestPhysicalObject{2.5, 0.5, 1, 1},
+		&TestPhysicalObject{6, 6, 1, 1},
+	))
+	region := &quadtree.Bounds{0, 0, 3, 3}
+
+	var stats quadtree.QueryStats
+	qt.Retrieve(region, quadtree.WithStats(&stats))
+	// the root, its top left quadrant and the two nodes of that quadrant holding objects
+	if stats.NodesVisited != 4 || stats.CandidatesTested != 2 {
+		t.Errorf("Retrieve expects 4 nodes visited and 2 candidates tested, got %+v", stats)
+	}
+
+	var cursorStats quadtree.QueryStats
+	batch := make([]quadtree.PhysicalObject, 8)
+	qt.Query(region, quadtree.WithStats(&cursorStats)).Next(batch)
+	if cursorStats != stats {
+		t.Errorf("Query expects the same stats as Retrieve %+v, got %+v", stats, cursorStats)
+	}
+
+	var ringStats quadtree.QueryStats
+	qt.QueryRing(0, 0, 0, 2, quadtree.WithStats(&ringStats))
+	if ringStats.NodesVisited == 0 || ringStats.CandidatesTested == 0 || ringStats.NodesVisited > stats.NodesVisited {
+		t.Errorf("QueryRing expects to visit part of the tree, got %+v", ringStats)
+	}
+}
+
+func TestPairAndNearestQueryOptions(t *testing.T) {
+	one := &TestPhysicalObject{0, 0, 1, 1}
+	another := &TestPhysicalObject{0.5, 0.5, 1, 1}
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	qt.UpdateTree(listOf(one, another, &TestPhysicalObject{6, 6, 1, 1}))
+
+	var stats quadtree.QueryStats
+	if pairs := qt.GetIntersection(nil, nil, quadtree.WithStats(&stats)); pairs.Len() != 1 {
+		t.Errorf("GetIntersection expects 1 pair, got %v", pairs.Len())
+	}
+	if stats.NodesVisited == 0 || stats.CandidatesTested == 0 || stats.Truncated {
+		t.Errorf("GetIntersection expects to count its work, got %+v", stats)
+	}
+	if pairs := qt.GetIntersection(nil, nil, quadtree.Except(one)); pairs.Len() != 0 {
+		t.Errorf("GetIntersection expects Except to skip the pair, got %v pairs", pairs.Len())
+	}
+	if pairs := qt.GetIntersection(nil, nil, quadtree.WithBudget(1)); pairs.Len() != 0 || !qt.QueryTruncated() {
+		t.Errorf("GetIntersection expects a budget of the root only to truncate, got %v pairs", pairs.Len())
+	}
+
+	stats = quadtree.QueryStats{}
+	if got := qt.GetIntersectedObjects(one, quadtree.WithStats(&stats)); !sameObjects(got, another) {
+		t.Errorf("GetIntersectedObjects expects the other object, got %v", got)
+	}
+	if stats.NodesVisited == 0 || stats.CandidatesTested == 0 {
+		t.Errorf("GetIntersectedObjects expects to count its work, got %+v", stats)
+	}
+	if got := qt.GetIntersectedObjects(one, quadtree.Except(another)); len(got) != 0 || qt.QueryTruncated() {
+		t.Errorf("GetIntersectedObjects expects Except to skip the object, got %v", got)
+	}
+
+	stats = quadtree.QueryStats{}
+	if got := qt.NearestInDirection(3, 3, 0, 0, 0, quadtree.WithStats(&stats)); got != another {
+		t.Errorf("NearestInDirection expects the closest object, got %v", got)
+	}
+	if stats.NodesVisited == 0 || stats.CandidatesTested == 0 {
+		t.Errorf("NearestInDirection expects to count its work, got %+v", stats)
+	}
+	if got := qt.NearestInDirection(3, 3, 0, 0, 0, quadtree.Except(another)); got != one {
+		t.Errorf("NearestInDirection expects Except to skip the closest object, got %v", got)
+	}
+	if got := qt.NearestInDirection(3, 3, 0, 0, 0, quadtree.WithBudget(1)); got != nil || !qt.QueryTruncated() {
+		t.Errorf("NearestInDirection expects a budget of the root only to truncate, got %v", got)
+	}
+}
+
+func TestQueryBudget(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 64, 64}, 1, 8)
+	for i := 0; i < 16; i += 1 {
+		qt.Insert(&TestPhysicalObject{1, 1, 0.1, 0.1})
+	}
+	region := &quadtree.Bounds{0, 0, 2, 2}
+	var full quadtree.QueryStats
+	if got := qt.Retrieve(region, quadtree.WithStats(&full)); len(got) != 16 || full.Truncated {
+		t.Fatalf("Retrieve expects 16 objects without truncation, got %v objects", len(got))
+	}
+
+	var stats quadtree.QueryStats
+	got := qt.Retrieve(region, quadtree.WithBudget(3), quadtree.WithStats(&stats))
+	if stats.NodesVisited != 3 || !stats.Truncated {
+		t.Errorf("Retrieve expects to stop after 3 nodes and report the truncation, got %+v", stats)
+	}
+	if len(got) >= 16 {
+		t.Errorf("Retrieve expects a partial result, got %v objects", len(got))
+	}
+
+	stats = quadtree.QueryStats{}
+	cursor := qt.Query(region, quadtree.WithBudget(3), quadtree.WithStats(&stats))
+	batch := make([]quadtree.PhysicalObject, 32)
+	if n, done := cursor.Next(batch); n != len(got) || !done || !stats.Truncated {
+		t.Errorf("Cursor expects to return the %v objects of the budget, got %v objects", len(got), n)
+	}
+
+	stats = quadtree.QueryStats{}
+	qt.QueryRing(1, 1, 0, 2, quadtree.WithBudget(3), quadtree.WithStats(&stats))
+	if stats.NodesVisited != 3 || !stats.Truncated {
+		t.Errorf("QueryRing expects to stop after 3 nodes, got %+v", stats)
+	}
+	if !qt.QueryTruncated() || !cursor.Truncated() {
+		t.Errorf("expects the truncation to be reported without WithStats")
+	}
+	if qt.Retrieve(region); qt.QueryTruncated() {
+		t.Errorf("expects a query within budget to clear the truncation")
+	}
+}
+
+func TestQueryCandidateBudget(t *testing.T) {
+	// every object stays in the root, so that only the candidates bound the cost of the query
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 100, 4)
+	for i := 0; i < 16; i += 1 {
+		qt.Insert(&TestPhysicalObject{1, 1, 1, 1})
+	}
+	region := &quadtree.Bounds{0, 0, 2, 2}
+	got := qt.Retrieve(region, quadtree.WithCandidateBudget(5))
+	if len(got) != 5 || !qt.QueryTruncated() {
+		t.Errorf("Retrieve expects to stop after 5 candidates and report it, got %v objects", len(got))
+	}
+	if got := qt.QueryAlongPath([]quadtree.Point{{1, 1}}, 1, quadtree.WithCandidateBudget(5)); len(got) != 5 || !qt.QueryTruncated() {
+		t.Errorf("QueryAlongPath expects to stop after 5 candidates, got %v objects", len(got))
+	}
+	if got := qt.Retrieve(region, quadtree.WithCandidateBudget(16)); len(got) != 16 || qt.QueryTruncated() {
+		t.Errorf("Retrieve expects every object within budget, got %v objects", len(got))
+	}
+
+	cursor := qt.Query(region, quadtree.WithCandidateBudget(5))
+	batch := make([]quadtree.PhysicalObject, 32)
+	if n, done := cursor.Next(batch); n != 5 || !done || !cursor.Truncated() {
+		t.Errorf("Cursor expects to stop after 5 candidates, got %v objects", n)
+	}
+	if trace := qt.Explain(region, quadtree.WithCandidateBudget(5)); len(trace.Results) != 5 || !trace.Truncated {
+		t.Errorf("Explain expects to stop after 5 candidates, got %v results", len(trace.Results))
+	}
+}
+
+func TestQueryExcept(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 2, 4)
+	rider := &TestPhysicalObject{1, 1, 1, 1}
+	mount := &TestPhysicalObject{1, 2, 2, 1}
+	enemy := &TestPhysicalObject{2, 1, 1, 1}
+	qt.Insert(enemy)
+	qt.Insert(mount)
+	riderHandle, _ := qt.InsertHandle(rider)
+	region := &quadtree.Bounds{0, 0, 4, 4}
+
+	var stats quadtree.QueryStats
+	if got := qt.Retrieve(region, quadtree.Except(rider, mount), quadtree.WithStats(&stats)); !sameObjects(got, enemy) {
+		t.Errorf("Retrieve expects to skip the excluded objects, got %v", got)
+	}
+	if stats.CandidatesTested != 1 {
+		t.Errorf("Retrieve expects the excluded objects not to be tested, got %v tests", stats.CandidatesTested)
+	}
+
+	var set quadtree.HandleSet
+	set.Add(riderHandle)
+	except := []quadtree.QueryOption{quadtree.ExceptHandles(set), quadtree.Except(mount)}
+	if got := qt.Retrieve(region, except...); !sameObjects(got, enemy) {
+		t.Errorf("Retrieve expects to skip the excluded handles, got %v", got)
+	}
+	if got := qt.QueryRing(2, 2, 0, 3, except...); !sameObjects(got, enemy) {
+		t.Errorf("QueryRing expects to skip the excluded objects, got %v", got)
+	}
+	batch := make([]quadtree.PhysicalObject, 4)
+	if n, _ := qt.Query(region, except...).Next(batch); !sameObjects(batch[:n], enemy) {
+		t.Errorf("Cursor expects to skip the excluded objects, got %v", batch[:n])
+	}
+}
@@ -0,0 +1,73 @@
+package quadtree
+
+// CurveOrder is the space filling curve ordering the linear representations of a tree
+type CurveOrder int
+
+const (
+	// ZOrder follows the Morton curve, visiting the quadrants of every node in index order
+	ZOrder CurveOrder = iota
+	// HilbertOrder follows the Hilbert curve, whose consecutive cells are always adjacent, which
+	// gives serialized trees better locality for range queries
+	HilbertOrder
+)
+
+// WithCurveOrder sets the curve along which BuildPacked sorts objects, and ExportFlat and ExportObjects
+// lay out nodes. The default is ZOrder
+func WithCurveOrder(order CurveOrder) Option {
+	return func(c *config) {
+		c.curveOrder = order
+	}
+}
+
+// curveCode returns the position of the center of the object along the curve of the tree,
+// quantized over the bounds of current node
+func (qt *Quadtree) curveCode(obj PhysicalObject) uint64 {
+	if qt.m_config.curveOrder == HilbertOrder {
+		cx, cy := center(obj)
+		return hilbertCode(quantize(cx, qt.X, qt.Width), quantize(cy, qt.Y, qt.Height))
+	}
+	return qt.mortonCode(obj)
+}
+
+// hilbertCode returns the distance of the cell (x, y) along a Hilbert curve covering 2^32 by 2^32 cells
+func hilbertCode(x, y uint32) uint64 {
+	var d uint64
+	for s := uint32(1 << 31); s > 0; s >>= 1 {
+		var rx, ry uint32
+		if x&s != 0 {
+			rx = 1
+		}
+		if y&s != 0 {
+			ry = 1
+		}
+		d += uint64(s) * uint64(s) * uint64((3*rx)^ry)
+		// rotate the quadrant so that the curve within it starts and ends at the right corners
+		if ry == 0 {
+			if rx == 1 {
+				x, y = ^x, ^y
+			}
+			x, y = y, x
+		}
+	}
+	return d
+}
+
+// childOrder returns the quadrants of current node in the order the curve of the tree visits them
+func (qt *Quadtree) childOrder() [4]int {
+	order := [4]int{0, 1, 2, 3}
+	if qt.m_config.curveOrder != HilbertOrder {
+		return order
+	}
+	root := qt.root()
+	var codes [4]uint64
+	for index := range codes {
+		cx, cy := qt.quadrantBounds(index).Center()
+		codes[index] = hilbertCode(quantize(cx, root.X, root.Width), quantize(cy, root.Y, root.Height))
+	}
+	for i := 1; i < 4; i += 1 {
+		for j := i; j > 0 && codes[order[j]] < codes[order[j-1]]; j -= 1 {
+			order[j], order[j-1] = order[j-1], order[j]
+		}
+	}
+	return order
+}
@@ -0,0 +1,40 @@
+package quadtree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestHilbertOrder(t *testing.T) {
+	var objects []quadtree.PhysicalObject
+	for y := 0; y < 4; y += 1 {
+		for x := 0; x < 4; x += 1 {
+			objects = append(objects, &TestPhysicalObject{float64(x) + 0.25, float64(y) + 0.25, 0.5, 0.5})
+		}
+	}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 2, quadtree.WithCurveOrder(quadtree.HilbertOrder))
+	qt.BuildPacked(objects)
+
+	exported := qt.ExportObjects()
+	if len(exported) != 16 {
+		t.Fatalf("ExportObjects expects 16 objects, got %v", len(exported))
+	}
+	if exported[0].X() != 0.25 || exported[0].Y() != 0.25 {
+		t.Errorf("Hilbert order expects to start at the top left cell, got %v", exported[0])
+	}
+	// consecutive cells of a Hilbert curve are adjacent
+	for i := 1; i < len(exported); i += 1 {
+		dx := math.Abs(exported[i].X() - exported[i-1].X())
+		dy := math.Abs(exported[i].Y() - exported[i-1].Y())
+		if dx+dy != 1 {
+			t.Errorf("Objects %d and %d expect to be adjacent, got %v and %v", i-1, i, exported[i-1], exported[i])
+		}
+	}
+
+	nodes, _ := qt.ExportFlat()
+	if nodes[0].Children != [4]int32{1, 16, 6, 11} {
+		t.Errorf("ExportFlat expects the quadrants in Hilbert order, got children %v", nodes[0].Children)
+	}
+}
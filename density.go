@@ -0,0 +1,68 @@
+package quadtree
+
+import (
+	"sort"
+)
+
+// CellInfo describes a node of the tree
+type CellInfo struct {
+	Key    NodeKey // address of the node
+	Bounds Bounds  // bounds of the node
+	Level  int     // level of the node
+	Count  int     // number of objects stored in the node and its subtrees
+}
+
+// byDensity orders cells by decreasing count, then smaller cells first, then by key
+type byDensity []CellInfo
+
+func (s byDensity) Len() int      { return len(s) }
+func (s byDensity) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byDensity) Less(i, j int) bool {
+	if s[i].Count != s[j].Count {
+		return s[i].Count > s[j].Count
+	}
+	if s[i].Level != s[j].Level {
+		return s[i].Level > s[j].Level
+	}
+	return s[i].Key < s[j].Key
+}
+
+// DensestCells returns the k nodes at level minLevel or deeper holding the most objects in their
+// subtree, densest first. Cells with the same count are ordered smallest first, then by key.
+// Empty cells and objects flagged by MarkRemoved are left out
+func (qt *Quadtree) DensestCells(k int, minLevel int) []CellInfo {
+	if !qt.ready() || k <= 0 {
+		return nil
+	}
+	var cells []CellInfo
+	qt.collectCells(qt.Key(), minLevel, &cells)
+	sort.Sort(byDensity(cells))
+	if len(cells) > k {
+		cells = cells[:k]
+	}
+	return cells
+}
+
+// collectCells appends the non empty nodes of the subtree at minLevel or deeper, and returns the
+// number of objects of the subtree
+func (qt *Quadtree) collectCells(key NodeKey, minLevel int, cells *[]CellInfo) int {
+	count := 0
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if !qt.buried(ele.Value.(PhysicalObject)) {
+			count += 1
+		}
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			count += qt.Nodes[index].collectCells(key.Child(index), minLevel, cells)
+		}
+		flags >>= 1
+		index += 1
+	}
+	if count > 0 && qt.Level >= minLevel {
+		*cells = append(*cells, CellInfo{Key: key, Bounds: *qt.Bounds, Level: qt.Level, Count: count})
+	}
+	return count
+}
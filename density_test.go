@@ -0,0 +1,38 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestDensestCells(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	for _, obj := range []*TestPhysicalObject{
+		{0.5, 0.5, 0.5, 0.5}, {1, 1, 0.5, 0.5}, {2.5, 2.5, 0.5, 0.5},
+		{6, 6, 1, 1}, {4.5, 0.5, 1, 1}, {3, 3, 2, 2},
+	} {
+		qt.Insert(obj)
+	}
+
+	cells := qt.DensestCells(2, 1)
+	if len(cells) != 2 {
+		t.Fatalf("expects 2 cells, got %+v", cells)
+	}
+	if cells[0].Key != "0" || cells[0].Count != 3 || cells[0].Bounds != (quadtree.Bounds{0, 0, 4, 4}) {
+		t.Errorf("expects the top left quadrant densest, got %+v", cells[0])
+	}
+	if cells[1].Key != "00" || cells[1].Count != 2 || cells[1].Level != 2 {
+		t.Errorf("expects the top left cell of the top left quadrant next, got %+v", cells[1])
+	}
+
+	all := qt.DensestCells(100, 0)
+	if all[0].Key != "" || all[0].Count != 6 {
+		t.Errorf("expects the root first with level 0 allowed, got %+v", all[0])
+	}
+	for _, cell := range all {
+		if cell.Count == 0 {
+			t.Errorf("expects empty cells left out, got %+v", cell)
+		}
+	}
+}
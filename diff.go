@@ -0,0 +1,207 @@
+package quadtree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangeKind identifies the kind of a structural difference between two trees
+type ChangeKind int
+
+const (
+	// NodeAdded reports a node present in the second tree only
+	NodeAdded ChangeKind = iota
+	// NodeRemoved reports a node present in the first tree only
+	NodeRemoved
+	// ObjectAdded reports an object indexed by the second tree only
+	ObjectAdded
+	// ObjectRemoved reports an object indexed by the first tree only
+	ObjectRemoved
+	// ObjectMoved reports an object stored in different nodes of the two trees
+	ObjectMoved
+)
+
+var changeKindNames = [...]string{"node added", "node removed", "object added", "object removed", "object moved"}
+
+func (k ChangeKind) String() string {
+	if k < 0 || int(k) >= len(changeKindNames) {
+		return "ChangeKind(" + strconv.Itoa(int(k)) + ")"
+	}
+	return changeKindNames[k]
+}
+
+// Change is a structural difference between two trees. Nodes are identified by their path of
+// quadrant indices from the root, objects are matched between the trees by ID when both trees were
+// created WithIDs, and with == otherwise
+type Change struct {
+	Kind   ChangeKind
+	Path   []int          // node added or removed, or the node holding the object in the tree it is found in (the second one for ObjectMoved)
+	From   []int          // node holding the object in the first tree, for ObjectMoved only
+	Object PhysicalObject // nil for node changes
+}
+
+func (c Change) String() string {
+	if c.Object == nil {
+		return fmt.Sprintf("%v %s", c.Kind, formatPath(c.Path))
+	}
+	rect := formatRect(c.Object.X(), c.Object.Y(), c.Object.Width(), c.Object.Height())
+	if c.Kind == ObjectMoved {
+		return fmt.Sprintf("%v %s from %s to %s", c.Kind, rect, formatPath(c.From), formatPath(c.Path))
+	}
+	return fmt.Sprintf("%v %s in %s", c.Kind, rect, formatPath(c.Path))
+}
+
+// formatPath formats a quadrant path as "/" for the root, or "/0/3" for descendants
+func formatPath(path []int) string {
+	parts := make([]string, len(path))
+	for i, index := range path {
+		parts[i] = strconv.Itoa(index)
+	}
+	return "/" + strings.Join(parts, "/")
+}
+
+// treeLayout records the nodes of a tree and where its objects are stored, in traversal order.
+// Objects are keyed by their ID when the layout was taken by ID, and by themselves otherwise
+type treeLayout struct {
+	nodes        []string
+	nodePaths    map[string][]int
+	nodeBounds   map[string]Bounds
+	ids          *idRegistry // nil to key objects by themselves
+	objects      []PhysicalObject
+	objectPath   map[interface{}][]int
+	objectBounds map[interface{}]Bounds // bounds of the objects when the layout was taken
+}
+
+func layoutOf(qt *Quadtree) *treeLayout {
+	return newLayout(qt, nil)
+}
+
+// layoutByID takes the layout of a tree keying its objects by the IDs the tree assigned them, objects
+// without an ID are keyed by themselves
+func layoutByID(qt *Quadtree) *treeLayout {
+	return newLayout(qt, qt.m_config.ids)
+}
+
+func newLayout(qt *Quadtree, ids *idRegistry) *treeLayout {
+	layout := &treeLayout{
+		nodePaths:    make(map[string][]int),
+		nodeBounds:   make(map[string]Bounds),
+		ids:          ids,
+		objectPath:   make(map[interface{}][]int),
+		objectBounds: make(map[interface{}]Bounds),
+	}
+	if qt != nil {
+		qt.layout(layout, nil)
+	}
+	return layout
+}
+
+// key returns the key of an object in the layout
+func (layout *treeLayout) key(obj PhysicalObject) interface{} {
+	if layout.ids != nil {
+		if id, ok := layout.ids.ids[obj]; ok {
+			return id
+		}
+	}
+	return obj
+}
+
+func (qt *Quadtree) layout(layout *treeLayout, path []int) {
+	key := formatPath(path)
+	layout.nodes = append(layout.nodes, key)
+	layout.nodePaths[key] = path
+	layout.nodeBounds[key] = *qt.Bounds
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		obj := ele.Value.(PhysicalObject)
+		k := layout.key(obj)
+		if _, found := layout.objectPath[k]; !found {
+			layout.objects = append(layout.objects, obj)
+			layout.objectPath[k] = path
+			layout.objectBounds[k] = *boundsOf(obj)
+		}
+	}
+
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			childPath := append(append([]int(nil), path...), index)
+			qt.Nodes[index].layout(layout, childPath)
+		}
+		flags >>= 1
+		index += 1
+	}
+}
+
+// objectOf returns the object of the layout with the given key, obj when the key is obj
+func (layout *treeLayout) objectOf(key interface{}, obj PhysicalObject) PhysicalObject {
+	if id, ok := key.(uint64); ok {
+		return layout.ids.objects[id]
+	}
+	return obj
+}
+
+func samePath(one, another []int) bool {
+	if len(one) != len(another) {
+		return false
+	}
+	for i := range one {
+		if one[i] != another[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// changeStreamer publishes the changes of a tree as Updates advance the simulation
+type changeStreamer interface {
+	advance(delta time.Duration)
+}
+
+// Diff reports the structural differences between two trees or snapshots: nodes present in only
+// one of them, objects indexed by only one of them, and objects stored in different nodes.
+// Node changes come first, followed by object changes, each in traversal order. When both trees were
+// created WithIDs, objects with the same ID are the same object, so that trees holding copies of the
+// objects can be compared. Object changes report the object of the tree it is found in
+func Diff(a, b *Quadtree) []Change {
+	if a.ready() && b.ready() && a.m_config.ids != nil && b.m_config.ids != nil {
+		return diffLayouts(layoutByID(a.root()), layoutByID(b.root()))
+	}
+	return diffLayouts(layoutOf(a), layoutOf(b))
+}
+
+// diffLayouts reports the differences between two layouts, as Diff does
+func diffLayouts(la, lb *treeLayout) []Change {
+	var changes []Change
+
+	for _, key := range la.nodes {
+		if _, found := lb.nodePaths[key]; !found {
+			changes = append(changes, Change{Kind: NodeRemoved, Path: la.nodePaths[key]})
+		}
+	}
+	for _, key := range lb.nodes {
+		if _, found := la.nodePaths[key]; !found {
+			changes = append(changes, Change{Kind: NodeAdded, Path: lb.nodePaths[key]})
+		}
+	}
+
+	for _, obj := range la.objects {
+		key := la.key(obj)
+		from := la.objectPath[key]
+		to, found := lb.objectPath[key]
+		if !found {
+			changes = append(changes, Change{Kind: ObjectRemoved, Path: from, Object: obj})
+		} else if !samePath(from, to) {
+			changes = append(changes, Change{Kind: ObjectMoved, Path: to, From: from, Object: lb.objectOf(key, obj)})
+		}
+	}
+	for _, obj := range lb.objects {
+		key := lb.key(obj)
+		if _, found := la.objectPath[key]; !found {
+			changes = append(changes, Change{Kind: ObjectAdded, Path: lb.objectPath[key], Object: obj})
+		}
+	}
+	return changes
+}
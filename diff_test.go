@@ -0,0 +1,83 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestDiff(t *testing.T) {
+	moving := &TestPhysicalObject{0, 0, 1, 1}
+	staying := &TestPhysicalObject{3, 3, 1, 1}
+	leaving := &TestPhysicalObject{1.5, 1.5, 1, 1}
+	coming := &TestPhysicalObject{3, 0, 1, 1}
+
+	a := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 1)
+	a.UpdateTree(listOf(moving, staying, leaving))
+
+	if changes := quadtree.Diff(a, a); len(changes) != 0 {
+		t.Errorf("tree expects no difference with itself, got %v", changes)
+	}
+
+	moving.y = 3
+	c := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 1)
+	c.UpdateTree(listOf(moving, staying, coming))
+
+	expected := []string{
+		"node removed /0",
+		"node added /1",
+		"node added /2",
+		"object removed (1.5, 1.5, 1, 1) in /",
+		"object moved (0, 3, 1, 1) from /0 to /2",
+		"object added (3, 0, 1, 1) in /1",
+	}
+	changes := quadtree.Diff(a, c)
+	if len(changes) != len(expected) {
+		t.Fatalf("expects %d changes, got %v", len(expected), changes)
+	}
+	for i, change := range changes {
+		if change.String() != expected[i] {
+			t.Errorf("change %d expects to be %q, got %q", i, expected[i], change.String())
+		}
+	}
+}
+
+func TestDiffByID(t *testing.T) {
+	build := func(objects ...quadtree.PhysicalObject) *quadtree.Quadtree {
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 1, quadtree.WithIDs())
+		for _, obj := range objects {
+			qt.Insert(obj)
+		}
+		return qt
+	}
+	// copies of the same objects, inserted in the same order, get the same IDs
+	a := build(&TestPhysicalObject{0, 0, 1, 1}, &TestPhysicalObject{3, 3, 1, 1})
+	moved := &TestPhysicalObject{0, 3, 1, 1}
+	b := build(moved, &TestPhysicalObject{3, 3, 1, 1})
+
+	objectChanges := func(a, b *quadtree.Quadtree) []quadtree.Change {
+		var changes []quadtree.Change
+		for _, change := range quadtree.Diff(a, b) {
+			if change.Object != nil {
+				changes = append(changes, change)
+			}
+		}
+		return changes
+	}
+
+	changes := objectChanges(a, b)
+	if len(changes) != 1 || changes[0].String() != "object moved (0, 3, 1, 1) from /0 to /2" {
+		t.Fatalf("expects copies with the same ID to match, got %v", changes)
+	}
+	if changes[0].Object != moved {
+		t.Errorf("expects the moved object of the second tree, got %v", changes[0].Object)
+	}
+
+	// without IDs on both sides, copies are different objects
+	plain := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 1)
+	plain.Insert(moved)
+	plain.Insert(&TestPhysicalObject{3, 3, 1, 1})
+	if changes := objectChanges(a, plain); len(changes) != 4 {
+		t.Errorf("expects objects to be matched with == without IDs, got %v", changes)
+	}
+}
@@ -0,0 +1,82 @@
+package quadtree
+
+// DrawCache keeps the draw list of one viewer between frames, so that only the nodes whose
+// objects changed since the previous frame are gathered again. A DrawCache must not be shared by viewers
+type DrawCache struct {
+	tree    *Quadtree
+	view    Bounds
+	stamp   uint64
+	nodes   []*Quadtree
+	entries map[*Quadtree]*drawEntry
+	list    []PhysicalObject
+}
+
+// drawEntry holds the objects gathered from a node at a given version
+type drawEntry struct {
+	version uint64
+	objects []PhysicalObject
+}
+
+// NewDrawCache creates an empty draw list cache for a viewer of the tree
+func (qt *Quadtree) NewDrawCache() *DrawCache {
+	qt.ready()
+	return &DrawCache{tree: qt.root()}
+}
+
+// DrawList returns the objects stored in the nodes overlapping view. Culling happens per node,
+// so objects close to but outside of view may be included. The returned slice is reused by
+// the next call if nothing changed, callers must not modify it
+func (dc *DrawCache) DrawList(view *Bounds) []PhysicalObject {
+	if dc.list != nil && *view == dc.view && dc.tree.ChangeStamp() == dc.stamp {
+		return dc.list
+	}
+	dc.stamp = dc.tree.ChangeStamp()
+
+	var nodes []*Quadtree
+	dc.tree.visitNodes(view, func(node *Quadtree) {
+		nodes = append(nodes, node)
+	})
+
+	changed := dc.list == nil || *view != dc.view || len(nodes) != len(dc.nodes)
+	entries := make(map[*Quadtree]*drawEntry, len(nodes))
+	for i, node := range nodes {
+		entry := dc.entries[node]
+		if entry == nil || entry.version != node.m_version {
+			entry = &drawEntry{version: node.m_version, objects: node.Objects()}
+			changed = true
+		} else if !changed && dc.nodes[i] != node {
+			changed = true
+		}
+		entries[node] = entry
+	}
+	dc.view = *view
+	dc.nodes = nodes
+	dc.entries = entries
+	if !changed {
+		return dc.list
+	}
+
+	list := []PhysicalObject{}
+	for _, node := range nodes {
+		list = append(list, entries[node].objects...)
+	}
+	if dc.tree.m_config.straddlePolicy == StraddleDuplicate {
+		list = dedupeObjects(list)
+	}
+	dc.list = list
+	return list
+}
+
+// visitNodes calls fn with current node, and every node of the subtree whose search bounds overlap region
+func (qt *Quadtree) visitNodes(region *Bounds, fn func(*Quadtree)) {
+	fn(qt)
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 && qt.Nodes[index].searchBounds().Intersects(region) {
+			qt.Nodes[index].visitNodes(region, fn)
+		}
+		flags >>= 1
+		index += 1
+	}
+}
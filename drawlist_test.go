@@ -0,0 +1,36 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/quadtreetest"
+)
+
+func TestDrawList(t *testing.T) {
+	visible := &steeredObject{TestPhysicalObject: TestPhysicalObject{1, 1, 1, 1}}
+	hidden := &steeredObject{TestPhysicalObject: TestPhysicalObject{6, 6, 1, 1}}
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	qt.UpdateTree(listOf(visible, hidden, &steeredObject{TestPhysicalObject: TestPhysicalObject{6, 1, 1, 1}}))
+
+	cache := qt.NewDrawCache()
+	view := &quadtree.Bounds{0, 0, 3, 3}
+	list := cache.DrawList(view)
+	expected := quadtree.IntersectedObjects{visible}
+	if !quadtreetest.SameObjects(list, expected) {
+		t.Fatalf("expects draw list:\n%s\nBut got:\n%s", quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(list))
+	}
+
+	qt.Update(0)
+	if again := cache.DrawList(view); &again[0] != &list[0] {
+		t.Errorf("expects the cached draw list when nothing changed")
+	}
+
+	hidden.moveTo(2, 2)
+	qt.Update(0)
+	list = cache.DrawList(view)
+	expected = quadtree.IntersectedObjects{visible, hidden}
+	if !quadtreetest.SameObjects(list, expected) {
+		t.Errorf("expects draw list after move:\n%s\nBut got:\n%s", quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(list))
+	}
+}
@@ -0,0 +1,81 @@
+package quadtree
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// String returns a canonical text dump of the tree, suitable for golden files and diffing between runs.
+// Every node is printed with its bounds, followed by its objects sorted by position and size,
+// then by its children in quadrant order. Numbers are printed in their shortest exact representation
+func (qt *Quadtree) String() string {
+	if !qt.ready() {
+		return ""
+	}
+	var buf bytes.Buffer
+	qt.dump(&buf, 0)
+	return buf.String()
+}
+
+func (qt *Quadtree) dump(buf *bytes.Buffer, indent int) {
+	indentString := strings.Repeat("\t", indent)
+	buf.WriteString("node ")
+	buf.WriteString(formatRect(qt.X, qt.Y, qt.Width, qt.Height))
+	buf.WriteString("\n")
+
+	for _, obj := range sortedObjects(qt.Objects()) {
+		buf.WriteString(indentString)
+		buf.WriteString("\tobject ")
+		buf.WriteString(formatRect(obj.X(), obj.Y(), obj.Width(), obj.Height()))
+		buf.WriteString("\n")
+	}
+
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			buf.WriteString(indentString)
+			buf.WriteString("\t")
+			buf.WriteString(strconv.Itoa(index))
+			buf.WriteString(": ")
+			qt.Nodes[index].dump(buf, indent+1)
+		}
+		flags >>= 1
+		index += 1
+	}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func formatRect(x, y, width, height float64) string {
+	return "(" + formatFloat(x) + ", " + formatFloat(y) + ", " + formatFloat(width) + ", " + formatFloat(height) + ")"
+}
+
+// sortedObjects sorts objects by X, Y, Width and Height
+func sortedObjects(objects []PhysicalObject) []PhysicalObject {
+	sort.Stable(byPosition(objects))
+	return objects
+}
+
+// byPosition orders objects by X, Y, Width and Height, without the reflection of sort.Slice
+type byPosition []PhysicalObject
+
+func (s byPosition) Len() int      { return len(s) }
+func (s byPosition) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byPosition) Less(i, j int) bool {
+	a, b := s[i], s[j]
+	if a.X() != b.X() {
+		return a.X() < b.X()
+	}
+	if a.Y() != b.Y() {
+		return a.Y() < b.Y()
+	}
+	if a.Width() != b.Width() {
+		return a.Width() < b.Width()
+	}
+	return a.Height() < b.Height()
+}
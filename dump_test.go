@@ -0,0 +1,39 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestString(t *testing.T) {
+	objects := []quadtree.PhysicalObject{
+		&TestPhysicalObject{1.5, 1.5, 1, 1},
+		&TestPhysicalObject{1, 1.5, 1, 1},
+		&TestPhysicalObject{0, 0, 1, 1},
+		&TestPhysicalObject{0.5, 0, 0.25, 1},
+		&TestPhysicalObject{3, 3, 1, 1},
+	}
+	expected := `node (0, 0, 4, 4)
+	object (1, 1.5, 1, 1)
+	object (1.5, 1.5, 1, 1)
+	0: node (0, 0, 2, 2)
+		object (0, 0, 1, 1)
+		object (0.5, 0, 0.25, 1)
+	3: node (2, 2, 2, 2)
+		object (3, 3, 1, 1)
+`
+
+	forward := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 2, 1)
+	backward := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 2, 1)
+	for i := range objects {
+		forward.Insert(objects[i])
+		backward.Insert(objects[len(objects)-1-i])
+	}
+	if s := forward.String(); s != expected {
+		t.Errorf("Quadtree expects to be dumped as:\n%s\nBut dumped as:\n%s", expected, s)
+	}
+	if s := backward.String(); s != expected {
+		t.Errorf("Quadtree built in reverse order expects to be dumped as:\n%s\nBut dumped as:\n%s", expected, s)
+	}
+}
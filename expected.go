@@ -0,0 +1,31 @@
+package quadtree
+
+// WithExpectedObjects sizes the internal maps of the tree for n objects up front, such as the IDs
+// of WithIDs, the pairs of WithPersistentPairs, and the tags and handles created on first use, so that
+// the initial bulk load of a dataset of known size does not grow them again and again.
+// Nodes store their objects in linked lists, which need no sizing
+func WithExpectedObjects(n int) Option {
+	return func(c *config) {
+		if n < 0 {
+			n = 0
+		}
+		c.expectedObjects = n
+	}
+}
+
+// presize sizes the maps created by options for the expected number of objects, whatever the order
+// of the options
+func (c *config) presize() {
+	n := c.expectedObjects
+	if n == 0 {
+		return
+	}
+	if c.ids != nil && len(c.ids.ids) == 0 {
+		c.ids.ids = make(map[PhysicalObject]uint64, n)
+		c.ids.objects = make(map[uint64]PhysicalObject, n)
+	}
+	if c.pairs != nil && len(c.pairs.adjacent) == 0 {
+		c.pairs.adjacent = make(map[PhysicalObject]map[PhysicalObject]bool, n)
+		c.pairs.dirty = make(map[PhysicalObject]bool, n)
+	}
+}
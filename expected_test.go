@@ -0,0 +1,38 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestExpectedObjects(t *testing.T) {
+	const n = 512
+	objects := make([]*TestPhysicalObject, n)
+	for i := range objects {
+		objects[i] = &TestPhysicalObject{float64(i % 32), float64(i / 32), 1, 1}
+	}
+	load := func(opts ...quadtree.Option) *quadtree.Quadtree {
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 32, 32}, n, 1, opts...)
+		for _, obj := range objects {
+			qt.InsertHandle(obj)
+		}
+		return qt
+	}
+
+	// the option works before or after the options it sizes maps for
+	for _, qt := range []*quadtree.Quadtree{
+		load(quadtree.WithExpectedObjects(n), quadtree.WithIDs()),
+		load(quadtree.WithIDs(), quadtree.WithExpectedObjects(n)),
+	} {
+		if id, ok := qt.ID(objects[n-1]); !ok || id != n {
+			t.Errorf("expects IDs to be assigned, got %d", id)
+		}
+	}
+
+	grown := testing.AllocsPerRun(5, func() { load(quadtree.WithIDs()) })
+	sized := testing.AllocsPerRun(5, func() { load(quadtree.WithIDs(), quadtree.WithExpectedObjects(n)) })
+	if sized >= grown {
+		t.Errorf("expects fewer allocations with expected objects, got %v and %v without", sized, grown)
+	}
+}
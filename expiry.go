@@ -0,0 +1,115 @@
+package quadtree
+
+import (
+	"time"
+)
+
+// expiry tracks the objects which leave the tree on their own
+type expiry struct {
+	elapsed   time.Duration                    // time of the Updates so far
+	deadlines map[PhysicalObject]time.Duration // elapsed time at which the objects of InsertWithTTL die
+	liveness  map[PhysicalObject]func() bool
+	order     []PhysicalObject // tracked objects in the order they were inserted, forgotten ones included
+}
+
+// newExpiry returns the expiry of the tree, creating it on first use
+func (c *config) newExpiry() *expiry {
+	if c.expiry == nil {
+		c.expiry = &expiry{
+			deadlines: make(map[PhysicalObject]time.Duration),
+			liveness:  make(map[PhysicalObject]func() bool),
+		}
+	}
+	return c.expiry
+}
+
+// tracked tells whether the object is tracked
+func (e *expiry) tracked(obj PhysicalObject) bool {
+	_, deadline := e.deadlines[obj]
+	_, liveness := e.liveness[obj]
+	return deadline || liveness
+}
+
+// forget stops tracking the object
+func (e *expiry) forget(obj PhysicalObject) {
+	delete(e.deadlines, obj)
+	delete(e.liveness, obj)
+}
+
+// rekey moves the deadline and the liveness check of old to obj, see Quadtree.rekey
+func (e *expiry) rekey(old, obj PhysicalObject) {
+	if !e.tracked(old) {
+		return
+	}
+	if deadline, ok := e.deadlines[old]; ok {
+		delete(e.deadlines, old)
+		e.deadlines[obj] = deadline
+	}
+	if alive, ok := e.liveness[old]; ok {
+		delete(e.liveness, old)
+		e.liveness[obj] = alive
+	}
+	e.order = append(e.order, obj)
+}
+
+// InsertWithTTL inserts the object like Insert, and removes it once Updates advanced the simulation by
+// ttl, e.g. for temporary effects such as explosions or decals. Expired objects are reported to
+// WithOnRemoved with RemovedExpired
+func (qt *Quadtree) InsertWithTTL(obj PhysicalObject, ttl time.Duration) error {
+	if err := qt.Insert(obj); err != nil {
+		return err
+	}
+	e := qt.m_config.newExpiry()
+	if !e.tracked(obj) {
+		e.order = append(e.order, obj)
+	}
+	e.deadlines[obj] = e.elapsed + ttl
+	return nil
+}
+
+// InsertWithLiveness inserts the object like Insert, and removes it during the first Update at which
+// alive returns false. Dead objects are reported to WithOnRemoved with RemovedExpired
+func (qt *Quadtree) InsertWithLiveness(obj PhysicalObject, alive func() bool) error {
+	if err := qt.Insert(obj); err != nil {
+		return err
+	}
+	e := qt.m_config.newExpiry()
+	if !e.tracked(obj) {
+		e.order = append(e.order, obj)
+	}
+	e.liveness[obj] = alive
+	return nil
+}
+
+// expire removes the tracked objects which died, at the start of an Update
+func (qt *Quadtree) expire(delta time.Duration) {
+	e := qt.m_config.expiry
+	if e == nil {
+		return
+	}
+	e.elapsed += delta
+	root := qt.root()
+	kept := e.order[:0]
+	var dead []PhysicalObject
+	for _, obj := range e.order {
+		if !e.tracked(obj) {
+			continue
+		}
+		deadline, timed := e.deadlines[obj]
+		alive, living := e.liveness[obj]
+		if (timed && e.elapsed >= deadline) || (living && !alive()) {
+			dead = append(dead, obj)
+			continue
+		}
+		kept = append(kept, obj)
+	}
+	e.order = kept
+	for _, obj := range dead {
+		if removed := root.remove(obj); removed != nil {
+			root.forget(removed)
+			root.notifyRemoved(removed, RemovedExpired)
+		} else {
+			e.forget(obj)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package quadtree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestExpiry(t *testing.T) {
+	var removed []string
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 3, quadtree.WithOnRemoved(func(obj quadtree.PhysicalObject, reason quadtree.RemoveReason) {
+		removed = append(removed, reason.String())
+	}))
+	explosion := &TestPhysicalObject{1, 1, 1, 1}
+	decal := &TestPhysicalObject{5, 5, 1, 1}
+	forgotten := &TestPhysicalObject{5, 1, 1, 1}
+	burning := true
+	qt.InsertWithTTL(explosion, 2*time.Second)
+	qt.InsertWithLiveness(decal, func() bool { return burning })
+	qt.InsertWithTTL(forgotten, time.Second)
+	qt.Remove(forgotten)
+
+	qt.Update(time.Second)
+	if qt.FindObject(explosion) == nil || qt.FindObject(decal) == nil {
+		t.Error("expects live objects to stay")
+	}
+	qt.Update(time.Second)
+	if qt.FindObject(explosion) != nil {
+		t.Error("expects the object to expire once its TTL elapsed")
+	}
+	burning = false
+	qt.Update(time.Second)
+	if qt.FindObject(decal) != nil {
+		t.Error("expects the object to be removed once dead")
+	}
+	expected := []string{"explicit", "expired", "expired"}
+	if len(removed) != len(expected) {
+		t.Fatalf("expects the removals %v, got %v", expected, removed)
+	}
+	for i := range expected {
+		if removed[i] != expected[i] {
+			t.Errorf("removal %d expects %v, got %v", i, expected[i], removed[i])
+		}
+	}
+
+	// objects inserted again without a TTL stay
+	qt.InsertWithTTL(explosion, time.Second)
+	qt.Remove(explosion)
+	qt.Insert(explosion)
+	qt.Update(2 * time.Second)
+	if qt.FindObject(explosion) == nil {
+		t.Error("expects the TTL to be dropped with the object")
+	}
+}
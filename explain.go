@@ -0,0 +1,149 @@
+package quadtree
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// TraceKind is the kind of a step of a query trace
+type TraceKind int
+
+const (
+	TraceVisit   TraceKind = iota // the query tests the objects of a node
+	TracePrune                    // the query skips a child node and its subtree
+	TraceCluster                  // the query summarizes a node at MaxDepth without descending into it
+	TraceAccept                   // an object overlaps the region and is returned
+	TraceReject                   // an object was tested and does not overlap the region
+	TraceSkip                     // an object was skipped without being tested
+)
+
+var traceKindNames = [...]string{"visit", "prune", "cluster", "accept", "reject", "skip"}
+
+func (k TraceKind) String() string {
+	if k < 0 || int(k) >= len(traceKindNames) {
+		return fmt.Sprintf("TraceKind(%d)", int(k))
+	}
+	return traceKindNames[k]
+}
+
+// TraceStep is a decision taken by a query, on a node or on one of its objects
+type TraceStep struct {
+	Kind   TraceKind
+	Key    NodeKey        // node the decision was taken in, the pruned node for TracePrune
+	Bounds Bounds         // bounds of the node, or of the object for object steps
+	Object PhysicalObject // object of the step, nil for node steps
+	Reason string         // why the node was pruned or the object skipped or rejected
+}
+
+// Trace records how a query ran, in the order the decisions were taken
+type Trace struct {
+	Region    Bounds
+	Steps     []TraceStep
+	Results   []PhysicalObject // objects the query returns, as Retrieve would
+	Truncated bool             // the query ran out of budget, Results is incomplete
+}
+
+// Explain runs a Retrieve of region with opts and records the nodes it visits, the nodes it prunes
+// and the objects it tests, along with the reason of every decision. Use it to find out why a query
+// returns an unexpected result, or renders the Trace as text with String
+func (qt *Quadtree) Explain(region *Bounds, opts ...QueryOption) Trace {
+	trace := Trace{Region: *region}
+	if !qt.ready() {
+		return trace
+	}
+	qc := newQueryConfig(opts)
+	var visit func(node *Quadtree, key NodeKey)
+	visit = func(node *Quadtree, key NodeKey) {
+		if !qc.enter() {
+			trace.Steps = append(trace.Steps, TraceStep{
+				Kind:   TracePrune,
+				Key:    key,
+				Bounds: *node.Bounds,
+				Reason: qc.exhausted(),
+			})
+			return
+		}
+		if qc.maxDepth >= 0 && node.Level >= qc.maxDepth {
+			trace.Steps = append(trace.Steps, TraceStep{
+				Kind:   TraceCluster,
+				Key:    key,
+				Bounds: *node.Bounds,
+				Reason: fmt.Sprintf("level %d reaches MaxDepth %d, %d objects", node.Level, qc.maxDepth, node.countMatches(region, qc)),
+			})
+			return
+		}
+		trace.Steps = append(trace.Steps, TraceStep{Kind: TraceVisit, Key: key, Bounds: *node.Bounds})
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			obj := ele.Value.(PhysicalObject)
+			step := TraceStep{Key: key, Bounds: *boundsOf(obj), Object: obj}
+			switch {
+			case node.buried(obj):
+				step.Kind, step.Reason = TraceSkip, "removed"
+			case !qc.accepts(node, obj):
+				step.Kind, step.Reason = TraceSkip, "excluded by the query"
+			case !qc.test():
+				step.Kind, step.Reason = TraceSkip, qc.exhausted()
+			case region.Intersects(boundsOf(obj)):
+				step.Kind = TraceAccept
+				trace.Results = append(trace.Results, obj)
+			default:
+				step.Kind, step.Reason = TraceReject, "bounds do not overlap the region"
+			}
+			trace.Steps = append(trace.Steps, step)
+		}
+		for index := range node.Nodes {
+			child := node.Nodes[index]
+			switch {
+			case node.m_ActiveNodes&(1<<uint(index)) == 0:
+			case !child.searchBounds().Intersects(region):
+				trace.Steps = append(trace.Steps, TraceStep{
+					Kind:   TracePrune,
+					Key:    key.Child(index),
+					Bounds: *child.Bounds,
+					Reason: "search bounds do not overlap the region",
+				})
+			default:
+				visit(child, key.Child(index))
+			}
+		}
+	}
+	visit(qt, qt.Key())
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		trace.Results = dedupeObjects(trace.Results)
+	}
+	trace.Truncated = qc.truncated
+	return trace
+}
+
+// exhausted describes the budget the query ran out of
+func (qc *queryConfig) exhausted() string {
+	if qc.candidates > 0 && qc.tested >= qc.candidates {
+		return fmt.Sprintf("budget of %d candidates exhausted", qc.candidates)
+	}
+	return fmt.Sprintf("budget of %d nodes exhausted", qc.budget)
+}
+
+// String renders the trace as text, one step per line, indented by the depth of the node
+func (t Trace) String() string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "explain %v: %d results\n", t.Region, len(t.Results))
+	for _, step := range t.Steps {
+		key := string(step.Key)
+		if key == "" {
+			key = "root"
+		}
+		indent := len(step.Key)
+		if step.Object != nil {
+			indent += 1
+		}
+		for i := 0; i < indent; i += 1 {
+			buf.WriteString("  ")
+		}
+		fmt.Fprintf(&buf, "%v %v %v", step.Kind, key, step.Bounds)
+		if step.Reason != "" {
+			buf.WriteString(": " + step.Reason)
+		}
+		buf.WriteByte('\n')
+	}
+	return buf.String()
+}
@@ -0,0 +1,62 @@
+package quadtree_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestExplain(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 1)
+	inside := &TestPhysicalObject{1, 1, 1, 1}
+	outside := &TestPhysicalObject{5, 5, 2, 1}
+	qt.UpdateTree(listOf(inside, outside))
+
+	trace := qt.Explain(&quadtree.Bounds{0, 0, 3, 3})
+	if len(trace.Results) != 1 || trace.Results[0] != inside {
+		t.Fatalf("Explain expects to return the object inside the region, got %v", trace.Results)
+	}
+	expected := []struct {
+		kind quadtree.TraceKind
+		key  quadtree.NodeKey
+	}{
+		{quadtree.TraceVisit, ""},
+		{quadtree.TraceVisit, "0"},
+		{quadtree.TraceAccept, "0"},
+		{quadtree.TracePrune, "3"},
+	}
+	if len(trace.Steps) != len(expected) {
+		t.Fatalf("Explain expects %d steps, got:\n%v", len(expected), trace)
+	}
+	for i, step := range trace.Steps {
+		if step.Kind != expected[i].kind || step.Key != expected[i].key {
+			t.Errorf("Step %d expects to be %v %q, got %v %q", i, expected[i].kind, expected[i].key, step.Kind, step.Key)
+		}
+	}
+	if trace.Steps[3].Reason == "" {
+		t.Errorf("Pruned nodes expect a reason")
+	}
+}
+
+func TestExplainRejectAndSkip(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 1)
+	near := &TestPhysicalObject{1, 1, 1, 1}
+	far := &TestPhysicalObject{5, 5, 1, 1}
+	removed := &TestPhysicalObject{1, 2, 1, 1}
+	qt.UpdateTree(listOf(near, far, removed))
+	qt.MarkRemoved(removed)
+
+	trace := qt.Explain(&quadtree.Bounds{0, 0, 3, 3})
+	kinds := make(map[quadtree.TraceKind]int)
+	for _, step := range trace.Steps {
+		kinds[step.Kind] += 1
+	}
+	if kinds[quadtree.TraceAccept] != 1 || kinds[quadtree.TraceReject] != 1 || kinds[quadtree.TraceSkip] != 1 {
+		t.Errorf("Explain expects one accepted, one rejected and one skipped object, got:\n%v", trace)
+	}
+	text := trace.String()
+	if !strings.Contains(text, "reject root") || !strings.Contains(text, "skip root") || !strings.Contains(text, "removed") {
+		t.Errorf("Trace expects to render its decisions, got:\n%s", text)
+	}
+}
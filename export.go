@@ -0,0 +1,78 @@
+package quadtree
+
+// FlatNode is a node of the tree exported by ExportFlat, linked to other nodes by index
+type FlatNode struct {
+	MinX, MinY, MaxX, MaxY float32  // bounds children are tested against by queries, see Retrieve
+	Children               [4]int32 // indexes of the child nodes in quadrant order, -1 for none
+	FirstObject            int32    // index of the first object of the node
+	ObjectCount            int32    // number of objects of the node
+	Level                  int32    // level of the node, 0 for the root
+}
+
+// ExportFlat returns the tree as contiguous buffers, for upload to a compute shader or WASM memory.
+// Nodes are in depth first order, the root first, visiting children along the curve of the tree (see WithCurveOrder). aabbs holds MinX, MinY, MaxX, MaxY of every object,
+// grouped by node: object i of the export is aabbs[4*i : 4*i+4], and the objects of a node are
+// [FirstObject, FirstObject+ObjectCount). Objects stored in several nodes appear once per node,
+// ExportObjects returns the objects in the same order. Objects flagged by MarkRemoved are left out
+func (qt *Quadtree) ExportFlat() (nodes []FlatNode, aabbs []float32) {
+	if !qt.ready() {
+		return nil, nil
+	}
+	qt.root().exportFlat(func(node *Quadtree) int32 {
+		b := node.searchBounds()
+		nodes = append(nodes, FlatNode{
+			MinX:        float32(b.X),
+			MinY:        float32(b.Y),
+			MaxX:        float32(b.X + b.Width),
+			MaxY:        float32(b.Y + b.Height),
+			Children:    [4]int32{-1, -1, -1, -1},
+			FirstObject: int32(len(aabbs) / 4),
+			Level:       int32(node.Level),
+		})
+		index := int32(len(nodes) - 1)
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			if obj := ele.Value.(PhysicalObject); !node.buried(obj) {
+				aabbs = append(aabbs,
+					float32(obj.X()), float32(obj.Y()),
+					float32(obj.X()+obj.Width()), float32(obj.Y()+obj.Height()))
+				nodes[index].ObjectCount += 1
+			}
+		}
+		return index
+	}, func(parent int32, quadrant int, child int32) {
+		nodes[parent].Children[quadrant] = child
+	})
+	return nodes, aabbs
+}
+
+// ExportObjects returns the objects of the tree in the order of the boxes returned by ExportFlat
+func (qt *Quadtree) ExportObjects() []PhysicalObject {
+	if !qt.ready() {
+		return nil
+	}
+	var objects []PhysicalObject
+	qt.root().exportFlat(func(node *Quadtree) int32 {
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			if obj := ele.Value.(PhysicalObject); !node.buried(obj) {
+				objects = append(objects, obj)
+			}
+		}
+		return 0
+	}, func(int32, int, int32) {})
+	return objects
+}
+
+// exportFlat visits the subtree depth first: add exports a node and returns its index,
+// link records the index of a child node
+func (qt *Quadtree) exportFlat(add func(node *Quadtree) int32, link func(parent int32, quadrant int, child int32)) int32 {
+	index := add(qt)
+	if qt.m_ActiveNodes == 0 {
+		return index
+	}
+	for _, quadrant := range qt.childOrder() {
+		if qt.m_ActiveNodes&(1<<uint(quadrant)) != 0 {
+			link(index, quadrant, qt.Nodes[quadrant].exportFlat(add, link))
+		}
+	}
+	return index
+}
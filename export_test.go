@@ -0,0 +1,40 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestExportFlat(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	a := &TestPhysicalObject{1, 1, 1, 1}
+	b := &TestPhysicalObject{6, 6, 1, 1}
+	c := &TestPhysicalObject{3, 3, 2, 2}
+	for _, obj := range []*TestPhysicalObject{a, b, c} {
+		qt.Insert(obj)
+	}
+
+	nodes, aabbs := qt.ExportFlat()
+	objects := qt.ExportObjects()
+	if len(nodes) != 3 || len(aabbs) != 12 || len(objects) != 3 {
+		t.Fatalf("expects 3 nodes and 3 objects, got %d nodes, %d floats and %d objects", len(nodes), len(aabbs), len(objects))
+	}
+	root := nodes[0]
+	if root.MaxX != 8 || root.ObjectCount != 1 || objects[root.FirstObject] != c {
+		t.Errorf("expects the straddling object at the root, got %+v", root)
+	}
+	for quadrant, want := range map[int]*TestPhysicalObject{0: a, 3: b} {
+		node := nodes[root.Children[quadrant]]
+		i := node.FirstObject
+		if node.Level != 1 || node.ObjectCount != 1 || objects[i] != want {
+			t.Errorf("expects quadrant %d to hold one object, got %+v", quadrant, node)
+		}
+		if aabbs[4*i] != float32(want.x) || aabbs[4*i+3] != float32(want.y+want.height) {
+			t.Errorf("expects the box of quadrant %d to match its object, got %v", quadrant, aabbs[4*i:4*i+4])
+		}
+	}
+	if root.Children[1] != -1 || root.Children[2] != -1 {
+		t.Errorf("expects no node for empty quadrants, got %v", root.Children)
+	}
+}
@@ -0,0 +1,72 @@
+package quadtree
+
+import (
+	"container/heap"
+	"math"
+)
+
+// DistanceField samples the bounds of the tree on a grid of resolution by resolution cells, and returns
+// for every cell, indexed by row then column, the distance from its center to the closest object of the
+// tree, 0 within an object, +Inf for an empty tree. Distances are measured to the bounds of the objects.
+// Every sample is a best-first search of the tree, bounded by the distance of the previous sample
+func (qt *Quadtree) DistanceField(resolution int) [][]float64 {
+	if !qt.ready() || resolution <= 0 {
+		return nil
+	}
+	cellWidth := qt.Width / float64(resolution)
+	cellHeight := qt.Height / float64(resolution)
+	// the distance changes by at most the distance between samples, the margin absorbs rounding
+	const margin = 1 + 1e-9
+	field := make([][]float64, resolution)
+	for row := range field {
+		field[row] = make([]float64, resolution)
+		y := qt.Y + (float64(row)+0.5)*cellHeight
+		bound := math.Inf(1)
+		if row > 0 {
+			bound = (field[row-1][0] + cellHeight) * margin
+		}
+		for col := range field[row] {
+			x := qt.X + (float64(col)+0.5)*cellWidth
+			field[row][col] = qt.closestDistance(x, y, bound)
+			bound = (field[row][col] + cellWidth) * margin
+		}
+	}
+	return field
+}
+
+// closestDistance returns the distance from (x, y) to the bounds of the closest object of the subtree,
+// searching only within bound, which must be at least the distance, of the point
+func (qt *Quadtree) closestDistance(x, y, bound float64) float64 {
+	best := math.Inf(1)
+	queue := &nodeQueue{{qt, 0}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(nodeDistance)
+		if item.distance >= best || item.distance > bound {
+			break
+		}
+		node := item.node
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			obj := ele.Value.(PhysicalObject)
+			if node.buried(obj) {
+				continue
+			}
+			if d := pointBoundsDistance(x, y, boundsOf(obj)); d < best {
+				best = d
+			}
+		}
+
+		flags := node.m_ActiveNodes
+		index := 0
+		for flags > 0 {
+			if flags&1 == 1 {
+				b := node.Nodes[index].searchBounds()
+				if d := pointBoundsDistance(x, y, b); d < best && d <= bound {
+					heap.Push(queue, nodeDistance{node.Nodes[index], d})
+				}
+			}
+			flags >>= 1
+			index += 1
+		}
+	}
+	return best
+}
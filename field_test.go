@@ -0,0 +1,41 @@
+package quadtree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestDistanceField(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 4)
+	if field := qt.DistanceField(2); !math.IsInf(field[0][0], 1) {
+		t.Errorf("expects infinite distances without objects, got %v", field)
+	}
+	walls := []*TestPhysicalObject{{0, 0, 2, 2}, {6, 0, 2, 1}, {3, 6, 1, 1}}
+	for _, wall := range walls {
+		qt.Insert(wall)
+	}
+
+	field := qt.DistanceField(8)
+	if len(field) != 8 || len(field[0]) != 8 {
+		t.Fatalf("expects 8 by 8 cells, got %d rows", len(field))
+	}
+	for row := range field {
+		for col := range field[row] {
+			x, y := float64(col)+0.5, float64(row)+0.5
+			expected := math.Inf(1)
+			for _, wall := range walls {
+				dx := math.Max(math.Max(wall.x-x, 0), x-(wall.x+wall.width))
+				dy := math.Max(math.Max(wall.y-y, 0), y-(wall.y+wall.height))
+				expected = math.Min(expected, math.Hypot(dx, dy))
+			}
+			if math.Abs(field[row][col]-expected) > 1e-9 {
+				t.Errorf("cell %d, %d expects %v, got %v", row, col, expected, field[row][col])
+			}
+		}
+	}
+	if field[0][0] != 0 {
+		t.Errorf("expects 0 within an object, got %v", field[0][0])
+	}
+}
@@ -0,0 +1,32 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestFixedPoint(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 0.3, 0.3}, 1, 10, quadtree.WithFixedPoint(4))
+	qt.Insert(&TestPhysicalObject{0, 0, 0.05, 0.05})
+	qt.Insert(&TestPhysicalObject{0.2, 0.2, 0.05, 0.05})
+
+	// 0.3 is 4/16 in 48.4 fixed point, so the root splits at 2/16
+	if x, y := qt.SplitPoint(); x != 0.125 || y != 0.125 {
+		t.Errorf("expects the split point on the fixed-point grid, got (%v, %v)", x, y)
+	}
+	if qt.Nodes[0] == nil || qt.Nodes[3] == nil {
+		t.Errorf("expects objects in the top left and bottom right quadrants:\n%s", qt.String())
+	}
+
+	// 0.13 rounds down to 2/16, the split point, so the object fits the bottom right quadrant
+	straddling := &TestPhysicalObject{0.13, 0.13, 0.1, 0.1}
+	qt.Insert(straddling)
+	if qt.Nodes[3].FindObject(straddling) == nil {
+		t.Errorf("expects the object classified by its fixed-point coordinates:\n%s", qt.String())
+	}
+	// the cell holding both objects is a single unit wide, and is not split any further
+	if _, nodes := qt.Size(); nodes != 4 {
+		t.Errorf("expects 4 nodes, got %d:\n%s", nodes, qt.String())
+	}
+}
@@ -0,0 +1,156 @@
+package quadtree
+
+// FrozenQuadtree is an immutable copy of a Quadtree packed into flat arrays, without parent
+// pointers or lists. It only answers queries, and is safe for any number of concurrent readers.
+// Objects are expected not to move once frozen, queries use the bounds they had when frozen
+type FrozenQuadtree struct {
+	bounds  Bounds
+	nodes   []frozenNode     // nodes in depth first order, the root first
+	objects []PhysicalObject // objects grouped by node
+	aabbs   []Bounds         // bounds of the objects when frozen, parallel to objects
+	config  *config          // identifies objects like the tree which was frozen
+}
+
+// frozenNode is a node of a FrozenQuadtree, it holds objects [first, first+count)
+type frozenNode struct {
+	aabb     Bounds   // bounds enclosing every object of the subtree
+	first    int32    // index of the first object of the node
+	count    int32    // number of objects of the node
+	children [4]int32 // indexes of the child nodes, -1 for none
+}
+
+// Freeze returns an immutable packed copy of the tree. Objects flagged by MarkRemoved are left out,
+// objects stored in several nodes are kept once. Empty subtrees are dropped
+func (qt *Quadtree) Freeze() *FrozenQuadtree {
+	if !qt.ready() {
+		return &FrozenQuadtree{config: newConfig()}
+	}
+	root := qt.root()
+	// every object fits in the bounds of its frozen node, whatever the straddle policy of the tree
+	ft := &FrozenQuadtree{bounds: *root.Bounds, config: &config{equal: root.m_config.equal}}
+	seen := make(map[PhysicalObject]bool)
+	ft.freeze(root, seen)
+	return ft
+}
+
+// freeze appends node and its subtree, and returns its index, -1 if the subtree holds no object
+func (ft *FrozenQuadtree) freeze(node *Quadtree, seen map[PhysicalObject]bool) int32 {
+	index := int32(len(ft.nodes))
+	ft.nodes = append(ft.nodes, frozenNode{first: int32(len(ft.objects)), children: [4]int32{-1, -1, -1, -1}})
+	var aabb Bounds
+	empty := true
+	for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		obj := ele.Value.(PhysicalObject)
+		if node.buried(obj) || seen[obj] {
+			continue
+		}
+		seen[obj] = true
+		b := *boundsOf(obj)
+		ft.objects = append(ft.objects, obj)
+		ft.aabbs = append(ft.aabbs, b)
+		aabb, empty = enclose(aabb, b, empty), false
+	}
+	ft.nodes[index].count = int32(len(ft.objects)) - ft.nodes[index].first
+
+	flags := node.m_ActiveNodes
+	child := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			if sub := ft.freeze(node.Nodes[child], seen); sub >= 0 {
+				ft.nodes[index].children[child] = sub
+				aabb, empty = enclose(aabb, ft.nodes[sub].aabb, empty), false
+			}
+		}
+		flags >>= 1
+		child += 1
+	}
+
+	if empty && index > 0 {
+		ft.nodes = ft.nodes[:index]
+		return -1
+	}
+	ft.nodes[index].aabb = aabb
+	return index
+}
+
+// enclose returns the union of aabb and b, which is b alone if aabb is empty
+func enclose(aabb, b Bounds, empty bool) Bounds {
+	if empty {
+		return b
+	}
+	return aabb.Union(&b)
+}
+
+// Bounds returns the bounds of the tree which was frozen
+func (ft *FrozenQuadtree) Bounds() Bounds {
+	return ft.bounds
+}
+
+// Len returns the number of objects of the frozen tree
+func (ft *FrozenQuadtree) Len() int {
+	return len(ft.objects)
+}
+
+// Walk calls walker for every object of the frozen tree
+func (ft *FrozenQuadtree) Walk(walker func(PhysicalObject)) {
+	for _, obj := range ft.objects {
+		walker(obj)
+	}
+}
+
+// visit calls fn for every object whose frozen bounds overlap region, touching borders count as overlap
+func (ft *FrozenQuadtree) visit(region *Bounds, fn func(obj PhysicalObject, b *Bounds)) {
+	ft.visitWhere(func(b *Bounds) bool {
+		return b.Intersects(region)
+	}, func(obj PhysicalObject, b *Bounds) {
+		if region.Intersects(b) {
+			fn(obj, b)
+		}
+	})
+}
+
+// visitWhere calls fn for every object of the nodes whose bounds are accepted by filter
+func (ft *FrozenQuadtree) visitWhere(filter func(*Bounds) bool, fn func(obj PhysicalObject, b *Bounds)) {
+	if len(ft.objects) == 0 {
+		return
+	}
+	stack := []int32{0}
+	for len(stack) > 0 {
+		node := &ft.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !filter(&node.aabb) {
+			continue
+		}
+		for i := node.first; i < node.first+node.count; i += 1 {
+			fn(ft.objects[i], &ft.aabbs[i])
+		}
+		for _, child := range node.children {
+			if child >= 0 {
+				stack = append(stack, child)
+			}
+		}
+	}
+}
+
+// Retrieve returns the objects whose bounds overlap region, touching borders count as overlap
+func (ft *FrozenQuadtree) Retrieve(region *Bounds) IntersectedObjects {
+	var objects []PhysicalObject
+	ft.visit(region, func(obj PhysicalObject, b *Bounds) {
+		objects = append(objects, obj)
+	})
+	return objects
+}
+
+// GetIntersectedObjects returns the objects intersecting the target, the target itself left out.
+// Nodes are pruned by the reach of Intersect, as in the live tree, so that both give the same results
+func (ft *FrozenQuadtree) GetIntersectedObjects(target PhysicalObject) IntersectedObjects {
+	var objects []PhysicalObject
+	ft.visitWhere(func(b *Bounds) bool {
+		return ft.config.reaches(b, target)
+	}, func(obj PhysicalObject, b *Bounds) {
+		if !ft.config.same(obj, target) && Intersect(target, &flatObject{b.X, b.Y, b.Width, b.Height}) {
+			objects = append(objects, obj)
+		}
+	})
+	return objects
+}
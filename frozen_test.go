@@ -0,0 +1,67 @@
+package quadtree_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestFreeze(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	a := &TestPhysicalObject{1, 1, 1, 1}
+	b := &TestPhysicalObject{1.5, 1.5, 1, 1}
+	c := &TestPhysicalObject{6, 6, 1, 1}
+	d := &TestPhysicalObject{3, 3, 2, 2}
+	for _, obj := range []*TestPhysicalObject{a, b, c, d} {
+		qt.Insert(obj)
+	}
+	qt.MarkRemoved(d)
+	ft := qt.Freeze()
+
+	if ft.Len() != 3 {
+		t.Errorf("expects 3 frozen objects, got %d", ft.Len())
+	}
+	if got := ft.Retrieve(&quadtree.Bounds{5, 5, 3, 3}); len(got) != 1 || got[0] != c {
+		t.Errorf("expects only c in the bottom right corner, got %v", got)
+	}
+	if got := ft.GetIntersectedObjects(a); len(got) != 1 || got[0] != b {
+		t.Errorf("expects a to intersect b only, got %v", got)
+	}
+
+	qt.Remove(c)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i += 1 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := ft.Retrieve(&quadtree.Bounds{0, 0, 8, 8}); len(got) != 3 {
+				t.Errorf("expects the frozen tree unchanged by later mutations, got %d objects", len(got))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestFreezeEmpty(t *testing.T) {
+	var qt *quadtree.Quadtree
+	if got := qt.Freeze().Retrieve(&quadtree.Bounds{0, 0, 8, 8}); len(got) != 0 {
+		t.Errorf("expects no object, got %v", got)
+	}
+}
+
+func TestFrozenIntersectionReach(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{-8, -8, 16, 16}, 4, 4)
+	target := &TestPhysicalObject{0, 0, 2, 2}
+	other := &TestPhysicalObject{-1.4, 0, 1, 1}
+	qt.Insert(target)
+	qt.Insert(other)
+	live := qt.GetIntersectedObjects(target)
+	frozen := qt.Freeze().GetIntersectedObjects(target)
+	if len(live) != 1 || !sameObjects(frozen, live...) {
+		t.Errorf("expects the frozen tree to agree with the live tree %v, got %v", live, frozen)
+	}
+	if found := qt.Freeze().GetIntersectedObjects(other); !sameObjects(found, target) {
+		t.Errorf("expects frozen intersections to be symmetric, got %v", found)
+	}
+}
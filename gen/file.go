@@ -0,0 +1,88 @@
+package gen
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// NewObject returns a static object
+func NewObject(x, y, width, height float64) *Object {
+	return &Object{x: x, y: y, width: width, height: height}
+}
+
+// NewMover returns an object moving at velocity (vx, vy), bouncing off the borders of bounds
+func NewMover(x, y, width, height, vx, vy float64, bounds quadtree.Bounds) *Mover {
+	return &Mover{Object: Object{x: x, y: y, width: width, height: height}, VX: vx, VY: vy, bounds: bounds}
+}
+
+// WriteScene writes objects in the text scene format read by ReadScene:
+//
+//	bounds X Y WIDTH HEIGHT
+//	object X Y WIDTH HEIGHT
+//	mover X Y WIDTH HEIGHT VX VY
+//
+// Objects other than *Object and *Mover are written as static objects at their current position
+func WriteScene(w io.Writer, bounds quadtree.Bounds, objects []quadtree.PhysicalObject) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "bounds %s\n", formatFloats(bounds.X, bounds.Y, bounds.Width, bounds.Height))
+	for _, obj := range objects {
+		if m, ok := obj.(*Mover); ok {
+			fmt.Fprintf(bw, "mover %s\n", formatFloats(m.x, m.y, m.width, m.height, m.VX, m.VY))
+		} else {
+			fmt.Fprintf(bw, "object %s\n", formatFloats(obj.X(), obj.Y(), obj.Width(), obj.Height()))
+		}
+	}
+	return bw.Flush()
+}
+
+func formatFloats(values ...float64) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ReadScene reads a scene written by WriteScene, empty lines and lines starting with '#' are ignored
+func ReadScene(r io.Reader) (quadtree.Bounds, []quadtree.PhysicalObject, error) {
+	bounds := quadtree.Bounds{X: 0, Y: 0, Width: 1000, Height: 1000}
+	var objects []quadtree.PhysicalObject
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber += 1 {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		values := make([]float64, len(fields)-1)
+		for i, field := range fields[1:] {
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return bounds, nil, fmt.Errorf("gen: line %d: %v", lineNumber, err)
+			}
+			values[i] = v
+		}
+
+		expected := map[string]int{"bounds": 4, "object": 4, "mover": 6}[fields[0]]
+		if expected == 0 {
+			return bounds, nil, fmt.Errorf("gen: line %d: unknown record %q", lineNumber, fields[0])
+		}
+		if len(values) != expected {
+			return bounds, nil, fmt.Errorf("gen: line %d: %s expects %d values, got %d", lineNumber, fields[0], expected, len(values))
+		}
+		switch fields[0] {
+		case "bounds":
+			bounds = quadtree.Bounds{X: values[0], Y: values[1], Width: values[2], Height: values[3]}
+		case "object":
+			objects = append(objects, NewObject(values[0], values[1], values[2], values[3]))
+		case "mover":
+			objects = append(objects, NewMover(values[0], values[1], values[2], values[3], values[4], values[5], bounds))
+		}
+	}
+	return bounds, objects, scanner.Err()
+}
@@ -0,0 +1,141 @@
+// Package gen generates reproducible scenes of static and moving objects, for benchmarks,
+// fuzzing corpora and bug reports against the quadtree package
+package gen
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// Option configures the scene generated by Scene
+type Option func(*config)
+
+type config struct {
+	bounds             quadtree.Bounds
+	movingRatio        float64
+	minSize, maxSize   float64
+	minSpeed, maxSpeed float64
+}
+
+// WithBounds sets the area objects are generated in and move within, defaults to (0, 0, 1000, 1000)
+func WithBounds(bounds quadtree.Bounds) Option {
+	return func(c *config) {
+		c.bounds = bounds
+	}
+}
+
+// WithMovingRatio sets the probability of a generated object to be moving, defaults to 0.5
+func WithMovingRatio(ratio float64) Option {
+	return func(c *config) {
+		c.movingRatio = ratio
+	}
+}
+
+// WithSizeRange sets the range of widths and heights of the objects, defaults to [1, 10)
+func WithSizeRange(min, max float64) Option {
+	return func(c *config) {
+		c.minSize, c.maxSize = min, max
+	}
+}
+
+// WithSpeedRange sets the range of speeds, in units per second along each axis, of moving objects, defaults to [1, 50)
+func WithSpeedRange(min, max float64) Option {
+	return func(c *config) {
+		c.minSpeed, c.maxSpeed = min, max
+	}
+}
+
+// Object is a static generated object
+type Object struct {
+	x, y, width, height float64
+}
+
+func (o *Object) X() float64      { return o.x }
+func (o *Object) Y() float64      { return o.y }
+func (o *Object) Width() float64  { return o.width }
+func (o *Object) Height() float64 { return o.height }
+
+// Update never moves a static object
+func (o *Object) Update(time.Duration) bool { return false }
+
+// Mover is a generated object moving at constant velocity, bouncing off the borders of the scene
+type Mover struct {
+	Object
+	VX, VY float64 // velocity in units per second
+	bounds quadtree.Bounds
+}
+
+// Update moves the object according to its velocity, it reports whether the object moved
+func (m *Mover) Update(delta time.Duration) bool {
+	if delta == 0 || (m.VX == 0 && m.VY == 0) {
+		return false
+	}
+	seconds := delta.Seconds()
+	m.x, m.VX = bounce(m.x+m.VX*seconds, m.VX, m.bounds.X, m.bounds.X+m.bounds.Width-m.width)
+	m.y, m.VY = bounce(m.y+m.VY*seconds, m.VY, m.bounds.Y, m.bounds.Y+m.bounds.Height-m.height)
+	return true
+}
+
+// bounce reflects pos back into [min, max], reversing the velocity when it hits a border
+func bounce(pos, velocity, min, max float64) (float64, float64) {
+	if max <= min {
+		return min, velocity
+	}
+	for pos < min || pos > max {
+		if pos < min {
+			pos = 2*min - pos
+		} else {
+			pos = 2*max - pos
+		}
+		velocity = -velocity
+	}
+	return pos, velocity
+}
+
+// Scene generates n objects from the seed, the same seed and options always produce the same scene.
+// Objects are *Object when static and *Mover when moving
+func Scene(seed int64, n int, opts ...Option) []quadtree.PhysicalObject {
+	c := &config{
+		bounds:      quadtree.Bounds{X: 0, Y: 0, Width: 1000, Height: 1000},
+		movingRatio: 0.5,
+		minSize:     1,
+		maxSize:     10,
+		minSpeed:    1,
+		maxSpeed:    50,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	between := func(min, max float64) float64 {
+		return min + rng.Float64()*(max-min)
+	}
+	objects := make([]quadtree.PhysicalObject, 0, n)
+	for i := 0; i < n; i += 1 {
+		width := between(c.minSize, c.maxSize)
+		height := between(c.minSize, c.maxSize)
+		obj := Object{
+			x:      between(c.bounds.X, c.bounds.X+c.bounds.Width-width),
+			y:      between(c.bounds.Y, c.bounds.Y+c.bounds.Height-height),
+			width:  width,
+			height: height,
+		}
+		if rng.Float64() >= c.movingRatio {
+			objects = append(objects, &obj)
+			continue
+		}
+
+		speed := func() float64 {
+			v := between(c.minSpeed, c.maxSpeed)
+			if rng.Intn(2) == 0 {
+				v = -v
+			}
+			return v
+		}
+		objects = append(objects, &Mover{Object: obj, VX: speed(), VY: speed(), bounds: c.bounds})
+	}
+	return objects
+}
@@ -0,0 +1,86 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestSceneReproducible(t *testing.T) {
+	one := Scene(42, 100)
+	another := Scene(42, 100)
+	different := Scene(43, 100)
+
+	if len(one) != 100 {
+		t.Fatalf("expects 100 objects, got %d", len(one))
+	}
+	same := true
+	for i := range one {
+		a, b, c := one[i], another[i], different[i]
+		if a.X() != b.X() || a.Y() != b.Y() || a.Width() != b.Width() || a.Height() != b.Height() {
+			t.Fatalf("object %d differs between scenes generated from the same seed", i)
+		}
+		if a.X() != c.X() || a.Y() != c.Y() {
+			same = false
+		}
+	}
+	if same {
+		t.Errorf("scenes generated from different seeds are identical")
+	}
+}
+
+func TestSceneBounds(t *testing.T) {
+	bounds := quadtree.Bounds{X: -50, Y: -50, Width: 100, Height: 100}
+	objects := Scene(7, 200, WithBounds(bounds), WithMovingRatio(1), WithSizeRange(1, 5), WithSpeedRange(10, 100))
+	for _, obj := range objects {
+		if _, ok := obj.(*Mover); !ok {
+			t.Fatalf("expects every object to be moving, got %T", obj)
+		}
+	}
+	for tick := 0; tick < 100; tick += 1 {
+		for i, obj := range objects {
+			if !obj.Update(100 * time.Millisecond) {
+				t.Fatalf("moving object %d does not report moving", i)
+			}
+			if !bounds.Contains(obj) {
+				t.Fatalf("object %d at (%v, %v) leaves the scene after %d ticks", i, obj.X(), obj.Y(), tick)
+			}
+		}
+	}
+
+	for _, obj := range Scene(7, 50, WithMovingRatio(0)) {
+		if obj.Update(time.Second) {
+			t.Fatalf("static object reports moving")
+		}
+	}
+}
+
+func TestSceneFile(t *testing.T) {
+	bounds := quadtree.Bounds{X: 0, Y: 0, Width: 100, Height: 100}
+	objects := Scene(3, 20, WithBounds(bounds))
+
+	var buf bytes.Buffer
+	if err := WriteScene(&buf, bounds, objects); err != nil {
+		t.Fatal(err)
+	}
+	readBounds, read, err := ReadScene(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readBounds != bounds || len(read) != len(objects) {
+		t.Fatalf("read scene has bounds %+v and %d objects, expects %+v and %d", readBounds, len(read), bounds, len(objects))
+	}
+	for i := range objects {
+		if fmt.Sprintf("%#v", objects[i]) != fmt.Sprintf("%#v", read[i]) {
+			t.Errorf("object %d read as %#v, expects %#v", i, read[i], objects[i])
+		}
+	}
+
+	if _, _, err := ReadScene(strings.NewReader("mover 1 2 3 4")); err == nil {
+		t.Errorf("expects an error for a truncated mover record")
+	}
+}
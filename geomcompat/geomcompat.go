@@ -0,0 +1,69 @@
+// Package geomcompat converts the bounding boxes and points of GIS geometry libraries such as
+// go-geom and orb into quadtree Bounds, and wraps geometries as PhysicalObjects. It relies on
+// the method sets of those types rather than importing them, so it adds no dependency
+package geomcompat
+
+import (
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// MinMaxer is a bounding box queried by dimension, as *geom.Bounds of go-geom
+type MinMaxer interface {
+	Min(dim int) float64
+	Max(dim int) float64
+}
+
+// Edger is a bounding box queried by edge, as orb.Bound
+type Edger interface {
+	Left() float64
+	Right() float64
+	Bottom() float64
+	Top() float64
+}
+
+// XYer is a point, as *geom.Point of go-geom
+type XYer interface {
+	X() float64
+	Y() float64
+}
+
+// FromMinMax converts a go-geom style bounding box into Bounds
+func FromMinMax(b MinMaxer) *quadtree.Bounds {
+	return &quadtree.Bounds{X: b.Min(0), Y: b.Min(1), Width: b.Max(0) - b.Min(0), Height: b.Max(1) - b.Min(1)}
+}
+
+// FromEdges converts an orb style bounding box into Bounds, Bottom being the smaller Y
+func FromEdges(b Edger) *quadtree.Bounds {
+	return &quadtree.Bounds{X: b.Left(), Y: b.Bottom(), Width: b.Right() - b.Left(), Height: b.Top() - b.Bottom()}
+}
+
+// FromPoint converts a point given as [2]float64, such as orb.Point, into empty Bounds at the point
+func FromPoint(p [2]float64) *quadtree.Bounds {
+	return &quadtree.Bounds{X: p[0], Y: p[1]}
+}
+
+// FromXY converts a go-geom style point into empty Bounds at the point
+func FromXY(p XYer) *quadtree.Bounds {
+	return &quadtree.Bounds{X: p.X(), Y: p.Y()}
+}
+
+// Object wraps a motionless geometry as a PhysicalObject occupying its bounding box
+type Object struct {
+	Geometry interface{}
+	bounds   quadtree.Bounds
+}
+
+// NewObject wraps geometry, whose bounding box is bounds
+func NewObject(geometry interface{}, bounds *quadtree.Bounds) *Object {
+	return &Object{Geometry: geometry, bounds: *bounds}
+}
+
+func (o *Object) X() float64      { return o.bounds.X }
+func (o *Object) Y() float64      { return o.bounds.Y }
+func (o *Object) Width() float64  { return o.bounds.Width }
+func (o *Object) Height() float64 { return o.bounds.Height }
+
+// Update never moves a geometry
+func (o *Object) Update(time.Duration) bool { return false }
@@ -0,0 +1,53 @@
+package geomcompat
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// geomBounds mimics *geom.Bounds of go-geom
+type geomBounds struct {
+	min, max []float64
+}
+
+func (b *geomBounds) Min(dim int) float64 { return b.min[dim] }
+func (b *geomBounds) Max(dim int) float64 { return b.max[dim] }
+
+// point and bound mimic orb.Point and orb.Bound
+type point [2]float64
+
+type bound struct {
+	Min, Max point
+}
+
+func (b bound) Left() float64   { return b.Min[0] }
+func (b bound) Right() float64  { return b.Max[0] }
+func (b bound) Bottom() float64 { return b.Min[1] }
+func (b bound) Top() float64    { return b.Max[1] }
+
+func TestConversions(t *testing.T) {
+	expected := quadtree.Bounds{X: 1, Y: 2, Width: 3, Height: 4}
+	if b := FromMinMax(&geomBounds{min: []float64{1, 2}, max: []float64{4, 6}}); *b != expected {
+		t.Errorf("FromMinMax expects %v, got %v", expected, *b)
+	}
+	if b := FromEdges(bound{point{1, 2}, point{4, 6}}); *b != expected {
+		t.Errorf("FromEdges expects %v, got %v", expected, *b)
+	}
+	if b := FromPoint(point{1, 2}); *b != (quadtree.Bounds{X: 1, Y: 2, Width: 0, Height: 0}) {
+		t.Errorf("FromPoint expects an empty box at (1, 2), got %v", *b)
+	}
+}
+
+func TestObject(t *testing.T) {
+	g := bound{point{1, 1}, point{2, 2}}
+	obj := NewObject(g, FromEdges(g))
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{X: 0, Y: 0, Width: 8, Height: 8}, 1, 10)
+	qt.Insert(obj)
+	qt.Insert(NewObject(nil, &quadtree.Bounds{X: 6, Y: 6, Width: 1, Height: 1}))
+
+	found := qt.Retrieve(&quadtree.Bounds{X: 0, Y: 0, Width: 3, Height: 3})
+	if len(found) != 1 || found[0].(*Object).Geometry != g {
+		t.Errorf("expects the wrapped geometry to be retrieved, got %v", found)
+	}
+}
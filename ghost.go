@@ -0,0 +1,33 @@
+package quadtree
+
+// QueryGhost returns the objects intersecting a probe with bounds b which is not in the tree, as
+// GetIntersectedObjects would if the probe were inserted: the probe is routed down the tree like an
+// Insert, then tested against the objects of the nodes along the way and of the subtree it lands in
+func (qt *Quadtree) QueryGhost(b Bounds) IntersectedObjects {
+	if !qt.ready() {
+		return nil
+	}
+	probe := b.MinMax()
+	root := qt.root()
+	if root.m_config.straddlePolicy != StraddleKeepAtParent || root.m_config.tolerance() > 0 {
+		// objects of sibling subtrees may overlap the probe, search the whole tree
+		return root.intersecting(&probe, nil)
+	}
+
+	var objects []PhysicalObject
+	node := root
+	for {
+		index := node.quadrantIndex(&probe)
+		if index == -1 || node.m_ActiveNodes&(1<<uint(index)) == 0 {
+			break
+		}
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			obj := ele.Value.(PhysicalObject)
+			if !node.buried(obj) && Intersect(&probe, obj) {
+				objects = append(objects, obj)
+			}
+		}
+		node = node.Nodes[index]
+	}
+	return node.GetIntersectedObjectsRaw(&probe, objects)
+}
@@ -0,0 +1,42 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestQueryGhost(t *testing.T) {
+	for _, opts := range [][]quadtree.Option{nil, {quadtree.WithStraddlePolicy(quadtree.StraddleLoose)}} {
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 16, 16}, 1, 4, opts...)
+		straddling := &TestPhysicalObject{7, 7, 2, 2}
+		near := &TestPhysicalObject{1, 1, 1, 1}
+		far := &TestPhysicalObject{12, 12, 1, 1}
+		for _, obj := range []*TestPhysicalObject{straddling, near, far, {0, 3, 1, 1}, {3, 0, 1, 1}} {
+			qt.Insert(obj)
+		}
+
+		probe := quadtree.Bounds{1.5, 1.5, 1, 1}
+		if found := qt.QueryGhost(probe); !sameObjects(found, near) {
+			t.Errorf("expects the probe to intersect the near object, got %v", found)
+		}
+		// the same probe inserted as an object gives the same result
+		inserted := &TestPhysicalObject{1.5, 1.5, 1, 1}
+		qt.Insert(inserted)
+		if found := qt.GetIntersectedObjects(inserted); !sameObjects(found, near) {
+			t.Errorf("expects GetIntersectedObjects to agree, got %v", found)
+		}
+		qt.Remove(inserted)
+
+		if found := qt.QueryGhost(quadtree.Bounds{6.5, 6.5, 1, 1}); !sameObjects(found, straddling) {
+			t.Errorf("expects the probe to intersect the straddling object, got %v", found)
+		}
+		if found := qt.QueryGhost(quadtree.Bounds{20, 20, 1, 1}); len(found) != 0 {
+			t.Errorf("expects nothing outside of the tree, got %v", found)
+		}
+		qt.MarkRemoved(near)
+		if found := qt.QueryGhost(probe); len(found) != 0 {
+			t.Errorf("expects removed objects to be skipped, got %v", found)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package quadtree
+
+import (
+	"math"
+)
+
+// Massive is implemented by objects with a mass, objects which are not Massive weigh 1
+type Massive interface {
+	Mass() float64
+}
+
+// body is an object taking part in a force computation, located at its center
+type body struct {
+	obj     PhysicalObject
+	x, y, m float64
+}
+
+// massOf returns the mass of the object
+func massOf(obj PhysicalObject) float64 {
+	if m, ok := obj.(Massive); ok {
+		return m.Mass()
+	}
+	return 1
+}
+
+// gravity holds the aggregates of a Barnes-Hut force computation
+type gravity struct {
+	bodies map[*Quadtree][]body // objects of each node, objects stored in several nodes belong to the first one
+	mass   map[*Quadtree]body   // total mass of each subtree, at its center of mass
+	seen   map[PhysicalObject]bool
+}
+
+// aggregate computes the mass and center of mass of the subtree
+func (g *gravity) aggregate(node *Quadtree) body {
+	var total body
+	add := func(b body) {
+		total.x += b.x * b.m
+		total.y += b.y * b.m
+		total.m += b.m
+	}
+	for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		obj := ele.Value.(PhysicalObject)
+		if node.buried(obj) || g.seen[obj] {
+			continue
+		}
+		g.seen[obj] = true
+		cx, cy := center(obj)
+		b := body{obj, cx, cy, massOf(obj)}
+		g.bodies[node] = append(g.bodies[node], b)
+		add(b)
+	}
+	flags := node.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			add(g.aggregate(node.Nodes[index]))
+		}
+		flags >>= 1
+		index += 1
+	}
+	if total.m != 0 {
+		total.x /= total.m
+		total.y /= total.m
+	}
+	g.mass[node] = total
+	return total
+}
+
+// force returns the force pulling b toward the subtree, approximating the subtree by its center
+// of mass when its size seen from b is below theta
+func (g *gravity) force(node *Quadtree, b body, constant, theta float64) (fx, fy float64) {
+	pull := func(other body) {
+		dx, dy := other.x-b.x, other.y-b.y
+		d := math.Hypot(dx, dy)
+		if d == 0 {
+			return
+		}
+		f := constant * b.m * other.m / (d * d)
+		fx += f * dx / d
+		fy += f * dy / d
+	}
+
+	aggregate := g.mass[node]
+	if aggregate.m == 0 {
+		return 0, 0
+	}
+	bounds := node.searchBounds()
+	size := math.Max(bounds.Width, bounds.Height)
+	d := math.Hypot(aggregate.x-b.x, aggregate.y-b.y)
+	if node.m_parent != nil && !bounds.ContainsPoint(b.x, b.y) && size < theta*d {
+		pull(aggregate)
+		return fx, fy
+	}
+
+	for _, other := range g.bodies[node] {
+		if other.obj != b.obj {
+			pull(other)
+		}
+	}
+	flags := node.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			cx, cy := g.force(node.Nodes[index], b, constant, theta)
+			fx += cx
+			fy += cy
+		}
+		flags >>= 1
+		index += 1
+	}
+	return fx, fy
+}
+
+// AccumulateForces computes the gravitational pull of every object on every other one with the
+// Barnes-Hut approximation, and calls apply once per object with the total force on it.
+// Objects are located at their center, and weigh their Mass if they are Massive, 1 otherwise.
+// The force between two objects is g * m1 * m2 / d², a node is approximated by its center of mass
+// when its size divided by its distance is below theta (0 computes every pair exactly, 0.5 is common)
+func (qt *Quadtree) AccumulateForces(g float64, theta float64, apply func(obj PhysicalObject, fx, fy float64)) {
+	if !qt.ready() {
+		return
+	}
+	grav := &gravity{
+		bodies: make(map[*Quadtree][]body),
+		mass:   make(map[*Quadtree]body),
+		seen:   make(map[PhysicalObject]bool),
+	}
+	root := qt.root()
+	grav.aggregate(root)
+
+	var bodies []body
+	for _, node := range grav.order(root, nil) {
+		bodies = append(bodies, grav.bodies[node]...)
+	}
+	for _, b := range bodies {
+		fx, fy := grav.force(root, b, g, theta)
+		apply(b.obj, fx, fy)
+	}
+}
+
+// order returns the nodes of the subtree in depth first order, so that forces are applied deterministically
+func (g *gravity) order(node *Quadtree, nodes []*Quadtree) []*Quadtree {
+	nodes = append(nodes, node)
+	flags := node.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			nodes = g.order(node.Nodes[index], nodes)
+		}
+		flags >>= 1
+		index += 1
+	}
+	return nodes
+}
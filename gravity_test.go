@@ -0,0 +1,82 @@
+package quadtree_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+type heavyObject struct {
+	TestPhysicalObject
+	mass float64
+}
+
+func (o *heavyObject) Mass() float64 { return o.mass }
+
+func TestAccumulateForcesTwoBodies(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 100, 100}, 1, 10)
+	light := &TestPhysicalObject{9, 9, 2, 2}
+	heavy := &heavyObject{TestPhysicalObject{49, 9, 2, 2}, 4}
+	qt.Insert(light)
+	qt.Insert(heavy)
+
+	forces := map[quadtree.PhysicalObject][2]float64{}
+	qt.AccumulateForces(100, 0.5, func(obj quadtree.PhysicalObject, fx, fy float64) {
+		forces[obj] = [2]float64{fx, fy}
+	})
+	// 100 * 1 * 4 / 40²
+	if f := forces[light]; f != [2]float64{0.25, 0} {
+		t.Errorf("expects the light object pulled right, got %v", f)
+	}
+	if f := forces[heavy]; f != [2]float64{-0.25, 0} {
+		t.Errorf("expects the heavy object pulled left, got %v", f)
+	}
+}
+
+func TestAccumulateForcesApproximation(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 1000, 1000}, 4, 8)
+	var objects []*TestPhysicalObject
+	for i := 0; i < 300; i += 1 {
+		obj := &TestPhysicalObject{rng.Float64() * 990, rng.Float64() * 990, 2, 2}
+		objects = append(objects, obj)
+		qt.Insert(obj)
+	}
+
+	exact := map[quadtree.PhysicalObject][2]float64{}
+	qt.AccumulateForces(1, 0, func(obj quadtree.PhysicalObject, fx, fy float64) {
+		exact[obj] = [2]float64{fx, fy}
+	})
+	for _, one := range objects[:10] {
+		var fx, fy float64
+		for _, other := range objects {
+			dx, dy := other.x-one.x, other.y-one.y
+			if d := math.Hypot(dx, dy); d > 0 {
+				fx += dx / (d * d * d)
+				fy += dy / (d * d * d)
+			}
+		}
+		if f := exact[one]; math.Abs(f[0]-fx) > 1e-12 || math.Abs(f[1]-fy) > 1e-12 {
+			t.Errorf("expects theta 0 to compute every pair exactly, got %v instead of (%v, %v)", f, fx, fy)
+		}
+	}
+
+	// the net force nearly cancels out for some objects, errors are measured against the mean force
+	mean := 0.0
+	for _, f := range exact {
+		mean += math.Hypot(f[0], f[1]) / float64(len(exact))
+	}
+	count := 0
+	qt.AccumulateForces(1, 0.5, func(obj quadtree.PhysicalObject, fx, fy float64) {
+		count += 1
+		want := exact[obj]
+		if math.Hypot(fx-want[0], fy-want[1]) > 0.1*mean {
+			t.Errorf("expects an approximation within 10%% of the mean force, got (%v, %v) instead of %v", fx, fy, want)
+		}
+	})
+	if count != len(objects) {
+		t.Errorf("expects a force applied to each of the %d objects, got %d", len(objects), count)
+	}
+}
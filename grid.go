@@ -0,0 +1,44 @@
+package quadtree
+
+import (
+	"time"
+)
+
+// WithIntegerGrid makes nodes split at the integer point rounding down their midpoint, so that
+// a tree with integer bounds only has cells with integer borders and objects on integer
+// coordinates are classified exactly. Cells of a single unit are never split
+func WithIntegerGrid() Option {
+	return func(c *config) {
+		c.integerGrid = true
+	}
+}
+
+// unitCell tells whether current node is a cell of an integer or fixed-point grid too small to be split
+func (qt *Quadtree) unitCell() bool {
+	c := qt.m_config
+	if c.fixedPoint {
+		left, top, right, bottom := c.fixedRect(qt.X, qt.Y, qt.Width, qt.Height)
+		if right-left <= 1 && bottom-top <= 1 {
+			return true
+		}
+	}
+	return c.integerGrid && qt.Width <= 1 && qt.Height <= 1
+}
+
+// Tile is a motionless object covering whole cells of an integer grid
+type Tile struct {
+	Col, Row, Cols, Rows int
+}
+
+func (t *Tile) X() float64      { return float64(t.Col) }
+func (t *Tile) Y() float64      { return float64(t.Row) }
+func (t *Tile) Width() float64  { return float64(t.Cols) }
+func (t *Tile) Height() float64 { return float64(t.Rows) }
+
+// Update never moves a tile
+func (t *Tile) Update(time.Duration) bool { return false }
+
+// IntBounds returns the bounds covering cols by rows cells from the cell at (col, row)
+func IntBounds(col, row, cols, rows int) *Bounds {
+	return &Bounds{float64(col), float64(row), float64(cols), float64(rows)}
+}
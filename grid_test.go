@@ -0,0 +1,28 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/quadtreetest"
+)
+
+func TestIntegerGrid(t *testing.T) {
+	tile := &quadtree.Tile{Col: 2, Row: 0, Cols: 1, Rows: 1}
+	qt := quadtree.NewQuadtree(quadtree.IntBounds(0, 0, 5, 5), 1, 10, quadtree.WithIntegerGrid())
+	qt.Insert(&quadtree.Tile{Col: 0, Row: 0, Cols: 1, Rows: 1})
+	qt.Insert(tile)
+
+	if x, y := qt.SplitPoint(); x != 2 || y != 2 {
+		t.Errorf("expects the root to split at (2, 2), got (%v, %v)", x, y)
+	}
+	if node := qt.FindObject(tile); node == nil || node.Level == 0 || node.X != 2 {
+		t.Errorf("tile on the split line expects to descend into the top right quadrant:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+	stacked := quadtree.NewQuadtree(quadtree.IntBounds(0, 0, 1, 1), 0, 10, quadtree.WithIntegerGrid())
+	stacked.Insert(&quadtree.Tile{Col: 0, Row: 0, Cols: 1, Rows: 1})
+	stacked.Insert(&quadtree.Tile{Col: 0, Row: 0, Cols: 1, Rows: 1})
+	if stacked.Nodes != [4]*quadtree.Quadtree{} {
+		t.Errorf("a unit cell expects not to split")
+	}
+}
@@ -0,0 +1,238 @@
+package quadtree
+
+import (
+	"time"
+)
+
+// Handle identifies an object inserted with InsertHandle, it indexes the flat bounds of the tree
+type Handle int
+
+// HandleSet is a set of handles stored as a bitset, indexed by handle
+type HandleSet []uint64
+
+// Add adds the handles to the set, negative handles are ignored
+func (s *HandleSet) Add(handles ...Handle) {
+	for _, h := range handles {
+		if h < 0 {
+			continue
+		}
+		word := int(h) / 64
+		for len(*s) <= word {
+			*s = append(*s, 0)
+		}
+		(*s)[word] |= 1 << uint(h%64)
+	}
+}
+
+// Contains tells whether the handle is in the set
+func (s HandleSet) Contains(h Handle) bool {
+	word := int(h) / 64
+	return h >= 0 && word < len(s) && s[word]&(1<<uint(h%64)) != 0
+}
+
+// handleStore keeps the bounds of handled objects in a flat slice, four floats per handle
+type handleStore struct {
+	bounds  []float64
+	objects []PhysicalObject
+	handles map[PhysicalObject]Handle
+	free    []Handle
+}
+
+// InsertHandle inserts the object like Insert, and records its bounds in the flat bounds of the tree,
+// which Retrieve and Query read instead of calling the object. Objects inserted this way are removed
+// with RemoveHandle, and MoveHandle tells the tree they moved outside of Update. The handle is freed
+// as soon as the object leaves the tree, however it leaves
+func (qt *Quadtree) InsertHandle(obj PhysicalObject) (Handle, error) {
+	if !qt.ready() {
+		return -1, ErrNilQuadtree
+	}
+	if err := qt.Insert(obj); err != nil {
+		return -1, err
+	}
+	store := qt.m_config.handles
+	if store == nil {
+		n := qt.m_config.expectedObjects
+		store = &handleStore{
+			bounds:  make([]float64, 0, 4*n),
+			objects: make([]PhysicalObject, 0, n),
+			handles: make(map[PhysicalObject]Handle, n),
+		}
+		qt.m_config.handles = store
+	}
+	var h Handle
+	if n := len(store.free); n > 0 {
+		h = store.free[n-1]
+		store.free = store.free[:n-1]
+		store.objects[h] = obj
+	} else {
+		h = Handle(len(store.objects))
+		store.objects = append(store.objects, obj)
+		store.bounds = append(store.bounds, 0, 0, 0, 0)
+	}
+	store.handles[obj] = h
+	store.record(h, obj)
+	return h, nil
+}
+
+// record copies the bounds of the object into the slot of the handle
+func (store *handleStore) record(h Handle, obj PhysicalObject) {
+	slot := store.bounds[4*h : 4*h+4]
+	slot[0], slot[1], slot[2], slot[3] = obj.X(), obj.Y(), obj.Width(), obj.Height()
+}
+
+// valid tells whether h is a live handle of the store
+func (store *handleStore) valid(h Handle) bool {
+	return store != nil && h >= 0 && int(h) < len(store.objects) && store.objects[h] != nil
+}
+
+// HandleObject returns the object of the handle, nil if the handle is not live
+func (qt *Quadtree) HandleObject(h Handle) PhysicalObject {
+	if !qt.ready() {
+		return nil
+	}
+	if store := qt.m_config.handles; store.valid(h) {
+		return store.objects[h]
+	}
+	return nil
+}
+
+// HandleBounds returns the bounds recorded for the handle, as of the last Update or MoveHandle
+func (qt *Quadtree) HandleBounds(h Handle) (Bounds, bool) {
+	if !qt.ready() {
+		return Bounds{}, false
+	}
+	store := qt.m_config.handles
+	if !store.valid(h) {
+		return Bounds{}, false
+	}
+	slot := store.bounds[4*h : 4*h+4]
+	return Bounds{slot[0], slot[1], slot[2], slot[3]}, true
+}
+
+// FlatBounds returns the bounds of every handle as consecutive x, y, width and height values,
+// the bounds of handle h start at index 4*h. Slots of removed handles are stale until reused.
+// The slice is owned by the tree and only valid until the next mutation
+func (qt *Quadtree) FlatBounds() []float64 {
+	if !qt.ready() {
+		return nil
+	}
+	if store := qt.m_config.handles; store != nil {
+		return store.bounds
+	}
+	return nil
+}
+
+// MoveHandle tells the tree the object of the handle moved, its recorded bounds are refreshed
+// and it is relocated within the tree. It returns false if the handle is not live
+func (qt *Quadtree) MoveHandle(h Handle) bool {
+	if !qt.ready() {
+		return false
+	}
+	store := qt.m_config.handles
+	if !store.valid(h) {
+		return false
+	}
+	obj := store.objects[h]
+	slot := store.bounds[4*h : 4*h+4]
+	if slot[0] == obj.X() && slot[1] == obj.Y() && slot[2] == obj.Width() && slot[3] == obj.Height() {
+		return true
+	}
+	root := qt.root()
+	node := root.locate(&flatObject{slot[0], slot[1], slot[2], slot[3]}, obj)
+	store.record(h, obj)
+	root.logMove(obj)
+	if node == nil || root.m_config.straddlePolicy == StraddleDuplicate {
+		root.remove(obj)
+		root.Insert(obj)
+		return true
+	}
+	node.relocate(obj)
+	qt.enforceCapacity()
+	return true
+}
+
+// RemoveHandle removes the object of the handle from the tree and frees the handle
+func (qt *Quadtree) RemoveHandle(h Handle) bool {
+	if !qt.ready() {
+		return false
+	}
+	store := qt.m_config.handles
+	if !store.valid(h) {
+		return false
+	}
+	return qt.root().Remove(store.objects[h])
+}
+
+// release frees the handle of an object which left the tree, if it has one
+func (store *handleStore) release(obj PhysicalObject) {
+	h, ok := store.handles[obj]
+	if !ok {
+		return
+	}
+	delete(store.handles, obj)
+	store.objects[h] = nil
+	store.free = append(store.free, h)
+}
+
+// storedBounds returns the bounds of the object as the tree indexed them: those recorded in the flat
+// bounds for objects inserted with InsertHandle, so that traversals read the flat slice rather than
+// calling the object, and the current bounds of the object otherwise
+func (c *config) storedBounds(obj PhysicalObject) *Bounds {
+	if store := c.handles; store != nil {
+		if h, ok := store.handles[obj]; ok {
+			slot := store.bounds[4*h : 4*h+4]
+			return &Bounds{slot[0], slot[1], slot[2], slot[3]}
+		}
+	}
+	return boundsOf(obj)
+}
+
+// syncHandle refreshes the recorded bounds of the object, if it was inserted with InsertHandle
+func (qt *Quadtree) syncHandle(obj PhysicalObject) {
+	store := qt.m_config.handles
+	if store == nil {
+		return
+	}
+	if h, ok := store.handles[obj]; ok {
+		store.record(h, obj)
+	}
+}
+
+// locate descends from current node along the placement of at, which holds the previously recorded
+// bounds of obj, and returns the node storing obj, nil if it is not found there
+func (qt *Quadtree) locate(at PhysicalObject, obj PhysicalObject) *Quadtree {
+	node := qt
+	for {
+		// stop where the object stays, or straddles into several quadrants
+		placement := node.placement(at)
+		if placement == 0 || placement&(placement-1) != 0 {
+			break
+		}
+		index := 0
+		for placement > 1 {
+			placement >>= 1
+			index += 1
+		}
+		if node.m_ActiveNodes&(1<<uint(index)) == 0 {
+			break
+		}
+		node = node.Nodes[index]
+	}
+	for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if node.same(ele.Value.(PhysicalObject), obj) {
+			return node
+		}
+	}
+	return nil
+}
+
+// flatObject is a motionless object with the given bounds
+type flatObject struct {
+	x, y, width, height float64
+}
+
+func (o *flatObject) X() float64                { return o.x }
+func (o *flatObject) Y() float64                { return o.y }
+func (o *flatObject) Width() float64            { return o.width }
+func (o *flatObject) Height() float64           { return o.height }
+func (o *flatObject) Update(time.Duration) bool { return false }
@@ -0,0 +1,82 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestHandles(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	mover := &TestPhysicalObject{1, 1, 1, 1}
+	h, err := qt.InsertHandle(mover)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, _ := qt.InsertHandle(&TestPhysicalObject{6, 6, 1, 1})
+	if flat := qt.FlatBounds(); len(flat) != 8 || flat[4*other] != 6 {
+		t.Errorf("expects flat bounds of both handles, got %v", flat)
+	}
+
+	mover.x, mover.y = 5, 1
+	if !qt.MoveHandle(h) {
+		t.Fatalf("expects live handle %d", h)
+	}
+	if b, _ := qt.HandleBounds(h); b != (quadtree.Bounds{5, 1, 1, 1}) {
+		t.Errorf("expects refreshed bounds, got %v", b)
+	}
+	if node := qt.FindObject(mover); node != qt.Nodes[1] {
+		t.Errorf("expects the moved object to be relocated into the top right quadrant, got %v", node)
+	}
+
+	mover.x = 6
+	qt.Update(0)
+	if b, _ := qt.HandleBounds(h); b.X != 6 {
+		t.Errorf("expects Update to refresh the bounds, got %v", b)
+	}
+
+	if !qt.RemoveHandle(h) || qt.FindObject(mover) != nil || qt.HandleObject(h) != nil {
+		t.Errorf("expects the handle and its object to be removed")
+	}
+	if reused, _ := qt.InsertHandle(&TestPhysicalObject{2, 2, 1, 1}); reused != h {
+		t.Errorf("expects removed handle %d to be reused, got %d", h, reused)
+	}
+}
+
+func TestHandleSet(t *testing.T) {
+	var set quadtree.HandleSet
+	set.Add(0, 3, 130, -1)
+	for _, h := range []quadtree.Handle{0, 3, 130} {
+		if !set.Contains(h) {
+			t.Errorf("HandleSet expects to contain %v", h)
+		}
+	}
+	for _, h := range []quadtree.Handle{1, 64, 129, 500, -1} {
+		if set.Contains(h) {
+			t.Errorf("HandleSet expects not to contain %v", h)
+		}
+	}
+}
+
+func TestHandleRelease(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	obj := &TestPhysicalObject{1, 1, 1, 1}
+	h, _ := qt.InsertHandle(obj)
+	if !qt.Remove(obj) {
+		t.Fatal("expects the object to be removed")
+	}
+	if qt.HandleObject(h) != nil || qt.MoveHandle(h) || qt.FindObject(obj) != nil {
+		t.Errorf("expects the handle to be released when its object leaves the tree")
+	}
+
+	// traversals read the recorded bounds until the next Update or MoveHandle
+	h, _ = qt.InsertHandle(obj)
+	obj.x, obj.y = 6, 6
+	if found := qt.Retrieve(&quadtree.Bounds{0, 0, 2, 2}); len(found) != 1 {
+		t.Errorf("expects Retrieve to use the recorded bounds, got %v", found)
+	}
+	qt.MoveHandle(h)
+	if found := qt.Retrieve(&quadtree.Bounds{0, 0, 2, 2}); len(found) != 0 {
+		t.Errorf("expects Retrieve to follow MoveHandle, got %v", found)
+	}
+}
@@ -0,0 +1,147 @@
+package quadtree
+
+// Repositionable is implemented by objects which Undo and Redo can put back where they were
+type Repositionable interface {
+	PhysicalObject
+	SetBounds(b Bounds)
+}
+
+type historyOp int
+
+const (
+	historyInsert historyOp = iota
+	historyMove
+	historyRemove
+)
+
+// historyEntry is one mutation of the tree, with the bounds of the object before and after it
+type historyEntry struct {
+	op     historyOp
+	obj    PhysicalObject
+	before Bounds
+	after  Bounds
+}
+
+// history keeps the mutations recorded by the operation log hooks for Undo and Redo
+type history struct {
+	limit     int
+	done      []historyEntry
+	undone    []historyEntry
+	known     map[PhysicalObject]Bounds // last recorded bounds of the objects in the tree
+	replaying bool                      // set while Undo and Redo apply entries
+}
+
+// WithHistory makes the tree remember its last limit mutations (all of them if limit is 0),
+// so that they can be reverted by Undo and applied again by Redo.
+// Mutations are recorded where WithLog would log them, a rebuild of the tree clears the history
+func WithHistory(limit int) Option {
+	return func(c *config) {
+		c.history = &history{
+			limit: limit,
+			known: make(map[PhysicalObject]Bounds),
+		}
+	}
+}
+
+func (h *history) push(entry historyEntry) {
+	if h.replaying {
+		return
+	}
+	h.undone = nil
+	h.done = append(h.done, entry)
+	if h.limit > 0 && len(h.done) > h.limit {
+		h.done = h.done[len(h.done)-h.limit:]
+	}
+}
+
+func (h *history) inserted(obj PhysicalObject) {
+	if _, ok := h.known[obj]; ok {
+		return
+	}
+	b := *boundsOf(obj)
+	h.known[obj] = b
+	h.push(historyEntry{op: historyInsert, obj: obj, before: b, after: b})
+}
+
+func (h *history) moved(obj PhysicalObject) {
+	before, ok := h.known[obj]
+	if !ok {
+		return
+	}
+	after := *boundsOf(obj)
+	h.known[obj] = after
+	h.push(historyEntry{op: historyMove, obj: obj, before: before, after: after})
+}
+
+func (h *history) removed(obj PhysicalObject) {
+	before, ok := h.known[obj]
+	if !ok {
+		return
+	}
+	delete(h.known, obj)
+	h.push(historyEntry{op: historyRemove, obj: obj, before: before, after: before})
+}
+
+func (h *history) reset() {
+	h.done = nil
+	h.undone = nil
+	h.known = make(map[PhysicalObject]Bounds)
+}
+
+// Undo reverts the last n mutations of a tree created WithHistory, and returns how many were reverted.
+// Moves of objects which are not Repositionable are only reverted in the history
+func (qt *Quadtree) Undo(n int) int {
+	if !qt.ready() || qt.m_config.history == nil {
+		return 0
+	}
+	h := qt.m_config.history
+	count := 0
+	for ; count < n && len(h.done) > 0; count += 1 {
+		entry := h.done[len(h.done)-1]
+		h.done = h.done[:len(h.done)-1]
+		qt.apply(entry, true)
+		h.undone = append(h.undone, entry)
+	}
+	return count
+}
+
+// Redo applies again the last n mutations reverted by Undo, and returns how many were applied.
+// Any other mutation of the tree drops the mutations left to redo
+func (qt *Quadtree) Redo(n int) int {
+	if !qt.ready() || qt.m_config.history == nil {
+		return 0
+	}
+	h := qt.m_config.history
+	count := 0
+	for ; count < n && len(h.undone) > 0; count += 1 {
+		entry := h.undone[len(h.undone)-1]
+		h.undone = h.undone[:len(h.undone)-1]
+		qt.apply(entry, false)
+		h.done = append(h.done, entry)
+	}
+	return count
+}
+
+// apply performs a history entry, or reverts it
+func (qt *Quadtree) apply(entry historyEntry, revert bool) {
+	root := qt.root()
+	h := root.m_config.history
+	h.replaying = true
+	defer func() { h.replaying = false }()
+
+	if r, ok := entry.obj.(Repositionable); ok {
+		if revert {
+			r.SetBounds(entry.before)
+		} else {
+			r.SetBounds(entry.after)
+		}
+	}
+	switch {
+	case entry.op == historyInsert && revert, entry.op == historyRemove && !revert:
+		root.RemoveAll(entry.obj)
+	case entry.op == historyInsert, entry.op == historyRemove:
+		root.Insert(entry.obj)
+	default:
+		root.InsertOrMove(entry.obj)
+	}
+}
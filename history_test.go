@@ -0,0 +1,63 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+type placedObject struct {
+	TestPhysicalObject
+}
+
+func (po *placedObject) SetBounds(b quadtree.Bounds) {
+	po.x, po.y, po.width, po.height = b.X, b.Y, b.Width, b.Height
+}
+
+func TestUndoRedo(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithHistory(0))
+	a := &placedObject{TestPhysicalObject{1, 1, 1, 1}}
+	b := &placedObject{TestPhysicalObject{6, 6, 1, 1}}
+	qt.Insert(a)
+	qt.Insert(b)
+	qt.Remove(b)
+	a.x, a.y = 1, 6
+	qt.InsertOrMove(a)
+
+	if got := qt.Undo(2); got != 2 {
+		t.Fatalf("expects 2 mutations undone, got %d", got)
+	}
+	if qt.FindObject(a) != qt.Nodes[0] || qt.FindObject(b) != qt.Nodes[3] || a.y != 1 {
+		t.Errorf("expects the tree as it was before the edits, got\n%s", qt.String())
+	}
+	if got := qt.Redo(5); got != 2 {
+		t.Fatalf("expects 2 mutations redone, got %d", got)
+	}
+	if qt.FindObject(a) != qt.Nodes[2] || qt.FindObject(b) != nil || a.y != 6 {
+		t.Errorf("expects the edited tree, got\n%s", qt.String())
+	}
+
+	if got := qt.Undo(10); got != 4 {
+		t.Errorf("expects 4 mutations undone, got %d", got)
+	}
+	if objects, _ := qt.Size(); objects != 0 {
+		t.Errorf("expects an empty tree, got %d objects", objects)
+	}
+	qt.Insert(b)
+	if got := qt.Redo(1); got != 0 {
+		t.Errorf("expects a new mutation to drop the mutations left to redo, got %d redone", got)
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithHistory(2))
+	for i := 0; i < 5; i += 1 {
+		qt.Insert(&TestPhysicalObject{float64(i), 1, 1, 1})
+	}
+	if got := qt.Undo(5); got != 2 {
+		t.Errorf("expects only 2 mutations remembered, got %d", got)
+	}
+	if objects, _ := qt.Size(); objects != 3 {
+		t.Errorf("expects 3 objects left, got %d", objects)
+	}
+}
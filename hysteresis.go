@@ -0,0 +1,90 @@
+package quadtree
+
+// hysteresis is the configuration of WithHysteresis
+type hysteresis struct {
+	lowWater float64
+	ticks    int
+}
+
+// WithHysteresis makes Update merge the subtrees of a node back into it once the node and its subtrees
+// hold fewer than lowWater times MaxObjects objects for ticks consecutive Updates. Nodes still split
+// once they hold more than MaxObjects objects, the gap between both thresholds stops a node hovering
+// around MaxObjects from splitting and collapsing over and over. Without it, only empty nodes are pruned
+func WithHysteresis(lowWater float64, ticks int) Option {
+	return func(c *config) {
+		c.hysteresis = &hysteresis{lowWater: lowWater, ticks: ticks}
+	}
+}
+
+// collapseUnderfull merges the subtrees which held too few objects for long enough, and returns the
+// number of objects stored in the subtree
+func (qt *Quadtree) collapseUnderfull() int {
+	count := qt.m_Objects.Len()
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			count += qt.Nodes[index].collapseUnderfull()
+		}
+		flags >>= 1
+		index += 1
+	}
+	if qt.m_ActiveNodes == 0 {
+		qt.m_underTicks = 0
+		return count
+	}
+
+	h := qt.m_config.hysteresis
+	if float64(count) >= h.lowWater*float64(qt.maxObjects()) {
+		qt.m_underTicks = 0
+		return count
+	}
+	qt.m_underTicks += 1
+	if qt.m_underTicks < h.ticks {
+		return count
+	}
+	qt.m_underTicks = 0
+	qt.mergeChildren()
+	return qt.m_Objects.Len()
+}
+
+// mergeChildren moves the objects of the subtrees into current node and drops the subtrees
+func (qt *Quadtree) mergeChildren() {
+	var objects []PhysicalObject
+	collect := func(obj PhysicalObject) {
+		objects = append(objects, obj)
+	}
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		collect = dedupe(collect)
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			qt.Nodes[index].visitStored(collect)
+			qt.dropChild(index)
+		}
+		flags >>= 1
+		index += 1
+	}
+	for _, obj := range objects {
+		qt.m_Objects.PushBack(obj)
+	}
+	qt.touch(len(objects))
+}
+
+// visitStored calls fn with every object stored in the subtree, objects flagged by MarkRemoved included
+func (qt *Quadtree) visitStored(fn func(PhysicalObject)) {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		fn(ele.Value.(PhysicalObject))
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			qt.Nodes[index].visitStored(fn)
+		}
+		flags >>= 1
+		index += 1
+	}
+}
@@ -0,0 +1,46 @@
+package quadtree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestHysteresis(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 3, quadtree.WithHysteresis(0.5, 3))
+	var objects []*steeredObject
+	for _, at := range [][2]float64{{1, 1}, {5, 1}, {1, 5}, {5, 5}, {2, 2}} {
+		obj := &steeredObject{TestPhysicalObject: TestPhysicalObject{at[0], at[1], 1, 1}}
+		objects = append(objects, obj)
+		qt.Insert(obj)
+	}
+	if nodes := qt.Stats().Nodes; nodes != 5 {
+		t.Fatalf("Quadtree expects to split above MaxObjects, got %v nodes", nodes)
+	}
+
+	// at MaxObjects/2 the subtrees are kept
+	qt.Remove(objects[4])
+	qt.Remove(objects[3])
+	qt.Remove(objects[2])
+	for i := 0; i < 5; i += 1 {
+		qt.Update(time.Millisecond)
+	}
+	if nodes := qt.Stats().Nodes; nodes != 5 {
+		t.Errorf("Quadtree expects to keep its subtrees above the low water mark, got %v nodes", nodes)
+	}
+
+	qt.Remove(objects[1])
+	qt.Update(time.Millisecond)
+	qt.Update(time.Millisecond)
+	if nodes := qt.Stats().Nodes; nodes != 5 {
+		t.Errorf("Quadtree expects to keep its subtrees before the hysteresis ticks, got %v nodes", nodes)
+	}
+	qt.Update(time.Millisecond)
+	if stats := qt.Stats(); stats.Nodes != 1 || stats.Objects != 1 {
+		t.Errorf("Quadtree expects to merge its subtrees, got %+v", stats)
+	}
+	if qt.FindObject(objects[0]) != qt {
+		t.Errorf("Quadtree expects the merged object in the root")
+	}
+}
@@ -0,0 +1,169 @@
+package quadtree
+
+// idRegistry maps the objects of a tree to the IDs it assigned them
+type idRegistry struct {
+	last    uint64
+	ids     map[PhysicalObject]uint64
+	objects map[uint64]PhysicalObject
+}
+
+func newIDRegistry() *idRegistry {
+	return &idRegistry{
+		ids:     make(map[PhysicalObject]uint64),
+		objects: make(map[uint64]PhysicalObject),
+	}
+}
+
+// WithIDs makes the tree assign a stable ID to every object entering it through Insert, UpdateTree
+// or BuildPacked. IDs start at 1, are never reused, and are kept while the object stays in the tree
+func WithIDs() Option {
+	return func(c *config) {
+		c.ids = newIDRegistry()
+	}
+}
+
+// assignID gives the object an ID unless it already has one
+func (qt *Quadtree) assignID(obj PhysicalObject) {
+	reg := qt.m_config.ids
+	if reg == nil {
+		return
+	}
+	if _, ok := reg.ids[obj]; !ok {
+		reg.last += 1
+		reg.ids[obj] = reg.last
+		reg.objects[reg.last] = obj
+	}
+}
+
+// forget drops everything the tree remembers about an object which left it
+func (qt *Quadtree) forget(obj PhysicalObject) {
+	qt.forgetID(obj)
+	qt.logRemove(obj)
+	if tags := qt.m_config.tags; tags != nil {
+		delete(tags, obj)
+	}
+	if tombstones := qt.m_config.tombstones; tombstones != nil {
+		delete(tombstones, obj)
+	}
+	if t := qt.m_config.throttle; t != nil {
+		delete(t.pending, obj)
+		t.dropInterval(obj)
+	}
+	if q := qt.m_config.relocationQueue; q != nil {
+		q.drop(obj)
+	}
+	if e := qt.m_config.expiry; e != nil {
+		e.forget(obj)
+	}
+	if store := qt.m_config.handles; store != nil {
+		store.release(obj)
+	}
+}
+
+// rekey moves everything the tree remembers about old to obj, an equal object replacing it in the tree
+func (qt *Quadtree) rekey(old, obj PhysicalObject) {
+	if old == obj {
+		return
+	}
+	c := qt.m_config
+	if reg := c.ids; reg != nil {
+		if id, ok := reg.ids[old]; ok {
+			delete(reg.ids, old)
+			reg.ids[obj] = id
+			reg.objects[id] = obj
+		}
+	}
+	if tags := c.tags; tags != nil {
+		if set, ok := tags[old]; ok {
+			delete(tags, old)
+			tags[obj] = set
+		}
+	}
+	if store := c.handles; store != nil {
+		if h, ok := store.handles[old]; ok {
+			delete(store.handles, old)
+			store.handles[obj] = h
+			store.objects[h] = obj
+		}
+	}
+	if t := c.throttle; t != nil {
+		t.rekey(old, obj)
+	}
+	if e := c.expiry; e != nil {
+		e.rekey(old, obj)
+	}
+	if q := c.relocationQueue; q != nil {
+		q.drop(old)
+	}
+}
+
+// forgetID drops the ID of an object which left the tree
+func (qt *Quadtree) forgetID(obj PhysicalObject) {
+	reg := qt.m_config.ids
+	if reg == nil {
+		return
+	}
+	if id, ok := reg.ids[obj]; ok {
+		delete(reg.ids, obj)
+		delete(reg.objects, id)
+	}
+}
+
+// reassignIDs rebuilds the IDs after the tree was rebuilt, objects still in the tree keep their ID
+func (qt *Quadtree) reassignIDs() {
+	old := qt.m_config.ids
+	if old == nil {
+		return
+	}
+	reg := newIDRegistry()
+	reg.last = old.last
+	qt.m_config.ids = reg
+	qt.root().Walk(func(obj PhysicalObject) {
+		if id, ok := old.ids[obj]; ok {
+			reg.ids[obj] = id
+			reg.objects[id] = obj
+		} else {
+			qt.assignID(obj)
+		}
+	})
+}
+
+// ID returns the ID the tree assigned to the object, false if it has none
+func (qt *Quadtree) ID(obj PhysicalObject) (uint64, bool) {
+	if !qt.ready() || qt.m_config.ids == nil {
+		return 0, false
+	}
+	id, ok := qt.m_config.ids.ids[qt.canonical(obj)]
+	return id, ok
+}
+
+// GetByID returns the object with the given ID, nil if there is none
+func (qt *Quadtree) GetByID(id uint64) PhysicalObject {
+	if !qt.ready() || qt.m_config.ids == nil {
+		return nil
+	}
+	return qt.m_config.ids.objects[id]
+}
+
+// RemoveByID removes the object with the given ID from the tree
+func (qt *Quadtree) RemoveByID(id uint64) bool {
+	obj := qt.GetByID(id)
+	if obj == nil {
+		return false
+	}
+	if store := qt.m_config.handles; store != nil {
+		if h, ok := store.handles[obj]; ok {
+			return qt.RemoveHandle(h)
+		}
+	}
+	return qt.root().Remove(obj)
+}
+
+// HandleID returns the ID of the object of the handle, false if it has none
+func (qt *Quadtree) HandleID(h Handle) (uint64, bool) {
+	obj := qt.HandleObject(h)
+	if obj == nil {
+		return 0, false
+	}
+	return qt.ID(obj)
+}
@@ -0,0 +1,39 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestIDs(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithIDs())
+	first := &TestPhysicalObject{1, 1, 1, 1}
+	second := &TestPhysicalObject{6, 6, 1, 1}
+	qt.Insert(first)
+	h, _ := qt.InsertHandle(second)
+
+	id, ok := qt.ID(first)
+	if !ok || id != 1 || qt.GetByID(id) != first {
+		t.Fatalf("expects the first object to get ID 1, got %d", id)
+	}
+	if hid, ok := qt.HandleID(h); !ok || hid != 2 {
+		t.Errorf("expects the handle to carry ID 2, got %d", hid)
+	}
+
+	first.x = 6
+	qt.Update(0)
+	qt.UpdateTree(listOf(first, second))
+	if id, _ := qt.ID(first); id != 1 {
+		t.Errorf("expects the ID to survive moves and rebuilds, got %d", id)
+	}
+
+	if !qt.RemoveByID(2) || qt.FindObject(second) != nil || qt.GetByID(2) != nil || qt.HandleObject(h) != nil {
+		t.Errorf("expects the object with ID 2 and its handle to be removed")
+	}
+	third := &TestPhysicalObject{2, 2, 1, 1}
+	qt.Insert(third)
+	if id, _ := qt.ID(third); id != 3 {
+		t.Errorf("expects IDs not to be reused, got %d", id)
+	}
+}
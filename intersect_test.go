@@ -0,0 +1,139 @@
+package quadtree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestIntersectionSymmetry(t *testing.T) {
+	configs := map[string][]quadtree.Option{
+		"default":   nil,
+		"loose":     {quadtree.WithStraddlePolicy(quadtree.StraddleLoose)},
+		"duplicate": {quadtree.WithStraddlePolicy(quadtree.StraddleDuplicate)},
+		"epsilon":   {quadtree.WithEpsilon(0.25)},
+		"overlap":   {quadtree.WithChildOverlap(1)},
+		"overflow":  {quadtree.WithOverflowGuard(2, quadtree.OverflowReport, nil)},
+	}
+	for name, opts := range configs {
+		rng := rand.New(rand.NewSource(3))
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 32, 32}, 2, 4, opts...)
+		var objects []quadtree.PhysicalObject
+		sizes := []float64{0, 0.5, 1, 2, 4}
+		for i := 0; i < 150; i += 1 {
+			// objects on the borders of the nodes, and in between
+			obj := &TestPhysicalObject{
+				float64(rng.Intn(16)) * 2, float64(rng.Intn(16)) * 2,
+				sizes[rng.Intn(len(sizes))], sizes[rng.Intn(len(sizes))],
+			}
+			if i%3 == 0 {
+				obj.x += rng.Float64()
+				obj.y += rng.Float64()
+			}
+			objects = append(objects, obj)
+			qt.Insert(obj)
+			if i%10 == 0 {
+				// coincident duplicates inserted twice
+				qt.Insert(obj)
+			}
+		}
+
+		for ele := qt.GetIntersection(nil, nil).Front(); ele != nil; ele = ele.Next() {
+			record := ele.Value.(*quadtree.IntersectionRecord)
+			if record.One == record.Another {
+				t.Errorf("%s: expects no self pair, got %v", name, record.One)
+			}
+		}
+
+		found := make(map[[2]quadtree.PhysicalObject]bool)
+		for _, one := range objects {
+			for _, another := range qt.GetIntersectedObjects(one) {
+				if another == one {
+					t.Errorf("%s: expects %v not to intersect itself", name, one)
+				}
+				found[[2]quadtree.PhysicalObject{one, another}] = true
+			}
+		}
+		for pair := range found {
+			if !found[[2]quadtree.PhysicalObject{pair[1], pair[0]}] {
+				t.Errorf("%s: expects %v to intersect %v both ways", name, pair[0], pair[1])
+			}
+		}
+	}
+}
+
+func TestIntersectionPoliciesAgree(t *testing.T) {
+	policies := map[string]quadtree.StraddlePolicy{
+		"default":   quadtree.StraddleKeepAtParent,
+		"loose":     quadtree.StraddleLoose,
+		"duplicate": quadtree.StraddleDuplicate,
+	}
+	rng := rand.New(rand.NewSource(5))
+	objects := []quadtree.PhysicalObject{
+		// Intersect reports these as intersecting although their bounds do not overlap
+		&TestPhysicalObject{0, 0, 1, 1},
+		&TestPhysicalObject{1.5, 0, 3, 1},
+	}
+	sizes := []float64{0, 0.5, 1, 3, 6}
+	for i := 0; i < 120; i += 1 {
+		objects = append(objects, &TestPhysicalObject{
+			rng.Float64() * 28, rng.Float64() * 28,
+			sizes[rng.Intn(len(sizes))], sizes[rng.Intn(len(sizes))],
+		})
+	}
+	expected := make(map[[2]quadtree.PhysicalObject]bool)
+	for i, one := range objects {
+		for _, another := range objects[i+1:] {
+			if quadtree.Intersect(one, another) {
+				expected[[2]quadtree.PhysicalObject{one, another}] = true
+				expected[[2]quadtree.PhysicalObject{another, one}] = true
+			}
+		}
+	}
+
+	for name, policy := range policies {
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 32, 32}, 2, 5, quadtree.WithStraddlePolicy(policy))
+		for _, obj := range objects {
+			qt.Insert(obj)
+		}
+
+		pairs := make(map[[2]quadtree.PhysicalObject]bool)
+		for ele := qt.GetIntersection(nil, nil).Front(); ele != nil; ele = ele.Next() {
+			record := ele.Value.(*quadtree.IntersectionRecord)
+			pair := [2]quadtree.PhysicalObject{record.One, record.Another}
+			if pairs[pair] {
+				t.Errorf("%s: expects pair %v once", name, pair)
+			}
+			pairs[pair] = true
+			pairs[[2]quadtree.PhysicalObject{record.Another, record.One}] = true
+		}
+		if len(pairs) != len(expected) {
+			t.Errorf("%s: GetIntersection expects %d pairs, got %d", name, len(expected)/2, len(pairs)/2)
+		}
+		for pair := range expected {
+			if !pairs[pair] {
+				t.Errorf("%s: GetIntersection expects pair %v", name, pair)
+				break
+			}
+		}
+
+		for _, one := range objects {
+			found := qt.GetIntersectedObjects(one)
+			count := 0
+			for _, another := range objects {
+				if expected[[2]quadtree.PhysicalObject{one, another}] {
+					count += 1
+				}
+			}
+			if len(found) != count {
+				t.Errorf("%s: GetIntersectedObjects(%v) expects %d objects, got %d", name, one, count, len(found))
+			}
+			for _, another := range found {
+				if !expected[[2]quadtree.PhysicalObject{one, another}] {
+					t.Errorf("%s: GetIntersectedObjects(%v) expects no %v", name, one, another)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package quadtree
+
+import (
+	"math"
+)
+
+// MetricStats summarizes the values of a metric over a set of objects
+type MetricStats struct {
+	Count    int     // number of objects
+	Sum      float64 // sum of their values
+	Min, Max float64 // extreme values, +Inf and -Inf without objects
+}
+
+func emptyMetricStats() MetricStats {
+	return MetricStats{Min: math.Inf(1), Max: math.Inf(-1)}
+}
+
+// merge returns the stats of the union of two sets of objects
+func (s MetricStats) merge(another MetricStats) MetricStats {
+	return MetricStats{
+		Count: s.Count + another.Count,
+		Sum:   s.Sum + another.Sum,
+		Min:   math.Min(s.Min, another.Min),
+		Max:   math.Max(s.Max, another.Max),
+	}
+}
+
+// Mean returns the average value, NaN without objects
+func (s MetricStats) Mean() float64 {
+	if s.Count == 0 {
+		return math.NaN()
+	}
+	return s.Sum / float64(s.Count)
+}
+
+// WithMetric registers an aggregator under name, summarizing value over objects as MetricStats,
+// for SumInRegion, MinInRegion, MaxInRegion and MetricInRegion. Aggregate(name) returns the MetricStats of a subtree
+func WithMetric(name string, value func(PhysicalObject) float64) Option {
+	return WithAggregator(name, Aggregator{
+		Zero: func() interface{} { return emptyMetricStats() },
+		Add: func(acc interface{}, obj PhysicalObject) interface{} {
+			v := value(obj)
+			return acc.(MetricStats).merge(MetricStats{Count: 1, Sum: v, Min: v, Max: v})
+		},
+		Merge: func(acc, child interface{}) interface{} {
+			return acc.(MetricStats).merge(child.(MetricStats))
+		},
+	})
+}
+
+// MetricInRegion returns the stats of the named metric over the objects whose bounds overlap region,
+// combining the cached stats of the nodes region fully covers. Without a metric registered under name,
+// the stats are empty
+func (qt *Quadtree) MetricInRegion(name string, region *Bounds) MetricStats {
+	if stats, ok := qt.AggregateInRegion(name, region).(MetricStats); ok {
+		return stats
+	}
+	return emptyMetricStats()
+}
+
+// SumInRegion returns the sum of the named metric over the objects whose bounds overlap region
+func (qt *Quadtree) SumInRegion(name string, region *Bounds) float64 {
+	return qt.MetricInRegion(name, region).Sum
+}
+
+// MinInRegion returns the smallest value of the named metric over the objects whose bounds overlap region,
+// false if there is no such object
+func (qt *Quadtree) MinInRegion(name string, region *Bounds) (float64, bool) {
+	stats := qt.MetricInRegion(name, region)
+	return stats.Min, stats.Count > 0
+}
+
+// MaxInRegion returns the largest value of the named metric over the objects whose bounds overlap region,
+// false if there is no such object
+func (qt *Quadtree) MaxInRegion(name string, region *Bounds) (float64, bool) {
+	stats := qt.MetricInRegion(name, region)
+	return stats.Max, stats.Count > 0
+}
@@ -0,0 +1,44 @@
+package quadtree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+type lootObject struct {
+	TestPhysicalObject
+	value float64
+}
+
+func TestMetricInRegion(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10,
+		quadtree.WithMetric("loot", func(obj quadtree.PhysicalObject) float64 { return obj.(*lootObject).value }))
+	for _, obj := range []*lootObject{
+		{TestPhysicalObject{1, 1, 1, 1}, 10},
+		{TestPhysicalObject{2.5, 2.5, 1, 1}, 5},
+		{TestPhysicalObject{6, 6, 1, 1}, 100},
+		{TestPhysicalObject{3, 3, 2, 2}, 1},
+	} {
+		qt.Insert(obj)
+	}
+
+	sector := &quadtree.Bounds{0, 0, 4, 4}
+	if got := qt.SumInRegion("loot", sector); got != 16 {
+		t.Errorf("expects loot worth 16 in the sector, got %v", got)
+	}
+	if got, ok := qt.MinInRegion("loot", sector); got != 1 || !ok {
+		t.Errorf("expects the cheapest loot worth 1, got %v %v", got, ok)
+	}
+	if got, ok := qt.MaxInRegion("loot", &quadtree.Bounds{0, 0, 8, 8}); got != 100 || !ok {
+		t.Errorf("expects the richest loot worth 100, got %v %v", got, ok)
+	}
+	stats := qt.MetricInRegion("loot", &quadtree.Bounds{0, 4, 2, 2})
+	if _, ok := qt.MaxInRegion("loot", &quadtree.Bounds{0, 4, 2, 2}); ok || stats.Count != 0 || !math.IsNaN(stats.Mean()) {
+		t.Errorf("expects empty stats for an empty region, got %+v", stats)
+	}
+	if got := qt.Aggregate("loot").(quadtree.MetricStats).Mean(); got != 29 {
+		t.Errorf("expects a mean loot value of 29, got %v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package quadtree
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// RasterizeMinimap renders the objects of the tree into a w by h image covering its bounds, painting
+// the pixels overlapped by every object with the color classify returns for it, or none for nil.
+// Nodes no larger than a pixel are painted as a single block, with the color of one of their objects,
+// so that dense clusters cost one pixel rather than a visit of all of their objects. Objects of
+// deeper nodes are painted over those of their ancestors
+func (qt *Quadtree) RasterizeMinimap(w, h int, classify func(PhysicalObject) color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	if !qt.ready() || w <= 0 || h <= 0 || qt.Width <= 0 || qt.Height <= 0 {
+		return img
+	}
+	scaleX := float64(w) / qt.Width
+	scaleY := float64(h) / qt.Height
+	paint := func(b *Bounds, c color.Color) {
+		if c == nil {
+			return
+		}
+		left := clampInt(int(math.Floor((b.X-qt.X)*scaleX)), 0, w-1)
+		y0, y1 := qt.m_config.screenSpan(qt.Bounds, b.Y, b.Height)
+		top := clampInt(int(math.Floor((y0-qt.Y)*scaleY)), 0, h-1)
+		right := clampInt(int(math.Ceil((b.X+b.Width-qt.X)*scaleX)), left+1, w)
+		bottom := clampInt(int(math.Ceil((y1-qt.Y)*scaleY)), top+1, h)
+		for y := top; y < bottom; y += 1 {
+			for x := left; x < right; x += 1 {
+				img.Set(x, y, c)
+			}
+		}
+	}
+
+	var visit func(node *Quadtree)
+	visit = func(node *Quadtree) {
+		if node.Width*scaleX <= 1 && node.Height*scaleY <= 1 {
+			if obj := node.anyObject(); obj != nil {
+				cx, cy := node.Center()
+				paint(&Bounds{cx, cy, 0, 0}, classify(obj))
+			}
+			return
+		}
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			if obj := ele.Value.(PhysicalObject); !node.buried(obj) {
+				paint(boundsOf(obj), classify(obj))
+			}
+		}
+		flags := node.m_ActiveNodes
+		index := 0
+		for flags > 0 {
+			if flags&1 == 1 {
+				visit(node.Nodes[index])
+			}
+			flags >>= 1
+			index += 1
+		}
+	}
+	visit(qt)
+	return img
+}
+
+// anyObject returns an object of the subtree, the shallowest first, nil if it holds none
+func (qt *Quadtree) anyObject() PhysicalObject {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if obj := ele.Value.(PhysicalObject); !qt.buried(obj) {
+			return obj
+		}
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			if obj := qt.Nodes[index].anyObject(); obj != nil {
+				return obj
+			}
+		}
+		flags >>= 1
+		index += 1
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package quadtree_test
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestRasterizeMinimap(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 6)
+	wall := &TestPhysicalObject{1, 1, 2, 2}
+	qt.Insert(wall)
+	for i := 0; i < 20; i += 1 {
+		qt.Insert(&TestPhysicalObject{6 + float64(i%5)*0.3, 6 + float64(i/5)*0.3, 0.1, 0.1})
+	}
+	classified := 0
+	classify := func(obj quadtree.PhysicalObject) color.Color {
+		classified += 1
+		if obj == wall {
+			return red
+		}
+		return blue
+	}
+
+	img := qt.RasterizeMinimap(8, 8, classify)
+	if img.Bounds().Dx() != 8 || img.Bounds().Dy() != 8 {
+		t.Fatalf("expects an 8 by 8 image, got %v", img.Bounds())
+	}
+	for _, p := range [][2]int{{1, 1}, {2, 2}} {
+		if got := img.RGBAAt(p[0], p[1]); got != red {
+			t.Errorf("expects the wall at %v, got %v", p, got)
+		}
+	}
+	if got := img.RGBAAt(0, 0); got.A != 0 {
+		t.Errorf("expects empty pixels to stay transparent, got %v", got)
+	}
+	if got := img.RGBAAt(6, 6); got != blue {
+		t.Errorf("expects the cluster at (6, 6), got %v", got)
+	}
+
+	classified = 0
+	qt.RasterizeMinimap(2, 2, classify)
+	if classified >= 21 {
+		t.Errorf("expects the cluster to be painted as blocks, got %d objects classified", classified)
+	}
+}
@@ -0,0 +1,144 @@
+package quadtree
+
+import (
+	"container/heap"
+	"math"
+)
+
+// nodeQueue is a priority queue of nodes ordered by their distance to a query point
+type nodeQueue []nodeDistance
+
+type nodeDistance struct {
+	node     *Quadtree
+	distance float64
+}
+
+func (q nodeQueue) Len() int            { return len(q) }
+func (q nodeQueue) Less(i, j int) bool  { return q[i].distance < q[j].distance }
+func (q nodeQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *nodeQueue) Push(x interface{}) { *q = append(*q, x.(nodeDistance)) }
+func (q *nodeQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
+
+// pointBoundsDistance returns the distance from (x, y) to the closest point of b, 0 if b contains the point
+func pointBoundsDistance(x, y float64, b *Bounds) float64 {
+	dx := math.Max(math.Max(b.X-x, 0), x-(b.X+b.Width))
+	dy := math.Max(math.Max(b.Y-y, 0), y-(b.Y+b.Height))
+	return math.Hypot(dx, dy)
+}
+
+// center returns the center of the physical object
+func center(obj PhysicalObject) (float64, float64) {
+	return obj.X() + obj.Width()/2, obj.Y() + obj.Height()/2
+}
+
+// nearest runs a best-first search for the object closest to (x, y), measured to the object center.
+// Only objects for which accept returns true are considered, and only nodes for which visit returns true are searched.
+// The search counts into the query qc, which may be nil
+func (qt *Quadtree) nearest(x, y float64, accept func(obj PhysicalObject, cx, cy float64) bool, visit func(b *Bounds) bool, qc *queryConfig) PhysicalObject {
+	var best PhysicalObject
+	bestDistance := math.Inf(1)
+
+	queue := &nodeQueue{{qt, 0}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(nodeDistance)
+		if item.distance > bestDistance || !qc.enter() {
+			break
+		}
+		node := item.node
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			obj := ele.Value.(PhysicalObject)
+			if node.buried(obj) || !qc.accepts(node, obj) {
+				continue
+			}
+			if !qc.test() {
+				return best
+			}
+			cx, cy := center(obj)
+			if d := math.Hypot(cx-x, cy-y); d < bestDistance && accept(obj, cx, cy) {
+				best, bestDistance = obj, d
+			}
+		}
+
+		flags := node.m_ActiveNodes
+		index := 0
+		for flags > 0 {
+			if flags&1 == 1 {
+				b := node.Nodes[index].searchBounds()
+				if d := pointBoundsDistance(x, y, b); d <= bestDistance && visit(b) {
+					heap.Push(queue, nodeDistance{node.Nodes[index], d})
+				}
+			}
+			flags >>= 1
+			index += 1
+		}
+	}
+	return best
+}
+
+// angleDistance returns the absolute difference between two angles, in [0, π]
+func angleDistance(a, b float64) float64 {
+	d := math.Mod(math.Abs(a-b), 2*math.Pi)
+	if d > math.Pi {
+		d = 2*math.Pi - d
+	}
+	return d
+}
+
+// boundsInCone checks whether any part of b may lie within maxAngle of the direction angle seen from (x, y)
+func boundsInCone(x, y, direction, maxAngle float64, b *Bounds) bool {
+	if maxAngle >= math.Pi || pointBoundsDistance(x, y, b) == 0 {
+		return true
+	}
+	// the bounds do not contain the point, so they span less than π as seen from it
+	ref := math.Atan2(b.Y+b.Height/2-y, b.X+b.Width/2-x)
+	minDelta, maxDelta := 0.0, 0.0
+	for _, corner := range [4][2]float64{
+		{b.X, b.Y}, {b.X + b.Width, b.Y}, {b.X, b.Y + b.Height}, {b.X + b.Width, b.Y + b.Height},
+	} {
+		delta := math.Remainder(math.Atan2(corner[1]-y, corner[0]-x)-ref, 2*math.Pi)
+		minDelta = math.Min(minDelta, delta)
+		maxDelta = math.Max(maxDelta, delta)
+	}
+	d := math.Remainder(direction-ref, 2*math.Pi)
+	if d >= minDelta && d <= maxDelta {
+		return true
+	}
+	return math.Min(angleDistance(d, minDelta), angleDistance(d, maxDelta)) <= maxAngle
+}
+
+// NearestInDirection returns the object closest to (x, y) whose center lies within maxAngle radians
+// of the direction (dx, dy), nil if there is none. Objects are measured to their centers, objects
+// centered exactly at (x, y) have no direction and are ignored. A zero direction accepts every direction.
+// Options count the work of the search, bound it and skip objects as for Retrieve, a truncated search
+// returns the closest object found so far
+func (qt *Quadtree) NearestInDirection(x, y, dx, dy float64, maxAngle float64, opts ...QueryOption) PhysicalObject {
+	if !qt.ready() {
+		return nil
+	}
+	if dx == 0 && dy == 0 {
+		maxAngle = math.Pi
+	}
+	direction := math.Atan2(dy, dx)
+	var qc *queryConfig
+	if len(opts) > 0 {
+		qc = newQueryConfig(opts)
+		defer qc.report(qt.m_config)
+	}
+	return qt.nearest(x, y,
+		func(obj PhysicalObject, cx, cy float64) bool {
+			if cx == x && cy == y {
+				return false
+			}
+			return angleDistance(math.Atan2(cy-y, cx-x), direction) <= maxAngle
+		},
+		func(b *Bounds) bool {
+			return boundsInCone(x, y, direction, maxAngle, b)
+		},
+		qc,
+	)
+}
@@ -0,0 +1,64 @@
+package quadtree_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestNearestInDirection(t *testing.T) {
+	right := &TestPhysicalObject{6, 4.5, 1, 1}
+	farRight := &TestPhysicalObject{8, 4.5, 1, 1}
+	up := &TestPhysicalObject{4.5, 1, 1, 1}
+	diagonal := &TestPhysicalObject{6, 6, 1, 1}
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 10, 10}, 1, 10)
+	qt.UpdateTree(listOf(right, farRight, up, diagonal))
+
+	for _, c := range []struct {
+		dx, dy, angle float64
+		expected      quadtree.PhysicalObject
+	}{
+		{1, 0, 0.1, right},
+		{0, -1, 0.1, up},
+		{1, 1, 0.1, diagonal},
+		{-1, 0, math.Pi / 4, nil},
+		{0, 0, 0, right},
+	} {
+		if obj := qt.NearestInDirection(5, 5, c.dx, c.dy, c.angle); obj != c.expected {
+			t.Errorf("NearestInDirection(5, 5, %v, %v, %v) returns %v, expects %v", c.dx, c.dy, c.angle, obj, c.expected)
+		}
+	}
+}
+
+func TestNearestInDirectionBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var objects []quadtree.PhysicalObject
+	for i := 0; i < 500; i += 1 {
+		objects = append(objects, &TestPhysicalObject{rng.Float64() * 99, rng.Float64() * 99, 1, 1})
+	}
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 100, 100}, 4, 8)
+	for _, obj := range objects {
+		qt.Insert(obj)
+	}
+
+	for i := 0; i < 200; i += 1 {
+		x, y := rng.Float64()*100, rng.Float64()*100
+		dx, dy := rng.Float64()-0.5, rng.Float64()-0.5
+		maxAngle := rng.Float64() * math.Pi / 2
+
+		var expected quadtree.PhysicalObject
+		best := math.Inf(1)
+		for _, obj := range objects {
+			cx, cy := obj.X()+obj.Width()/2, obj.Y()+obj.Height()/2
+			diff := math.Abs(math.Remainder(math.Atan2(cy-y, cx-x)-math.Atan2(dy, dx), 2*math.Pi))
+			if d := math.Hypot(cx-x, cy-y); diff <= maxAngle && d < best {
+				expected, best = obj, d
+			}
+		}
+		if obj := qt.NearestInDirection(x, y, dx, dy, maxAngle); obj != expected {
+			t.Fatalf("NearestInDirection(%v, %v, %v, %v, %v) returns %v, expects %v", x, y, dx, dy, maxAngle, obj, expected)
+		}
+	}
+}
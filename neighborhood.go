@@ -0,0 +1,70 @@
+package quadtree
+
+import (
+	"math"
+)
+
+// EachNeighborhood calls fn once for every object of the tree with the other objects whose centers lie
+// within radius of its center, e.g. for flocking. Candidates are gathered once per node for all of its
+// objects rather than by a query per object. The neighbors slice is reused between calls, copy it to
+// keep it
+func (qt *Quadtree) EachNeighborhood(radius float64, fn func(obj PhysicalObject, neighbors []PhysicalObject)) {
+	if !qt.ready() {
+		return
+	}
+	var seen map[PhysicalObject]bool
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		seen = make(map[PhysicalObject]bool)
+	}
+	var candidates, neighbors []PhysicalObject
+	var visit func(node *Quadtree)
+	visit = func(node *Quadtree) {
+		gathered := false
+		for ele := node.m_Objects.Front(); ele != nil; ele = ele.Next() {
+			obj := ele.Value.(PhysicalObject)
+			if node.buried(obj) || seen[obj] {
+				continue
+			}
+			if seen != nil {
+				seen[obj] = true
+			}
+			if !gathered {
+				gathered = true
+				candidates = candidates[:0]
+				region := node.searchBounds().Expand(radius)
+				qt.visitRegion(&region, func(one PhysicalObject) {
+					candidates = append(candidates, one)
+				})
+			}
+			x, y := center(obj)
+			neighbors = neighbors[:0]
+			for _, one := range candidates {
+				if cx, cy := center(one); one != obj && math.Hypot(cx-x, cy-y) <= radius {
+					neighbors = append(neighbors, one)
+				}
+			}
+			fn(obj, neighbors)
+		}
+
+		flags := node.m_ActiveNodes
+		index := 0
+		for flags > 0 {
+			if flags&1 == 1 {
+				visit(node.Nodes[index])
+			}
+			flags >>= 1
+			index += 1
+		}
+	}
+	visit(qt)
+}
+
+// Neighborhoods returns the objects whose centers lie within radius of the center of every object of
+// the tree, see EachNeighborhood
+func (qt *Quadtree) Neighborhoods(radius float64) map[PhysicalObject][]PhysicalObject {
+	neighborhoods := make(map[PhysicalObject][]PhysicalObject)
+	qt.EachNeighborhood(radius, func(obj PhysicalObject, neighbors []PhysicalObject) {
+		neighborhoods[obj] = append([]PhysicalObject(nil), neighbors...)
+	})
+	return neighborhoods
+}
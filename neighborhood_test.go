@@ -0,0 +1,43 @@
+package quadtree_test
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestNeighborhoods(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 64, 64}, 4, 6)
+	var boids []*TestPhysicalObject
+	for i := 0; i < 200; i += 1 {
+		boid := &TestPhysicalObject{rng.Float64() * 63, rng.Float64() * 63, 1, 1}
+		boids = append(boids, boid)
+		qt.Insert(boid)
+	}
+
+	const radius = 5
+	neighborhoods := qt.Neighborhoods(radius)
+	if len(neighborhoods) != len(boids) {
+		t.Fatalf("expects a neighborhood per object, got %d", len(neighborhoods))
+	}
+	for _, boid := range boids {
+		expected := 0
+		for _, other := range boids {
+			if other != boid && math.Hypot(other.x-boid.x, other.y-boid.y) <= radius {
+				expected += 1
+			}
+		}
+		neighbors := neighborhoods[boid]
+		if len(neighbors) != expected {
+			t.Errorf("expects %d neighbors of %v, got %d", expected, boid, len(neighbors))
+		}
+		for _, neighbor := range neighbors {
+			if neighbor == quadtree.PhysicalObject(boid) {
+				t.Errorf("expects %v to be left out of its own neighborhood", boid)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package quadtree
+
+import (
+	"errors"
+)
+
+// ErrInvalidNodeKey is returned when parsing a NodeKey containing characters other than quadrant digits
+var ErrInvalidNodeKey = errors.New("invalid node key")
+
+// NodeKey is the canonical address of a node, the quadrant indexes ('0' to '3', see QuadrantName) on the path
+// from the root, the root itself has the empty key. Keys only depend on the shape of the tree,
+// so they stay valid across processes as long as the tree is built the same way
+type NodeKey string
+
+// ParseNodeKey validates s as a NodeKey
+func ParseNodeKey(s string) (NodeKey, error) {
+	for i := 0; i < len(s); i += 1 {
+		if s[i] < '0' || s[i] > '3' {
+			return "", ErrInvalidNodeKey
+		}
+	}
+	return NodeKey(s), nil
+}
+
+// Level returns the level of the addressed node, relative to the root
+func (key NodeKey) Level() int {
+	return len(key)
+}
+
+// Parent returns the key of the parent node, the root is its own parent
+func (key NodeKey) Parent() NodeKey {
+	if key == "" {
+		return key
+	}
+	return key[:len(key)-1]
+}
+
+// Child returns the key of the child node in the given quadrant
+func (key NodeKey) Child(index int) NodeKey {
+	return key + NodeKey('0'+byte(index))
+}
+
+// Key returns the canonical address of the node
+func (qt *Quadtree) Key() NodeKey {
+	if !qt.ready() {
+		return ""
+	}
+	var path []byte
+	for node := qt; node.m_parent != nil; node = node.m_parent {
+		for index, sibling := range node.m_parent.Nodes {
+			if sibling == node {
+				path = append(path, '0'+byte(index))
+				break
+			}
+		}
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return NodeKey(path)
+}
+
+// NodeAt returns the node addressed by key, relative to the root of the tree, nil if it does not exist
+func (qt *Quadtree) NodeAt(key NodeKey) *Quadtree {
+	if !qt.ready() {
+		return nil
+	}
+	node := qt.root()
+	for i := 0; i < len(key); i += 1 {
+		index := int(key[i] - '0')
+		if index < 0 || index > 3 || node.m_ActiveNodes&(1<<uint(index)) == 0 {
+			return nil
+		}
+		node = node.Nodes[index]
+	}
+	return node
+}
+
+// Bounds returns the bounds of the addressed node in a tree with the given root bounds whose nodes
+// split at their midpoint
+func (key NodeKey) Bounds(root *Bounds) Bounds {
+	b := *root
+	for i := 0; i < len(key); i += 1 {
+		b.Width /= 2
+		b.Height /= 2
+		index := key[i] - '0'
+		if index&1 == 1 {
+			b.X += b.Width
+		}
+		if index&2 == 2 {
+			b.Y += b.Height
+		}
+	}
+	return b
+}
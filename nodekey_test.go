@@ -0,0 +1,47 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestNodeKey(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	qt.UpdateTree(listOf(
+		&TestPhysicalObject{0, 0, 1, 1},
+		&TestPhysicalObject{2.5, 0.5, 1, 1},
+		&TestPhysicalObject{6, 6, 1, 1},
+	))
+
+	if key := qt.Key(); key != "" {
+		t.Errorf("root expects the empty key, got %q", key)
+	}
+	node := qt.NodeAt("01")
+	if node == nil || *node.Bounds != (quadtree.Bounds{2, 0, 2, 2}) {
+		t.Fatalf("expects node 01 to cover (2, 0, 2, 2), got %v", node)
+	}
+	if key := node.Key(); key != "01" || key.Level() != 2 || key.Parent() != "0" || key.Parent().Child(1) != key {
+		t.Errorf("expects key 01, got %q", key)
+	}
+	if qt.Nodes[3].NodeAt("01") != node {
+		t.Errorf("NodeAt expects to resolve keys from the root")
+	}
+	if qt.NodeAt("2") != nil || qt.NodeAt("4") != nil {
+		t.Errorf("NodeAt expects nil for missing nodes")
+	}
+
+	if _, err := quadtree.ParseNodeKey("014"); err != quadtree.ErrInvalidNodeKey {
+		t.Errorf("ParseNodeKey expects ErrInvalidNodeKey, got %v", err)
+	}
+	if key, err := quadtree.ParseNodeKey("013"); err != nil || key != "013" {
+		t.Errorf("ParseNodeKey expects 013, got %q, %v", key, err)
+	}
+}
+
+func TestNodeKeyBounds(t *testing.T) {
+	root := &quadtree.Bounds{0, 0, 100, 100}
+	if b := quadtree.NodeKey("13").Bounds(root); b != (quadtree.Bounds{75, 25, 25, 25}) {
+		t.Errorf("expects the bottom right cell of the top right quadrant, got %v", b)
+	}
+}
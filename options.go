@@ -0,0 +1,298 @@
+package quadtree
+
+import (
+	"context"
+	"math"
+	"sort"
+)
+
+// Option configures a Quadtree created by NewQuadtree, options are shared by all nodes of the tree
+type Option func(*config)
+
+type config struct {
+	invalidPolicy         InvalidCoordinatesPolicy
+	onInvalid             func(PhysicalObject)
+	eagerCollapse         bool
+	lifespan              int
+	lifespanDoublingLimit int
+	prunePolicy           PrunePolicy
+	splitChooser          SplitChooser
+	epsilon               float64
+	childOverlap          float64 // margin by which child nodes overlap their siblings
+	straddlePolicy        StraddlePolicy
+	straddleWidth         float64 // largest width of the objects duplicated with StraddleDuplicate
+	straddleHeight        float64 // largest height of the objects duplicated with StraddleDuplicate
+	profilerLabels        bool
+	profilerContext       context.Context // labels of the caller WithProfilerLabels adds to, nil for none
+	maxObjects            int             // capacity of the tree in stored objects, 0 for no limit
+	maxNodes              int             // capacity of the tree in nodes, 0 for no limit
+	evict                 Evictor
+	maxObjectsFunc        func(level int) int
+	integerGrid           bool
+	equal                 func(a, b PhysicalObject) bool
+	ids                   *idRegistry // IDs assigned to objects, nil unless WithIDs
+	tags                  map[PhysicalObject]map[string]bool
+	rejectDuplicates      bool
+	tombstones            map[PhysicalObject]bool
+	log                   *opLog   // mutation log, nil unless WithLog
+	history               *history // mutations to undo, nil unless WithHistory
+	fixedPoint            bool
+	fixedScale            float64   // 2 to the number of fractional bits of fixed-point coordinates
+	recorder              *recorder // operation recording, nil unless Record
+	aggregators           []namedAggregator
+	aggregateEpoch        uint64                       // bumped when cached aggregates go stale without a change stamp
+	chunks                map[NodeKey][]PhysicalObject // objects attached by AttachChunk
+	handles               *handleStore                 // flat bounds of the objects inserted with InsertHandle, created on first use
+	results               *resultCache                 // memoized query results, nil unless WithResultCache
+	overflow              *overflowGuard               // nil unless WithOverflowGuard
+	pairBudget            *pairBudget                  // nil unless WithPairBudget
+	queryTruncated        bool                         // the last query ran out of budget, see QueryTruncated
+	onRemoved             func(obj PhysicalObject, reason RemoveReason)
+	statsHistory          *statsHistory    // Stats sampled during Updates, nil unless WithStatsHistory
+	throttle              *throttle        // regions updated at a reduced rate, nil until SetRegionUpdateRate
+	pairs                 *pairSet         // intersecting pairs, nil unless WithPersistentPairs
+	hysteresis            *hysteresis      // nil unless WithHysteresis
+	borrowed              []PhysicalObject // results of the last query made with Borrow
+	curveOrder            CurveOrder
+	streams               []changeStreamer // publishers of StreamChanges, advanced after every Update
+	relocations           *relocations     // nil unless WithRelocationHistogram
+	expectedObjects       int              // number of objects the maps of the tree are sized for
+	allocator             Allocator        // nil unless WithAllocator
+	simulation            *simulation      // objects moved by Simulate, nil once committed
+	committing            *simulation      // simulation applied by the running Commit
+	relocationQueue       *relocationQueue // nil unless WithRelocationBudget
+	expiry                *expiry          // objects of InsertWithTTL and InsertWithLiveness, created on first use
+	points                *pointStore      // batches of InsertPoints, created on first use
+	yAxis                 YAxis
+}
+
+const (
+	// number of Updates an empty node survives by default
+	defaultLifespan = 64
+)
+
+func newConfig() *config {
+	return &config{
+		lifespan:              defaultLifespan,
+		lifespanDoublingLimit: defaultLifespan,
+	}
+}
+
+// InvalidCoordinatesPolicy decides what happens to objects with NaN or infinite coordinates
+type InvalidCoordinatesPolicy int
+
+const (
+	// InvalidCoordinatesReject makes Insert return ErrInvalidCoordinates, objects turning invalid during Update are dropped and reported
+	InvalidCoordinatesReject InvalidCoordinatesPolicy = iota
+	// InvalidCoordinatesDrop silently drops the object and reports it
+	InvalidCoordinatesDrop
+	// InvalidCoordinatesClamp keeps the object but clamps its placement to the root node,
+	// where it never takes part in quadrant classification
+	InvalidCoordinatesClamp
+)
+
+// WithInvalidCoordinatesPolicy sets the policy for objects with NaN or infinite coordinates,
+// report (if not nil) is called for every object dropped by the policy
+func WithInvalidCoordinatesPolicy(policy InvalidCoordinatesPolicy, report func(PhysicalObject)) Option {
+	return func(c *config) {
+		c.invalidPolicy = policy
+		c.onInvalid = report
+	}
+}
+
+func isFinite(f float64) bool {
+	return !math.IsNaN(f) && !math.IsInf(f, 0)
+}
+
+// validCoordinates checks that none of the dimensions of the object is NaN or infinite
+func validCoordinates(obj PhysicalObject) bool {
+	return isFinite(obj.X()) && isFinite(obj.Y()) && isFinite(obj.Width()) && isFinite(obj.Height())
+}
+
+// handleInvalid applies the invalid coordinates policy to an object that is not (or no longer) in the tree
+func (qt *Quadtree) handleInvalid(obj PhysicalObject) error {
+	c := qt.m_config
+	if c.invalidPolicy == InvalidCoordinatesClamp {
+		root := qt.root()
+		root.m_Objects.PushBack(obj)
+		root.touch(1)
+		root.assignID(obj)
+		root.logInsert(obj)
+		return nil
+	}
+	qt.forget(obj)
+	if c.onInvalid != nil {
+		c.onInvalid(obj)
+	}
+	if c.invalidPolicy == InvalidCoordinatesReject {
+		return ErrInvalidCoordinates
+	}
+	return nil
+}
+
+// WithEagerCollapse makes Remove collapse emptied nodes immediately and recursively,
+// instead of keeping them alive until their lifespan runs out during Update
+func WithEagerCollapse() Option {
+	return func(c *config) {
+		c.eagerCollapse = true
+	}
+}
+
+// WithLifespan sets the number of Updates an emptied node survives before being pruned.
+// Every time an empty node gets objects again its lifespan doubles, as long as it does not
+// exceed doublingLimit before doubling. A doublingLimit lower than lifespan disables the doubling
+func WithLifespan(lifespan, doublingLimit int) Option {
+	return func(c *config) {
+		c.lifespan = lifespan
+		c.lifespanDoublingLimit = doublingLimit
+	}
+}
+
+// PrunePolicy decides whether an empty leaf node dies during Update, idleTicks is the number of
+// consecutive Updates the node has been empty
+type PrunePolicy func(node *Quadtree, idleTicks int) bool
+
+// WithPrunePolicy replaces the lifespan countdown with a custom policy deciding when empty nodes are pruned
+func WithPrunePolicy(policy PrunePolicy) Option {
+	return func(c *config) {
+		c.prunePolicy = policy
+	}
+}
+
+// SplitChooser returns the point at which a node with the given bounds, holding the given objects,
+// is divided into quadrants. The point is clamped into the bounds of the node
+type SplitChooser func(bounds *Bounds, objects []PhysicalObject) (x, y float64)
+
+// WithSplitChooser makes nodes split at the point returned by choose rather than at their midpoint
+func WithSplitChooser(choose SplitChooser) Option {
+	return func(c *config) {
+		c.splitChooser = choose
+	}
+}
+
+// MedianSplit is a SplitChooser dividing nodes at the median of the top left corners of their objects,
+// it falls back to the midpoint for empty nodes
+func MedianSplit(bounds *Bounds, objects []PhysicalObject) (x, y float64) {
+	if len(objects) == 0 {
+		return bounds.X + bounds.Width/2, bounds.Y + bounds.Height/2
+	}
+	xs := make([]float64, len(objects))
+	ys := make([]float64, len(objects))
+	for i, obj := range objects {
+		xs[i] = obj.X()
+		ys[i] = obj.Y()
+	}
+	sort.Float64s(xs)
+	sort.Float64s(ys)
+	return xs[len(xs)/2], ys[len(ys)/2]
+}
+
+// WithEpsilon sets the tolerance used when classifying objects into quadrants, objects crossing
+// a quadrant border by no more than epsilon (e.g. due to floating point error) still descend into it
+func WithEpsilon(epsilon float64) Option {
+	return func(c *config) {
+		c.epsilon = math.Abs(epsilon)
+	}
+}
+
+// WithChildOverlap makes child nodes overlap their siblings by margin, so that objects crossing a
+// quadrant border by no more than margin, such as objects sitting exactly on a split line of a tile
+// aligned world, are owned by a child rather than by its parent. Queries search the bounds of the nodes
+// grown by margin. This is a lighter alternative to StraddleLoose for small margins
+func WithChildOverlap(margin float64) Option {
+	return func(c *config) {
+		c.childOverlap = math.Abs(margin)
+	}
+}
+
+// tolerance returns how far objects may cross the borders of the node owning them
+func (c *config) tolerance() float64 {
+	return math.Max(c.epsilon, c.childOverlap)
+}
+
+// WithMaxObjectsFunc makes the number of objects a node holds before splitting depend on its level,
+// replacing MaxObjects. The root is at level 0
+func WithMaxObjectsFunc(maxObjects func(level int) int) Option {
+	return func(c *config) {
+		c.maxObjectsFunc = maxObjects
+	}
+}
+
+// maxObjects returns the number of objects current node holds before splitting
+func (qt *Quadtree) maxObjects() int {
+	if maxObjects := qt.m_config.maxObjectsFunc; maxObjects != nil {
+		return maxObjects(qt.Level)
+	}
+	return qt.MaxObjects
+}
+
+// WithEqual makes Remove, FindObject, InsertOrMove and the exclusion of the target from its own
+// intersections identify objects with equal rather than by comparing the interfaces. IDs, tags,
+// tombstones and update intervals are kept for the stored object and found from any equal one,
+// at the cost of a search of the tree
+func WithEqual(equal func(a, b PhysicalObject) bool) Option {
+	return func(c *config) {
+		c.equal = equal
+	}
+}
+
+// same tells whether a and b are the same object
+func (qt *Quadtree) same(a, b PhysicalObject) bool {
+	return qt.m_config.same(a, b)
+}
+
+// same tells whether a and b are the same object, as identified by WithEqual if set
+func (c *config) same(a, b PhysicalObject) bool {
+	if c.equal != nil {
+		return c.equal(a, b)
+	}
+	return a == b
+}
+
+// canonical returns the object the tree stores for obj, another object equal to obj WithEqual, so that
+// the state kept by object, such as IDs, tags or tombstones, is found from any of the equal objects.
+// Objects not in the tree, and every object of trees without WithEqual, are returned as is
+func (qt *Quadtree) canonical(obj PhysicalObject) PhysicalObject {
+	if qt.m_config.equal == nil {
+		return obj
+	}
+	root := qt.root()
+	if node := root.locate(obj, obj); node != nil {
+		if stored := node.stored(obj); stored != nil {
+			return stored
+		}
+	}
+	if stored := root.stored(obj); stored != nil {
+		return stored
+	}
+	return obj
+}
+
+// stored returns the object of the subtree equal to obj, nil if there is none
+func (qt *Quadtree) stored(obj PhysicalObject) PhysicalObject {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if one := ele.Value.(PhysicalObject); qt.same(one, obj) {
+			return one
+		}
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			if one := qt.Nodes[index].stored(obj); one != nil {
+				return one
+			}
+		}
+		flags >>= 1
+		index += 1
+	}
+	return nil
+}
+
+// WithRejectDuplicates makes Insert return ErrDuplicateObject for objects already in the tree,
+// as identified by WithEqual if set. Every Insert then searches the whole tree
+func WithRejectDuplicates() Option {
+	return func(c *config) {
+		c.rejectDuplicates = true
+	}
+}
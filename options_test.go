@@ -0,0 +1,346 @@
+package quadtree_test
+
+import (
+	"container/list"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/quadtreetest"
+)
+
+func listOf(objects ...quadtree.PhysicalObject) *list.List {
+	l := &list.List{}
+	for _, obj := range objects {
+		l.PushBack(obj)
+	}
+	return l
+}
+
+func TestInvalidCoordinatesPolicy(t *testing.T) {
+	var reported []quadtree.PhysicalObject
+	report := func(obj quadtree.PhysicalObject) {
+		reported = append(reported, obj)
+	}
+
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 10, quadtree.WithInvalidCoordinatesPolicy(quadtree.InvalidCoordinatesReject, report))
+	if err := qt.Insert(&TestPhysicalObject{math.NaN(), 0, 1, 1}); err != quadtree.ErrInvalidCoordinates {
+		t.Errorf("Insert of NaN object returns %v, expects ErrInvalidCoordinates", err)
+	}
+	if err := qt.Insert(&TestPhysicalObject{0, 0, 1, 1}); err != nil {
+		t.Errorf("Insert of valid object returns %v", err)
+	}
+
+	// object moves to NaN during Update
+	moving := &TestPhysicalObject{2, 2, 1, 1}
+	qt.Insert(moving)
+	moving.x = math.Inf(1)
+	qt.Update(0)
+	if qt.FindObject(moving) != nil {
+		t.Errorf("object with infinite coordinates remains in the tree:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+	if len(reported) != 2 || reported[1] != moving {
+		t.Errorf("expects 2 reported objects, got %d", len(reported))
+	}
+
+	qt = quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 10, quadtree.WithInvalidCoordinatesPolicy(quadtree.InvalidCoordinatesDrop, nil))
+	if err := qt.Insert(&TestPhysicalObject{0, math.NaN(), 1, 1}); err != nil {
+		t.Errorf("Insert with drop policy returns %v", err)
+	}
+	if len(qt.Objects()) != 0 {
+		t.Errorf("dropped object is stored in the tree")
+	}
+
+	qt = quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 10, quadtree.WithInvalidCoordinatesPolicy(quadtree.InvalidCoordinatesClamp, nil))
+	qt.Insert(&TestPhysicalObject{0, 0, 1, 1})
+	qt.Insert(&TestPhysicalObject{0, 0, 1, 1})
+	nan := &TestPhysicalObject{math.NaN(), 0, 1, 1}
+	if err := qt.Insert(nan); err != nil {
+		t.Errorf("Insert with clamp policy returns %v", err)
+	}
+	if qt.FindObject(nan) != qt {
+		t.Errorf("clamped object is not stored at the root:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+}
+
+func TestEagerCollapse(t *testing.T) {
+	deep := &TestPhysicalObject{0, 0, 0.5, 0.5}
+	other := &TestPhysicalObject{3, 3, 1, 1}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 0, 3, quadtree.WithEagerCollapse())
+	qt.Insert(other)
+	qt.Insert(deep)
+
+	if qt.FindObject(deep).Level != 3 {
+		t.Fatalf("object expects to be stored at level 3:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+	qt.Remove(deep)
+	if qt.Nodes[0] != nil || qt.Nodes[1] != nil || qt.Nodes[2] != nil || qt.Nodes[3] == nil {
+		t.Errorf("emptied subtrees are not collapsed:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+	if qt.FindObject(other) == nil {
+		t.Errorf("object lost after collapsing siblings")
+	}
+}
+
+func TestLifespanAndPrunePolicy(t *testing.T) {
+	newTree := func(opts ...quadtree.Option) (*quadtree.Quadtree, *TestPhysicalObject) {
+		obj := &TestPhysicalObject{0, 0, 1, 1}
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 0, 1, opts...)
+		qt.Insert(obj)
+		// move the object out of its node, leaving the node empty
+		obj.x, obj.y = 1.5, 1.5
+		qt.Update(0)
+		if qt.Nodes[0] == nil {
+			t.Fatalf("emptied node expects to be retained:\n%s", quadtreetest.DumpState(qt).String(0))
+		}
+		return qt, obj
+	}
+
+	qt, _ := newTree(quadtree.WithLifespan(2, 2))
+	qt.Update(0)
+	if qt.Nodes[0] == nil {
+		t.Errorf("emptied node pruned before its lifespan runs out")
+	}
+	qt.Update(0)
+	if qt.Nodes[0] != nil {
+		t.Errorf("emptied node expects to be pruned after 2 Updates:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+
+	var idle []int
+	qt, _ = newTree(quadtree.WithPrunePolicy(func(node *quadtree.Quadtree, idleTicks int) bool {
+		idle = append(idle, idleTicks)
+		return idleTicks >= 2
+	}))
+	qt.Update(0)
+	if qt.Nodes[0] == nil {
+		t.Errorf("emptied node pruned before the policy allows it")
+	}
+	qt.Update(0)
+	if qt.Nodes[0] != nil {
+		t.Errorf("emptied node expects to be pruned by the policy:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+	if len(idle) != 3 || idle[0] != 0 || idle[2] != 2 {
+		t.Errorf("prune policy called with idle ticks %v, expects [0 1 2]", idle)
+	}
+}
+
+func TestShortLifespanKeepsObjects(t *testing.T) {
+	for _, lifespan := range []int{1, 2} {
+		rng := rand.New(rand.NewSource(int64(lifespan)))
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 64, 64}, 2, 6, quadtree.WithLifespan(lifespan, lifespan))
+		objects := make([]*TestPhysicalObject, 80)
+		for i := range objects {
+			objects[i] = &TestPhysicalObject{rng.Float64() * 63, rng.Float64() * 63, 1, 1}
+			qt.Insert(objects[i])
+		}
+		for step := 0; step < 50; step += 1 {
+			// objects jump into nodes emptied during the same Update
+			for _, obj := range objects {
+				obj.x, obj.y = rng.Float64()*63, rng.Float64()*63
+			}
+			qt.Update(0)
+			if found := len(qt.Retrieve(qt.Bounds)); found != len(objects) {
+				t.Fatalf("lifespan %d expects %d objects after %d Updates, got %d", lifespan, len(objects), step+1, found)
+			}
+		}
+		for _, obj := range objects {
+			if qt.FindObject(obj) == nil {
+				t.Errorf("lifespan %d lost object %v", lifespan, *obj)
+			}
+		}
+	}
+}
+
+func TestMedianSplit(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 100, 100}, 1, 10, quadtree.WithSplitChooser(quadtree.MedianSplit))
+	objects := []quadtree.PhysicalObject{
+		&TestPhysicalObject{0, 0, 1, 1},
+		&TestPhysicalObject{2, 0, 1, 1},
+		&TestPhysicalObject{0, 2, 1, 1},
+		&TestPhysicalObject{2, 2, 1, 1},
+	}
+	qt.UpdateTree(listOf(objects...))
+
+	if x, y := qt.SplitPoint(); x != 2 || y != 2 {
+		t.Errorf("root expects to split at (2, 2), got (%v, %v)", x, y)
+	}
+	for i, obj := range objects {
+		if node := qt.FindObject(obj); node != qt.Nodes[i] {
+			t.Errorf("object %d expects to be stored in quadrant %d:\n%s", i, i, quadtreetest.DumpState(qt).String(0))
+		}
+	}
+	if b := *qt.Nodes[3].Bounds; b != (quadtree.Bounds{2, 2, 98, 98}) {
+		t.Errorf("bottom right quadrant has bounds %+v, expects {2 2 98 98}", b)
+	}
+
+	// later inserts classify against the chosen split point
+	late := &TestPhysicalObject{1.5, 1.5, 1, 1}
+	qt.Insert(late)
+	if qt.FindObject(late) != qt {
+		t.Errorf("object straddling the split point expects to be stored at the root")
+	}
+}
+
+func TestEpsilon(t *testing.T) {
+	// 0.1 + 0.2 > 0.3 in floating point, the object crosses the midpoint by a hair
+	obj := &TestPhysicalObject{0.1, 0, 0.2, 0.3}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 0.6, 0.6}, 0, 1)
+	qt.Insert(obj)
+	if qt.FindObject(obj) != qt {
+		t.Fatalf("object expects to be stuck at the root without epsilon")
+	}
+
+	qt = quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 0.6, 0.6}, 0, 1, quadtree.WithEpsilon(1e-9))
+	qt.Insert(obj)
+	if qt.FindObject(obj) != qt.Nodes[0] {
+		t.Errorf("object expects to descend into the top left quadrant:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+	qt.Update(0)
+	if qt.FindObject(obj) != qt.Nodes[0] {
+		t.Errorf("object expects to stay in the top left quadrant after Update:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+}
+
+func TestChildOverlap(t *testing.T) {
+	onLine := &TestPhysicalObject{7.75, 2, 0.5, 0.5}
+	neighbor := &TestPhysicalObject{8.1, 2, 0.5, 0.5}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 16, 16}, 0, 1, quadtree.WithChildOverlap(0.5))
+	qt.Insert(onLine)
+	qt.Insert(neighbor)
+	if qt.FindObject(onLine) != qt.Nodes[0] {
+		t.Fatalf("object on the split line expects to be owned by the top left quadrant:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+	if got := qt.Retrieve(&quadtree.Bounds{8.2, 2, 1, 1}); len(got) != 2 {
+		t.Errorf("Retrieve expects to search the overlapping margin of the quadrants, got %v", got)
+	}
+	if got := qt.GetIntersectedObjects(onLine); len(got) != 1 || got[0] != neighbor {
+		t.Errorf("GetIntersectedObjects expects objects of sibling quadrants, got %v", got)
+	}
+}
+
+func TestMaxObjectsFunc(t *testing.T) {
+	objects := []quadtree.PhysicalObject{
+		&TestPhysicalObject{0, 0, 1, 1},
+		&TestPhysicalObject{1, 1, 1, 1},
+		&TestPhysicalObject{2, 2, 1, 1},
+		&TestPhysicalObject{3, 3, 1, 1},
+	}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 16, 16}, 1, 10, quadtree.WithMaxObjectsFunc(func(level int) int {
+		if level == 0 {
+			return 1
+		}
+		return 8
+	}))
+	qt.UpdateTree(listOf(objects...))
+
+	if qt.Nodes[0] == nil || qt.Nodes[0].Nodes != [4]*quadtree.Quadtree{} || len(qt.Nodes[0].Objects()) != len(objects) {
+		t.Errorf("expects the root to split and its top left quadrant to hold every object:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+}
+
+type entityView struct {
+	TestPhysicalObject
+	id int
+}
+
+func TestEqual(t *testing.T) {
+	sameEntity := func(a, b quadtree.PhysicalObject) bool {
+		ea, ok := a.(*entityView)
+		eb, ok2 := b.(*entityView)
+		return ok && ok2 && ea.id == eb.id
+	}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithEqual(sameEntity))
+	stored := &entityView{TestPhysicalObject{1, 1, 2, 2}, 7}
+	neighbour := &entityView{TestPhysicalObject{2, 2, 1, 1}, 8}
+	qt.Insert(stored)
+	qt.Insert(neighbour)
+
+	view := &entityView{TestPhysicalObject{1, 1, 2, 2}, 7}
+	if qt.FindObject(view) == nil {
+		t.Errorf("expects another view of the same entity to be found")
+	}
+	inter := qt.GetIntersectedObjects(view)
+	if len(inter) != 1 || inter[0] != neighbour {
+		t.Errorf("expects the entity to be excluded from its own intersections, got %v", inter)
+	}
+	if !qt.Remove(view) || qt.FindObject(stored) != nil {
+		t.Errorf("expects the stored entity to be removed through another view")
+	}
+}
+
+func TestEqualKeys(t *testing.T) {
+	sameEntity := func(a, b quadtree.PhysicalObject) bool {
+		ea, ok := a.(*entityView)
+		eb, ok2 := b.(*entityView)
+		return ok && ok2 && ea.id == eb.id
+	}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithEqual(sameEntity), quadtree.WithIDs())
+	stored := &entityView{TestPhysicalObject{1, 1, 1, 1}, 7}
+	qt.Insert(stored)
+	qt.Insert(&entityView{TestPhysicalObject{6, 6, 1, 1}, 8})
+	view := &entityView{TestPhysicalObject{1, 1, 1, 1}, 7}
+
+	if id, ok := qt.ID(view); !ok || qt.GetByID(id) != stored {
+		t.Errorf("expects another view to find the ID of the entity, got %v %v", id, ok)
+	}
+	qt.Tag(view, "player")
+	if !qt.HasTag(stored, "player") || len(qt.RetrieveTagged(&quadtree.Bounds{0, 0, 8, 8}, "player")) != 1 {
+		t.Errorf("expects tags attached through another view to belong to the entity")
+	}
+	qt.MarkRemoved(view)
+	if found := qt.Retrieve(&quadtree.Bounds{0, 0, 3, 3}); len(found) != 0 {
+		t.Errorf("expects the entity to be flagged as removed through another view, got %v", found)
+	}
+	qt.Insert(view)
+	if found := qt.Retrieve(&quadtree.Bounds{0, 0, 3, 3}); len(found) != 1 || found[0] != view {
+		t.Errorf("expects the flagged entity to be replaced when inserted again, got %v", found)
+	}
+	if objects, _ := qt.Size(); objects != 2 {
+		t.Errorf("expects the entity to be stored once, got %d objects", objects)
+	}
+}
+
+func TestRejectDuplicates(t *testing.T) {
+	obj := &TestPhysicalObject{1, 1, 1, 1}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithRejectDuplicates())
+	if err := qt.Insert(obj); err != nil {
+		t.Fatal(err)
+	}
+	if err := qt.Insert(obj); err != quadtree.ErrDuplicateObject {
+		t.Errorf("expects ErrDuplicateObject, got %v", err)
+	}
+
+	lenient := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	for i := 0; i < 3; i += 1 {
+		lenient.Insert(obj)
+	}
+	lenient.Insert(&TestPhysicalObject{6, 6, 1, 1})
+	if removed := lenient.RemoveAll(obj); removed != 3 || lenient.FindObject(obj) != nil {
+		t.Errorf("expects 3 copies to be removed, got %d", removed)
+	}
+	if removed := lenient.RemoveAll(obj); removed != 0 {
+		t.Errorf("expects nothing left to remove, got %d", removed)
+	}
+}
+
+func TestRemoveOneOfDuplicates(t *testing.T) {
+	obj := &TestPhysicalObject{1, 1, 1, 1}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithIDs())
+	qt.Insert(obj)
+	qt.Insert(obj)
+	qt.Tag(obj, "player")
+	id, _ := qt.ID(obj)
+
+	if !qt.Remove(obj) || qt.FindObject(obj) == nil {
+		t.Fatal("expects one occurrence to remain after Remove")
+	}
+	if got, ok := qt.ID(obj); !ok || got != id || !qt.HasTag(obj, "player") {
+		t.Errorf("expects the remaining occurrence to keep ID %d and its tag, got %d, %v", id, got, ok)
+	}
+
+	qt.Remove(obj)
+	if _, ok := qt.ID(obj); ok || qt.HasTag(obj, "player") || qt.GetByID(id) != nil {
+		t.Error("expects the object to be forgotten once its last occurrence is removed")
+	}
+}
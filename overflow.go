@@ -0,0 +1,107 @@
+package quadtree
+
+import (
+	"container/list"
+)
+
+// OverflowStrategy decides how GetIntersection treats the objects of overflowing leaves
+type OverflowStrategy int
+
+const (
+	// OverflowReport only reports overflowing leaves, their objects are all tested against each other
+	OverflowReport OverflowStrategy = iota
+	// OverflowCapPairs tests every object of an overflowing leaf against the first limit objects of the
+	// leaf only, so that the pairs generated grow linearly with the number of objects
+	OverflowCapPairs
+	// OverflowMergeIdentical makes objects whose bounds are identical to those of a previous object of an
+	// overflowing leaf skip intersection tests, the previous object stands for them
+	OverflowMergeIdentical
+)
+
+// overflowGuard is the configuration of WithOverflowGuard
+type overflowGuard struct {
+	limit    int
+	strategy OverflowStrategy
+	report   func(node *Quadtree, count int)
+}
+
+// WithOverflowGuard detects leaves holding more than limit objects which cannot be split any further,
+// because they reached MaxLevels or the unit cell of the grid, as happens to objects stacked at one
+// point. report (if not nil) is called with the leaf and its number of objects every time an object
+// enters an overflowing leaf, and strategy bounds the cost of their intersection tests
+func WithOverflowGuard(limit int, strategy OverflowStrategy, report func(node *Quadtree, count int)) Option {
+	return func(c *config) {
+		c.overflow = &overflowGuard{limit: limit, strategy: strategy, report: report}
+	}
+}
+
+// overflowing tells whether current node is a leaf which cannot split and holds too many objects
+func (qt *Quadtree) overflowing() bool {
+	g := qt.m_config.overflow
+	return g != nil && qt.m_ActiveNodes == 0 && qt.m_Objects.Len() > g.limit &&
+		(qt.Level >= qt.MaxLevels || qt.unitCell())
+}
+
+// checkOverflow reports current node if it overflows
+func (qt *Quadtree) checkOverflow() {
+	if qt.overflowing() {
+		if report := qt.m_config.overflow.report; report != nil {
+			report(qt, qt.m_Objects.Len())
+		}
+	}
+}
+
+// getOverflowIntersection is getIntersection for an overflowing leaf
+func (qt *Quadtree) getOverflowIntersection(intersections *list.List, potentialObjects *list.List, qc *queryConfig) {
+	g := qt.m_config.overflow
+	var leaf []PhysicalObject
+	var seen map[Bounds]bool
+	if g.strategy == OverflowMergeIdentical {
+		seen = make(map[Bounds]bool)
+	}
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		one := ele.Value.(PhysicalObject)
+		if qt.buried(one) || !qc.accepts(qt, one) {
+			continue
+		}
+		if seen != nil {
+			b := *boundsOf(one)
+			if seen[b] {
+				continue
+			}
+			seen[b] = true
+		}
+		// check intersections with each physical object of parent nodes
+		for eleParent := potentialObjects.Front(); eleParent != nil; eleParent = eleParent.Next() {
+			objParent := eleParent.Value.(PhysicalObject)
+			if !qc.test() {
+				return
+			}
+			if !qt.same(objParent, one) && Intersect(objParent, one) {
+				intersections.PushBack(&IntersectionRecord{One: objParent, Another: one})
+				if qt.m_config.pairsFull(intersections) {
+					return
+				}
+			}
+		}
+		previous := leaf
+		if g.strategy == OverflowCapPairs && len(previous) > g.limit {
+			previous = previous[:g.limit]
+		}
+		for _, another := range previous {
+			if !qc.test() {
+				return
+			}
+			if !qt.same(another, one) && Intersect(another, one) {
+				intersections.PushBack(&IntersectionRecord{One: another, Another: one})
+				if qt.m_config.pairsFull(intersections) {
+					return
+				}
+			}
+		}
+		leaf = append(leaf, one)
+	}
+	for _, one := range leaf {
+		potentialObjects.PushBack(one)
+	}
+}
@@ -0,0 +1,50 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func stackedTree(strategy quadtree.OverflowStrategy, reports *int) *quadtree.Quadtree {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 2, 2,
+		quadtree.WithOverflowGuard(4, strategy, func(node *quadtree.Quadtree, count int) {
+			if node.Level != 2 || count <= 4 {
+				panic("unexpected overflow report")
+			}
+			*reports += 1
+		}))
+	for i := 0; i < 20; i += 1 {
+		qt.Insert(&TestPhysicalObject{1, 1, 0.5, 0.5})
+	}
+	qt.Insert(&TestPhysicalObject{1.2, 1.2, 0.5, 0.5})
+	return qt
+}
+
+func TestOverflowGuard(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy quadtree.OverflowStrategy
+		pairs    int
+	}{
+		// every pair of the 21 objects
+		{"report", quadtree.OverflowReport, 210},
+		// object i is tested against min(i, 4) previous objects
+		{"cap pairs", quadtree.OverflowCapPairs, 0 + 1 + 2 + 3 + 17*4},
+		// the stacked objects stand as one
+		{"merge identical", quadtree.OverflowMergeIdentical, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reports := 0
+			qt := stackedTree(tt.strategy, &reports)
+			// objects 5 to 21 overflow the leaf
+			if reports != 17 {
+				t.Errorf("Overflow expects to be reported 17 times, got %v", reports)
+			}
+			if got := qt.GetIntersection(nil, nil).Len(); got != tt.pairs {
+				t.Errorf("GetIntersection expects %v pairs, got %v", tt.pairs, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package quadtree
+
+import (
+	"math"
+)
+
+// View queries several trees as one, such as the terrain, the actors and the projectiles of a world
+// kept in trees of their own. Objects found in several trees are returned once
+type View struct {
+	trees []*Quadtree
+}
+
+// Overlay returns a View over the trees, nil trees are skipped. The trees are not copied, queries
+// of the view see their current state
+func Overlay(trees ...*Quadtree) View {
+	var view View
+	for _, tree := range trees {
+		if tree.ready() {
+			view.trees = append(view.trees, tree.root())
+		}
+	}
+	return view
+}
+
+// Trees returns the trees of the view
+func (v View) Trees() []*Quadtree {
+	return v.trees
+}
+
+// collect runs query on every tree and merges the results, keeping the first occurrence of every object
+func (v View) collect(query func(tree *Quadtree) IntersectedObjects) IntersectedObjects {
+	if len(v.trees) == 1 {
+		return query(v.trees[0])
+	}
+	var objects []PhysicalObject
+	keep := dedupe(func(obj PhysicalObject) {
+		objects = append(objects, obj)
+	})
+	for _, tree := range v.trees {
+		for _, obj := range query(tree) {
+			keep(obj)
+		}
+	}
+	return objects
+}
+
+// Walk calls walker with every object of the trees, objects in several trees once
+func (v View) Walk(walker func(PhysicalObject)) {
+	keep := dedupe(walker)
+	for _, tree := range v.trees {
+		tree.Walk(keep)
+	}
+}
+
+// Retrieve returns the objects of the trees overlapping region, opts apply to the query of every tree
+func (v View) Retrieve(region *Bounds, opts ...QueryOption) IntersectedObjects {
+	return v.collect(func(tree *Quadtree) IntersectedObjects {
+		return tree.Retrieve(region, opts...)
+	})
+}
+
+// RetrieveTagged returns the objects of the trees overlapping region and tagged with tag in their tree
+func (v View) RetrieveTagged(region *Bounds, tag string, opts ...QueryOption) IntersectedObjects {
+	return v.collect(func(tree *Quadtree) IntersectedObjects {
+		return tree.RetrieveTagged(region, tag, opts...)
+	})
+}
+
+// QueryRing returns the objects of the trees overlapping the ring, see Quadtree.QueryRing
+func (v View) QueryRing(cx, cy, rInner, rOuter float64, opts ...QueryOption) IntersectedObjects {
+	return v.collect(func(tree *Quadtree) IntersectedObjects {
+		return tree.QueryRing(cx, cy, rInner, rOuter, opts...)
+	})
+}
+
+// QueryAlongPath returns the objects of the trees within radius of the path, see Quadtree.QueryAlongPath
+func (v View) QueryAlongPath(points []Point, radius float64, opts ...QueryOption) IntersectedObjects {
+	return v.collect(func(tree *Quadtree) IntersectedObjects {
+		return tree.QueryAlongPath(points, radius, opts...)
+	})
+}
+
+// GetIntersectedObjects returns the objects of the trees intersecting the target, which may be stored
+// in any of the trees or in none of them
+func (v View) GetIntersectedObjects(target PhysicalObject) IntersectedObjects {
+	return v.collect(func(tree *Quadtree) IntersectedObjects {
+		if tree.FindObject(target) != nil {
+			return tree.GetIntersectedObjects(target)
+		}
+		return tree.intersecting(target, nil)
+	})
+}
+
+// NearestInDirection returns the object of the trees closest to (x, y) whose center lies within
+// maxAngle radians of the direction (dx, dy), see Quadtree.NearestInDirection
+func (v View) NearestInDirection(x, y, dx, dy float64, maxAngle float64) PhysicalObject {
+	var best PhysicalObject
+	bestDistance := math.Inf(1)
+	for _, tree := range v.trees {
+		obj := tree.NearestInDirection(x, y, dx, dy, maxAngle)
+		if obj == nil {
+			continue
+		}
+		cx, cy := center(obj)
+		if d := math.Hypot(cx-x, cy-y); d < bestDistance {
+			best, bestDistance = obj, d
+		}
+	}
+	return best
+}
@@ -0,0 +1,61 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestOverlay(t *testing.T) {
+	terrain := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 16, 16}, 4, 4)
+	actors := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 16, 16}, 4, 4)
+	rock := &TestPhysicalObject{2, 2, 2, 2}
+	bridge := &TestPhysicalObject{3, 8, 4, 1}
+	hero := &TestPhysicalObject{3, 3, 1, 1}
+	villain := &TestPhysicalObject{12, 12, 1, 1}
+	terrain.Insert(rock)
+	terrain.Insert(bridge)
+	actors.Insert(hero)
+	actors.Insert(villain)
+	// an object shared by both trees is returned once
+	actors.Insert(bridge)
+
+	view := quadtree.Overlay(terrain, nil, actors)
+	if len(view.Trees()) != 2 {
+		t.Fatalf("Overlay expects to skip nil trees, got %v trees", len(view.Trees()))
+	}
+	if got := view.Retrieve(&quadtree.Bounds{0, 0, 8, 10}); !sameObjects(got, rock, bridge, hero) {
+		t.Errorf("Retrieve expects the rock, the bridge and the hero, got %v", got)
+	}
+	if got := view.GetIntersectedObjects(hero); !sameObjects(got, rock) {
+		t.Errorf("GetIntersectedObjects expects the rock, got %v", got)
+	}
+	if got := view.NearestInDirection(16, 16, -1, -1, 0.1); got != villain {
+		t.Errorf("NearestInDirection expects the villain, got %v", got)
+	}
+	count := 0
+	view.Walk(func(obj quadtree.PhysicalObject) {
+		count += 1
+	})
+	if count != 4 {
+		t.Errorf("Walk expects 4 objects, got %v", count)
+	}
+}
+
+// sameObjects tells whether got holds exactly the expected objects, in any order
+func sameObjects(got []quadtree.PhysicalObject, expected ...quadtree.PhysicalObject) bool {
+	if len(got) != len(expected) {
+		return false
+	}
+	want := make(map[quadtree.PhysicalObject]bool)
+	for _, obj := range expected {
+		want[obj] = true
+	}
+	for _, obj := range got {
+		if !want[obj] {
+			return false
+		}
+		delete(want, obj)
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package quadtree
+
+import (
+	"math"
+	"sort"
+)
+
+// BuildPacked rebuilds the tree from objs in a single pass, meant for static datasets. Objects are
+// sorted along the curve of the tree (see WithCurveOrder) by their centers, then every node is split at once,
+// so each leaf is filled up to MaxObjects and the objects of neighbouring nodes lie next to each other.
+// Objects with NaN or infinite coordinates are handled according to the InvalidCoordinatesPolicy of the tree
+func (qt *Quadtree) BuildPacked(objs []PhysicalObject) {
+	if !qt.ready() {
+		return
+	}
+	var valid, invalid []PhysicalObject
+	for _, obj := range objs {
+		if validCoordinates(obj) {
+			valid = append(valid, obj)
+		} else {
+			invalid = append(invalid, obj)
+		}
+	}
+
+	codes := make([]uint64, len(valid))
+	for i, obj := range valid {
+		codes[i] = qt.curveCode(obj)
+	}
+	sort.Stable(byCode{codes, valid})
+
+	stored := qt.storedObjects()
+	qt.clear()
+	qt.buildPacked(valid)
+	for _, obj := range invalid {
+		qt.handleInvalid(obj)
+	}
+	qt.reassignIDs()
+	qt.dropLeftIntervals()
+	qt.logRebuild()
+	qt.notifyRebuilt(stored)
+	qt.enforceCapacity()
+}
+
+func (qt *Quadtree) buildPacked(objects []PhysicalObject) {
+	qt.m_Objects.Init()
+	if len(objects) <= qt.maxObjects() || qt.Level >= qt.MaxLevels || qt.unitCell() {
+		for _, obj := range objects {
+			qt.m_Objects.PushBack(obj)
+		}
+		qt.touch(len(objects))
+		return
+	}
+
+	qt.chooseSplitFor(objects)
+	var subtreeObjects [4][]PhysicalObject
+	for _, obj := range objects {
+		placement := qt.placement(obj)
+		if placement == 0 {
+			qt.m_Objects.PushBack(obj)
+			continue
+		}
+		for index := 0; index < 4; index += 1 {
+			if placement&(1<<uint(index)) != 0 {
+				subtreeObjects[index] = append(subtreeObjects[index], obj)
+			}
+		}
+	}
+
+	qt.touch(qt.m_Objects.Len())
+
+	for i, objects := range subtreeObjects {
+		if len(objects) == 0 {
+			continue
+		}
+		qt.Nodes[i] = qt.createSubtree(qt.quadrantBounds(i))
+		qt.m_ActiveNodes |= 1 << uint(i)
+		qt.Nodes[i].buildPacked(objects)
+	}
+}
+
+// byCode sorts objects by their codes
+type byCode struct {
+	codes   []uint64
+	objects []PhysicalObject
+}
+
+func (s byCode) Len() int           { return len(s.codes) }
+func (s byCode) Less(i, j int) bool { return s.codes[i] < s.codes[j] }
+func (s byCode) Swap(i, j int) {
+	s.codes[i], s.codes[j] = s.codes[j], s.codes[i]
+	s.objects[i], s.objects[j] = s.objects[j], s.objects[i]
+}
+
+// mortonCode interleaves the bits of the center of the object, quantized over the bounds of current node
+func (qt *Quadtree) mortonCode(obj PhysicalObject) uint64 {
+	cx, cy := center(obj)
+	return spreadBits(quantize(cx, qt.X, qt.Width)) | spreadBits(quantize(cy, qt.Y, qt.Height))<<1
+}
+
+// quantize maps v within [min, min+size] to a 32 bit cell index, clamping values outside of the range
+func quantize(v, min, size float64) uint32 {
+	if size <= 0 {
+		return 0
+	}
+	f := (v - min) / size * math.MaxUint32
+	if f <= 0 {
+		return 0
+	}
+	if f >= math.MaxUint32 {
+		return math.MaxUint32
+	}
+	return uint32(f)
+}
+
+// spreadBits inserts a zero bit before every bit of v
+func spreadBits(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<16) & 0x0000ffff0000ffff
+	x = (x | x<<8) & 0x00ff00ff00ff00ff
+	x = (x | x<<4) & 0x0f0f0f0f0f0f0f0f
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+	return x
+}
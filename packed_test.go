@@ -0,0 +1,45 @@
+package quadtree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/gen"
+)
+
+func TestBuildPacked(t *testing.T) {
+	bounds := quadtree.Bounds{0, 0, 1000, 1000}
+	objects := gen.Scene(7, 500, gen.WithBounds(bounds))
+
+	packed := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 1000, 1000}, 8, 6)
+	packed.BuildPacked(objects)
+	built := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 1000, 1000}, 8, 6)
+	built.UpdateTree(listOf(objects...))
+
+	if changes := quadtree.Diff(built, packed); len(changes) != 0 {
+		t.Errorf("packed tree expects the same layout as a built one, got changes %v", changes)
+	}
+	count := 0
+	packed.Walk(func(quadtree.PhysicalObject) { count += 1 })
+	if count != len(objects) {
+		t.Errorf("packed tree expects %d objects, got %d", len(objects), count)
+	}
+}
+
+func TestBuildPackedInvalid(t *testing.T) {
+	var dropped []quadtree.PhysicalObject
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 10,
+		quadtree.WithInvalidCoordinatesPolicy(quadtree.InvalidCoordinatesDrop, func(obj quadtree.PhysicalObject) {
+			dropped = append(dropped, obj)
+		}))
+	invalid := &TestPhysicalObject{math.NaN(), 0, 1, 1}
+	qt.BuildPacked([]quadtree.PhysicalObject{&TestPhysicalObject{0, 0, 1, 1}, invalid, &TestPhysicalObject{3, 3, 1, 1}})
+
+	if len(dropped) != 1 || dropped[0] != invalid {
+		t.Errorf("expects the invalid object to be dropped, got %v", dropped)
+	}
+	if qt.Nodes[0] == nil || qt.Nodes[3] == nil {
+		t.Errorf("expects valid objects in the top left and bottom right quadrants")
+	}
+}
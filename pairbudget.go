@@ -0,0 +1,85 @@
+package quadtree
+
+import (
+	"container/list"
+	"math"
+	"sort"
+)
+
+// PairPriority tells whether intersection record a should be kept rather than b when GetIntersection
+// runs out of pair budget
+type PairPriority func(a, b *IntersectionRecord) bool
+
+// ByOverlapArea is a PairPriority keeping the pairs whose bounds overlap the most
+func ByOverlapArea(a, b *IntersectionRecord) bool {
+	return overlapArea(a) > overlapArea(b)
+}
+
+// overlapArea returns the area of the intersection of the bounds of the pair
+func overlapArea(record *IntersectionRecord) float64 {
+	one, another := boundsOf(record.One), boundsOf(record.Another)
+	width := math.Min(one.X+one.Width, another.X+another.Width) - math.Max(one.X, another.X)
+	height := math.Min(one.Y+one.Height, another.Y+another.Height) - math.Max(one.Y, another.Y)
+	return math.Max(width, 0) * math.Max(height, 0)
+}
+
+// pairBudget is the configuration of WithPairBudget, and the outcome of the last GetIntersection
+type pairBudget struct {
+	limit     int
+	priority  PairPriority
+	truncated bool
+}
+
+// WithPairBudget makes GetIntersection return at most limit intersection records per call, keeping the
+// records priority puts first, such as ByOverlapArea. A nil priority keeps the records found first,
+// and stops the search once the budget is spent. PairsTruncated tells whether the last call dropped records
+func WithPairBudget(limit int, priority PairPriority) Option {
+	return func(c *config) {
+		c.pairBudget = &pairBudget{limit: limit, priority: priority}
+	}
+}
+
+// PairsTruncated tells whether the last GetIntersection dropped intersection records to fit the pair
+// budget of the tree
+func (qt *Quadtree) PairsTruncated() bool {
+	if !qt.ready() || qt.m_config.pairBudget == nil {
+		return false
+	}
+	return qt.m_config.pairBudget.truncated
+}
+
+// byPriority orders intersection records by priority
+type byPriority struct {
+	records  []*IntersectionRecord
+	priority PairPriority
+}
+
+func (s byPriority) Len() int           { return len(s.records) }
+func (s byPriority) Swap(i, j int)      { s.records[i], s.records[j] = s.records[j], s.records[i] }
+func (s byPriority) Less(i, j int) bool { return s.priority(s.records[i], s.records[j]) }
+
+// pairsFull tells whether GetIntersection found enough records for a pair budget without priority,
+// which keeps the records found first, to stop looking for more. One record past the limit tells
+// that the result is truncated
+func (c *config) pairsFull(found *list.List) bool {
+	p := c.pairBudget
+	return p != nil && p.priority == nil && found.Len() > p.limit
+}
+
+// keep appends to intersections the records of found fitting the budget
+func (p *pairBudget) keep(found, intersections *list.List) {
+	records := make([]*IntersectionRecord, 0, found.Len())
+	for ele := found.Front(); ele != nil; ele = ele.Next() {
+		records = append(records, ele.Value.(*IntersectionRecord))
+	}
+	p.truncated = len(records) > p.limit
+	if p.truncated {
+		if p.priority != nil {
+			sort.Stable(byPriority{records, p.priority})
+		}
+		records = records[:p.limit]
+	}
+	for _, record := range records {
+		intersections.PushBack(record)
+	}
+}
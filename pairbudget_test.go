@@ -0,0 +1,62 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestPairBudget(t *testing.T) {
+	big := &TestPhysicalObject{0, 0, 4, 4}
+	deep := &TestPhysicalObject{1, 1, 2, 2}
+	shallow := &TestPhysicalObject{2.5, 2.5, 2, 2}
+	far := &TestPhysicalObject{20, 20, 1, 1}
+	objects := []quadtree.PhysicalObject{big, deep, shallow, far}
+
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 32, 32}, 4, 4, quadtree.WithPairBudget(1, quadtree.ByOverlapArea))
+	for _, obj := range objects {
+		qt.Insert(obj)
+	}
+	pairs := qt.GetIntersection(nil, nil)
+	if pairs.Len() != 1 || !qt.PairsTruncated() {
+		t.Fatalf("GetIntersection expects one pair and the truncation flag, got %v pairs", pairs.Len())
+	}
+	record := pairs.Front().Value.(*quadtree.IntersectionRecord)
+	if record.One == shallow || record.Another == shallow {
+		t.Errorf("GetIntersection expects to keep the pair overlapping the most, got %+v", record)
+	}
+
+	qt.Remove(deep)
+	if pairs := qt.GetIntersection(nil, nil); pairs.Len() != 1 || qt.PairsTruncated() {
+		t.Errorf("GetIntersection expects the pairs to fit the budget, got %v pairs", pairs.Len())
+	}
+}
+
+// probedObject counts the reads of its position
+type probedObject struct {
+	TestPhysicalObject
+	reads *int
+}
+
+func (po *probedObject) X() float64 {
+	*po.reads += 1
+	return po.TestPhysicalObject.X()
+}
+
+func TestPairBudgetStopsSearch(t *testing.T) {
+	for _, policy := range []quadtree.StraddlePolicy{quadtree.StraddleKeepAtParent, quadtree.StraddleLoose} {
+		reads := 0
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 32, 32}, 4, 4, quadtree.WithPairBudget(3, nil), quadtree.WithStraddlePolicy(policy))
+		for i := 0; i < 40; i += 1 {
+			qt.Insert(&probedObject{TestPhysicalObject{1, 1, 2, 2}, &reads})
+		}
+		reads = 0
+		if pairs := qt.GetIntersection(nil, nil); pairs.Len() != 3 || !qt.PairsTruncated() {
+			t.Errorf("policy %v expects 3 pairs and the truncation flag, got %v pairs", policy, pairs.Len())
+		}
+		// the 780 pairs of the stacked objects would read positions thousands of times
+		if reads > 200 {
+			t.Errorf("policy %v expects the search to stop at the budget, got %d reads", policy, reads)
+		}
+	}
+}
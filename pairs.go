@@ -0,0 +1,163 @@
+package quadtree
+
+// pairSet is the set of intersecting pairs of a tree, maintained across Updates
+type pairSet struct {
+	adjacent map[PhysicalObject]map[PhysicalObject]bool
+	dirty    map[PhysicalObject]bool // objects inserted or moved since the pairs were last refreshed
+	order    []PhysicalObject        // dirty objects, in the order they changed
+	started  []IntersectionRecord    // pairs which started intersecting since the last PairChanges
+	ended    []IntersectionRecord    // pairs which stopped intersecting since the last PairChanges
+	current  []IntersectionRecord    // intersecting pairs in the order they started, ended pairs leave zero records
+	slots    map[[2]PhysicalObject]int
+	holes    int // zero records of current
+}
+
+// WithPersistentPairs makes the tree maintain its set of intersecting pairs across Updates, rather than
+// computing every pair from scratch like GetIntersection does. Only the pairs of the objects inserted or
+// moved since the last call of Pairs or PairChanges are searched again, so that the cost of a frame is
+// proportional to the number of objects which moved. Objects moving without the tree knowing, that is
+// outside of Update and MoveHandle, keep their pairs until they are seen moving
+func WithPersistentPairs() Option {
+	return func(c *config) {
+		c.pairs = &pairSet{
+			adjacent: make(map[PhysicalObject]map[PhysicalObject]bool),
+			dirty:    make(map[PhysicalObject]bool),
+			slots:    make(map[[2]PhysicalObject]int),
+		}
+	}
+}
+
+// changed records an object whose pairs must be searched again
+func (p *pairSet) changed(obj PhysicalObject) {
+	if !p.dirty[obj] {
+		p.dirty[obj] = true
+		p.order = append(p.order, obj)
+	}
+}
+
+// dropped removes the pairs of an object which left the tree
+func (p *pairSet) dropped(obj PhysicalObject) {
+	for another := range p.adjacent[obj] {
+		p.unlink(obj, another)
+	}
+	delete(p.adjacent, obj)
+	if p.dirty[obj] {
+		delete(p.dirty, obj)
+		for i, one := range p.order {
+			if one == obj {
+				p.order = append(p.order[:i], p.order[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// rebuilt drops the pairs of the objects a rebuilt tree no longer holds, the pairs of the others are
+// searched again
+func (p *pairSet) rebuilt(root *Quadtree) {
+	kept := make(map[PhysicalObject]bool)
+	root.Walk(func(obj PhysicalObject) {
+		kept[obj] = true
+		p.changed(obj)
+	})
+	for obj := range p.adjacent {
+		if !kept[obj] {
+			p.dropped(obj)
+		}
+	}
+}
+
+func (p *pairSet) link(one, another PhysicalObject) {
+	for _, pair := range [2][2]PhysicalObject{{one, another}, {another, one}} {
+		if p.adjacent[pair[0]] == nil {
+			p.adjacent[pair[0]] = make(map[PhysicalObject]bool)
+		}
+		p.adjacent[pair[0]][pair[1]] = true
+	}
+	record := IntersectionRecord{One: one, Another: another}
+	p.slots[[2]PhysicalObject{one, another}] = len(p.current)
+	p.current = append(p.current, record)
+	p.started = append(p.started, record)
+}
+
+func (p *pairSet) unlink(one, another PhysicalObject) {
+	delete(p.adjacent[one], another)
+	delete(p.adjacent[another], one)
+	key := [2]PhysicalObject{one, another}
+	slot, ok := p.slots[key]
+	if !ok {
+		key = [2]PhysicalObject{another, one}
+		slot = p.slots[key]
+	}
+	delete(p.slots, key)
+	p.current[slot] = IntersectionRecord{}
+	p.holes += 1
+	p.ended = append(p.ended, IntersectionRecord{One: one, Another: another})
+}
+
+// compact drops the records of the pairs which ended from current
+func (p *pairSet) compact() {
+	if p.holes == 0 {
+		return
+	}
+	kept := p.current[:0]
+	for _, record := range p.current {
+		if record.One != nil {
+			p.slots[[2]PhysicalObject{record.One, record.Another}] = len(kept)
+			kept = append(kept, record)
+		}
+	}
+	for i := len(kept); i < len(p.current); i += 1 {
+		p.current[i] = IntersectionRecord{}
+	}
+	p.current = kept
+	p.holes = 0
+}
+
+// refreshPairs searches the pairs of the objects which changed
+func (qt *Quadtree) refreshPairs() *pairSet {
+	p := qt.m_config.pairs
+	root := qt.root()
+	for _, obj := range p.order {
+		current := make(map[PhysicalObject]bool)
+		for _, another := range root.intersecting(obj, nil) {
+			current[another] = true
+			if !p.adjacent[obj][another] {
+				p.link(obj, another)
+			}
+		}
+		for another := range p.adjacent[obj] {
+			if !current[another] {
+				p.unlink(obj, another)
+			}
+		}
+	}
+	p.dirty = make(map[PhysicalObject]bool)
+	p.order = p.order[:0]
+	return p
+}
+
+// Pairs returns the intersecting pairs of the tree maintained WithPersistentPairs, every pair once,
+// in the order they started intersecting. Its cost only depends on the objects which moved and the pairs
+// which changed since the previous call. The returned slice is reused by the next calls, callers must
+// not modify it. It returns nil for trees without persistent pairs
+func (qt *Quadtree) Pairs() []IntersectionRecord {
+	if !qt.ready() || qt.m_config.pairs == nil {
+		return nil
+	}
+	p := qt.refreshPairs()
+	p.compact()
+	return p.current
+}
+
+// PairChanges returns the pairs which started and stopped intersecting since the last call, in the
+// order the changes were found, for trees WithPersistentPairs
+func (qt *Quadtree) PairChanges() (started, ended []IntersectionRecord) {
+	if !qt.ready() || qt.m_config.pairs == nil {
+		return nil, nil
+	}
+	p := qt.refreshPairs()
+	started, ended = p.started, p.ended
+	p.started, p.ended = nil, nil
+	return started, ended
+}
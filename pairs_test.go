@@ -0,0 +1,79 @@
+package quadtree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestPersistentPairs(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 16, 16}, 2, 4, quadtree.WithPersistentPairs())
+	a := &steeredObject{TestPhysicalObject: TestPhysicalObject{1, 1, 2, 2}}
+	b := &steeredObject{TestPhysicalObject: TestPhysicalObject{2, 2, 2, 2}}
+	c := &steeredObject{TestPhysicalObject: TestPhysicalObject{10, 10, 2, 2}}
+	for _, obj := range []quadtree.PhysicalObject{a, b, c} {
+		qt.Insert(obj)
+	}
+
+	pairs := qt.Pairs()
+	if len(pairs) != 1 || pairs[0] != (quadtree.IntersectionRecord{One: a, Another: b}) {
+		t.Fatalf("Pairs expects a and b to intersect, got %v", pairs)
+	}
+	started, ended := qt.PairChanges()
+	if len(started) != 1 || len(ended) != 0 {
+		t.Errorf("PairChanges expects the pair of a and b to start, got %v and %v", started, ended)
+	}
+
+	c.moveTo(2.5, 1.5)
+	b.moveTo(12, 12)
+	qt.Update(time.Millisecond)
+	started, ended = qt.PairChanges()
+	if len(started) != 1 || !sameObjects([]quadtree.PhysicalObject{started[0].One, started[0].Another}, a, c) {
+		t.Errorf("PairChanges expects the pair of a and c to start, got %v", started)
+	}
+	if len(ended) != 1 || !sameObjects([]quadtree.PhysicalObject{ended[0].One, ended[0].Another}, a, b) {
+		t.Errorf("PairChanges expects the pair of a and b to end, got %v", ended)
+	}
+	if pairs := qt.Pairs(); len(pairs) != 1 || qt.GetIntersection(nil, nil).Len() != 1 {
+		t.Errorf("Pairs expects to match GetIntersection, got %v", pairs)
+	}
+
+	qt.Remove(c)
+	if pairs := qt.Pairs(); len(pairs) != 0 {
+		t.Errorf("Pairs expects the pairs of removed objects to be dropped, got %v", pairs)
+	}
+	qt.UpdateTree(listOf(a, c))
+	if pairs := qt.Pairs(); len(pairs) != 1 {
+		t.Errorf("Pairs expects the pairs of the rebuilt tree, got %v", pairs)
+	}
+}
+
+func TestPersistentPairsOrder(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 16, 16}, 2, 4, quadtree.WithPersistentPairs())
+	a := &steeredObject{TestPhysicalObject: TestPhysicalObject{1, 1, 2, 2}}
+	b := &steeredObject{TestPhysicalObject: TestPhysicalObject{2, 2, 2, 2}}
+	c := &steeredObject{TestPhysicalObject: TestPhysicalObject{10, 10, 2, 2}}
+	d := &steeredObject{TestPhysicalObject: TestPhysicalObject{11, 11, 2, 2}}
+	for _, obj := range []quadtree.PhysicalObject{a, b, c, d} {
+		qt.Insert(obj)
+	}
+	pairs := qt.Pairs()
+	if len(pairs) != 2 {
+		t.Fatalf("Pairs expects 2 pairs, got %v", pairs)
+	}
+	if again := qt.Pairs(); &again[0] != &pairs[0] {
+		t.Error("Pairs expects to reuse the pairs when nothing changed")
+	}
+
+	// the pair of a and b ends, the pair of b and e starts after the one of c and d
+	e := &steeredObject{TestPhysicalObject: TestPhysicalObject{6, 6, 2, 2}}
+	qt.Insert(e)
+	b.moveTo(5, 5)
+	qt.Update(time.Millisecond)
+	pairs = qt.Pairs()
+	if len(pairs) != 2 || !sameObjects([]quadtree.PhysicalObject{pairs[0].One, pairs[0].Another}, c, d) ||
+		!sameObjects([]quadtree.PhysicalObject{pairs[1].One, pairs[1].Another}, b, e) {
+		t.Errorf("Pairs expects the pairs in the order they started, got %v", pairs)
+	}
+}
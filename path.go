@@ -0,0 +1,102 @@
+package quadtree
+
+import (
+	"math"
+)
+
+// Point is a location in the coordinate space of the tree
+type Point struct {
+	X, Y float64
+}
+
+// pointSegmentDistance returns the distance from p to the segment [a, b]
+func pointSegmentDistance(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/length))
+	}
+	return math.Hypot(p.X-(a.X+t*dx), p.Y-(a.Y+t*dy))
+}
+
+// segmentCrossesBounds checks whether the segment [a, b] has any point within r (Liang-Barsky clipping)
+func segmentCrossesBounds(a, b Point, r *Bounds) bool {
+	t0, t1 := 0.0, 1.0
+	dx, dy := b.X-a.X, b.Y-a.Y
+	for _, edge := range [4][2]float64{
+		{-dx, a.X - r.X},
+		{dx, r.X + r.Width - a.X},
+		{-dy, a.Y - r.Y},
+		{dy, r.Y + r.Height - a.Y},
+	} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return false
+			}
+			continue
+		}
+		t := q / p
+		if p < 0 {
+			t0 = math.Max(t0, t)
+		} else {
+			t1 = math.Min(t1, t)
+		}
+		if t0 > t1 {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentBoundsDistance returns the distance between the segment [a, b] and r, 0 if they cross
+func segmentBoundsDistance(a, b Point, r *Bounds) float64 {
+	if segmentCrossesBounds(a, b, r) {
+		return 0
+	}
+	distance := math.Min(pointBoundsDistance(a.X, a.Y, r), pointBoundsDistance(b.X, b.Y, r))
+	for _, corner := range [4]Point{
+		{r.X, r.Y}, {r.X + r.Width, r.Y}, {r.X, r.Y + r.Height}, {r.X + r.Width, r.Y + r.Height},
+	} {
+		distance = math.Min(distance, pointSegmentDistance(corner, a, b))
+	}
+	return distance
+}
+
+// pathBoundsDistance returns the distance between the polyline through points and r
+func pathBoundsDistance(points []Point, r *Bounds) float64 {
+	if len(points) == 1 {
+		return pointBoundsDistance(points[0].X, points[0].Y, r)
+	}
+	distance := math.Inf(1)
+	for i := 1; i < len(points) && distance > 0; i += 1 {
+		distance = math.Min(distance, segmentBoundsDistance(points[i-1], points[i], r))
+	}
+	return distance
+}
+
+// QueryAlongPath returns the objects whose bounds lie within radius of the polyline through points,
+// visiting only the nodes the inflated path touches. A single point queries a disc around it
+func (qt *Quadtree) QueryAlongPath(points []Point, radius float64, opts ...QueryOption) IntersectedObjects {
+	if !qt.ready() {
+		return nil
+	}
+	if len(points) == 0 {
+		return nil
+	}
+	qc := newQueryConfig(opts)
+	objects := qc.resultBuffer(qt.m_config)
+	qt.visitWhere(qc.instrument(qt,
+		func(b *Bounds) bool {
+			return pathBoundsDistance(points, b) <= radius
+		},
+		func(obj PhysicalObject) {
+			if pathBoundsDistance(points, boundsOf(obj)) <= radius {
+				objects = append(objects, obj)
+			}
+		},
+	))
+	qc.lend(qt.m_config, objects)
+	qc.report(qt.m_config)
+	return objects
+}
@@ -0,0 +1,32 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/quadtreetest"
+)
+
+func TestQueryAlongPath(t *testing.T) {
+	onPath := &TestPhysicalObject{2, 0.5, 1, 1}
+	nearCorner := &TestPhysicalObject{9.2, 1.5, 0.5, 0.5}
+	nearEnd := &TestPhysicalObject{9.5, 8.5, 1, 1}
+	away := &TestPhysicalObject{1, 8, 1, 1}
+	insideBend := &TestPhysicalObject{6, 4, 1, 1}
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 12, 12}, 1, 10)
+	qt.UpdateTree(listOf(onPath, nearCorner, nearEnd, away, insideBend))
+
+	path := []quadtree.Point{{0, 1}, {10, 1}, {10, 8}}
+	expected := quadtree.IntersectedObjects{onPath, nearCorner, nearEnd}
+	if result := qt.QueryAlongPath(path, 0.6); !quadtreetest.SameObjects(result, expected) {
+		t.Errorf("expects objects:\n%s\nBut got:\n%s", quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(result))
+	}
+
+	expected = quadtree.IntersectedObjects{away}
+	if result := qt.QueryAlongPath([]quadtree.Point{{1.5, 7}}, 1); !quadtreetest.SameObjects(result, expected) {
+		t.Errorf("single point expects objects:\n%s\nBut got:\n%s", quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(result))
+	}
+	if result := qt.QueryAlongPath(nil, 100); len(result) != 0 {
+		t.Errorf("empty path expects no objects, got %d", len(result))
+	}
+}
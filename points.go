@@ -0,0 +1,145 @@
+package quadtree
+
+// PointBatch identifies a batch of points inserted with InsertPoints
+type PointBatch int
+
+// pointBatch holds the coordinates of the points of a batch, nil for a removed batch
+type pointBatch struct {
+	xs, ys []float32
+}
+
+// pointStore keeps the batches of points of a tree, outside of its nodes
+type pointStore struct {
+	batches []*pointBatch
+	free    []PointBatch
+	scratch [][]int32 // indices of the points within the nodes of each level, reused by CollidePoints
+}
+
+// InsertPoints adds a batch of points, such as particles, to the tree. Points are not PhysicalObjects:
+// they are kept apart from the nodes in flat slices, cost no interface calls, and are meant to be
+// tested against the objects of the tree by CollidePoints, then dropped all at once by RemovePoints.
+// xs and ys hold the coordinates of the points, the extra coordinates of the longer slice are ignored.
+// The tree keeps the slices, which must not change while the batch is in the tree
+func (qt *Quadtree) InsertPoints(xs, ys []float32) PointBatch {
+	if !qt.ready() {
+		return -1
+	}
+	if len(ys) < len(xs) {
+		xs = xs[:len(ys)]
+	}
+	c := qt.m_config
+	if c.points == nil {
+		c.points = &pointStore{}
+	}
+	store := c.points
+	batch := &pointBatch{xs: xs, ys: ys[:len(xs)]}
+	if n := len(store.free); n > 0 {
+		b := store.free[n-1]
+		store.free = store.free[:n-1]
+		store.batches[b] = batch
+		return b
+	}
+	store.batches = append(store.batches, batch)
+	return PointBatch(len(store.batches) - 1)
+}
+
+// batch returns the points of the batch, nil if it is not in the tree
+func (store *pointStore) batch(b PointBatch) *pointBatch {
+	if store == nil || b < 0 || int(b) >= len(store.batches) {
+		return nil
+	}
+	return store.batches[b]
+}
+
+// RemovePoints drops the whole batch, and returns false if it is not in the tree
+func (qt *Quadtree) RemovePoints(b PointBatch) bool {
+	if !qt.ready() {
+		return false
+	}
+	store := qt.m_config.points
+	if store.batch(b) == nil {
+		return false
+	}
+	store.batches[b] = nil
+	store.free = append(store.free, b)
+	return true
+}
+
+// CollidePoints calls fn with the index of every point of the batch lying within an object of the
+// tree, and the object. The tree is traversed once for the whole batch, every node testing only the
+// points within its bounds
+func (qt *Quadtree) CollidePoints(b PointBatch, fn func(point int, obj PhysicalObject)) {
+	if !qt.ready() {
+		return
+	}
+	store := qt.m_config.points
+	batch := store.batch(b)
+	if batch == nil || len(batch.xs) == 0 {
+		return
+	}
+	if len(store.scratch) == 0 {
+		store.scratch = append(store.scratch, nil)
+	}
+	all := store.scratch[0][:0]
+	for i := range batch.xs {
+		all = append(all, int32(i))
+	}
+	store.scratch[0] = all
+	var seen map[[2]interface{}]bool
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		seen = make(map[[2]interface{}]bool)
+	}
+	qt.collidePoints(batch, all, 0, func(point int, obj PhysicalObject) {
+		if seen != nil {
+			key := [2]interface{}{point, obj}
+			if seen[key] {
+				return
+			}
+			seen[key] = true
+		}
+		fn(point, obj)
+	})
+}
+
+// collidePoints tests the points of the batch with the given indices, which lie within current node,
+// against the objects of the subtree. depth is the depth of current node below the node queried
+func (qt *Quadtree) collidePoints(batch *pointBatch, indices []int32, depth int, fn func(point int, obj PhysicalObject)) {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		obj := ele.Value.(PhysicalObject)
+		if qt.buried(obj) {
+			continue
+		}
+		left, top := obj.X(), obj.Y()
+		right, bottom := left+obj.Width(), top+obj.Height()
+		for _, i := range indices {
+			x, y := float64(batch.xs[i]), float64(batch.ys[i])
+			if x >= left && x <= right && y >= top && y <= bottom {
+				fn(int(i), obj)
+			}
+		}
+	}
+
+	store := qt.m_config.points
+	if len(store.scratch) <= depth+1 {
+		store.scratch = append(store.scratch, nil)
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			b := qt.Nodes[index].searchBounds()
+			within := store.scratch[depth+1][:0]
+			for _, i := range indices {
+				if b.ContainsPoint(float64(batch.xs[i]), float64(batch.ys[i])) {
+					within = append(within, i)
+				}
+			}
+			store.scratch[depth+1] = within
+			if len(within) > 0 {
+				qt.Nodes[index].collidePoints(batch, within, depth+1, fn)
+			}
+		}
+		flags >>= 1
+		index += 1
+	}
+}
@@ -0,0 +1,60 @@
+package quadtree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestPointBatch(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 64, 64}, 2, 6)
+	var walls []*TestPhysicalObject
+	for i := 0; i < 30; i += 1 {
+		wall := &TestPhysicalObject{rng.Float64() * 60, rng.Float64() * 60, 1 + rng.Float64()*3, 1 + rng.Float64()*3}
+		walls = append(walls, wall)
+		qt.Insert(wall)
+	}
+	xs := make([]float32, 2000)
+	ys := make([]float32, 2000)
+	for i := range xs {
+		xs[i], ys[i] = float32(rng.Float64()*64), float32(rng.Float64()*64)
+	}
+
+	batch := qt.InsertPoints(xs, ys)
+	hits := make(map[[2]int]bool)
+	qt.CollidePoints(batch, func(point int, obj quadtree.PhysicalObject) {
+		for w, wall := range walls {
+			if quadtree.PhysicalObject(wall) == obj {
+				hits[[2]int{point, w}] = true
+			}
+		}
+	})
+	expected := 0
+	for i := range xs {
+		x, y := float64(xs[i]), float64(ys[i])
+		for w, wall := range walls {
+			if x >= wall.x && x <= wall.x+wall.width && y >= wall.y && y <= wall.y+wall.height {
+				expected += 1
+				if !hits[[2]int{i, w}] {
+					t.Errorf("expects point %d to hit wall %d", i, w)
+				}
+			}
+		}
+	}
+	if expected == 0 || len(hits) != expected {
+		t.Errorf("expects %d hits, got %d", expected, len(hits))
+	}
+
+	other := qt.InsertPoints([]float32{1}, []float32{1, 2})
+	if !qt.RemovePoints(batch) || qt.RemovePoints(batch) {
+		t.Error("expects the batch to be removed once")
+	}
+	qt.CollidePoints(batch, func(int, quadtree.PhysicalObject) {
+		t.Error("expects no collision for a removed batch")
+	})
+	if reused := qt.InsertPoints(xs, ys); reused != batch || other == batch {
+		t.Errorf("expects the slot of the removed batch to be reused, got %d", reused)
+	}
+}
@@ -0,0 +1,20 @@
+//go:build !tinygo
+// +build !tinygo
+
+package quadtree
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// labeled runs fn under the labels of ctx and pprof labels naming the operation and the number of objects
+func labeled(ctx context.Context, op string, objects int, fn func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	labels := pprof.Labels("quadtree_op", op, "quadtree_objects", objectCountBucket(objects))
+	pprof.Do(ctx, labels, func(context.Context) {
+		fn()
+	})
+}
@@ -0,0 +1,13 @@
+//go:build tinygo
+// +build tinygo
+
+package quadtree
+
+import (
+	"context"
+)
+
+// labeled runs fn, TinyGo has no profiler labels
+func labeled(ctx context.Context, op string, objects int, fn func()) {
+	fn()
+}
@@ -0,0 +1,32 @@
+package quadtree
+
+// Preallocate creates every missing node of the tree down to the given level, bounded by MaxLevels,
+// so that the objects spawning at the start of a game descend into existing nodes instead of splitting
+// nodes progressively. Objects already stored move down into the new nodes they fit in. The nodes
+// created are not pruned by Update until an object enters them, from then on they are pruned like any
+// other once empty. WithHysteresis still merges the skeleton of subtrees holding too few objects
+func (qt *Quadtree) Preallocate(depth int) {
+	if !qt.ready() {
+		return
+	}
+	root := qt.root()
+	root.preallocate(depth)
+	root.build()
+}
+
+func (qt *Quadtree) preallocate(depth int) {
+	if qt.Level >= depth || qt.Level >= qt.MaxLevels || qt.unitCell() {
+		return
+	}
+	if qt.m_ActiveNodes == 0 && !qt.m_splitSet {
+		qt.chooseSplitFor(qt.Objects())
+	}
+	for index := 0; index < 4; index += 1 {
+		if qt.m_ActiveNodes&(1<<uint(index)) == 0 {
+			qt.Nodes[index] = qt.createSubtree(qt.quadrantBounds(index))
+			qt.Nodes[index].m_reserved = true
+			qt.m_ActiveNodes |= 1 << uint(index)
+		}
+		qt.Nodes[index].preallocate(depth)
+	}
+}
@@ -0,0 +1,65 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestPreallocate(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 2, 3)
+	qt.Preallocate(2)
+	if stats := qt.Stats(); stats.Nodes != 1+4+16 || stats.Depth != 2 {
+		t.Errorf("expects the full skeleton down to level 2, got %+v", stats)
+	}
+	obj := &TestPhysicalObject{0.25, 0.25, 0.5, 0.5}
+	qt.Insert(obj)
+	if node := qt.FindObject(obj); node == nil || node.Key() != "00" {
+		t.Errorf("expects the object to descend into the skeleton, got %v", node)
+	}
+
+	qt.Preallocate(10)
+	if stats := qt.Stats(); stats.Depth != 3 || stats.Nodes != 1+4+16+64 {
+		t.Errorf("expects the skeleton to stop at MaxLevels, got %+v", stats)
+	}
+	if node := qt.FindObject(obj); node == nil || node.Key() != "000" {
+		t.Errorf("expects the object to move down into the new nodes, got %v", node)
+	}
+}
+
+func TestPreallocateMovesStoredObjects(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 10, 3)
+	inside := &TestPhysicalObject{5, 1, 1, 1}
+	straddler := &TestPhysicalObject{3.5, 3.5, 1, 1}
+	qt.Insert(inside)
+	qt.Insert(straddler)
+	qt.Preallocate(2)
+	if node := qt.FindObject(inside); node == nil || node.Key() != "10" {
+		t.Errorf("expects the stored object to move down into the skeleton, got %v", node)
+	}
+	if node := qt.FindObject(straddler); node == nil || node.Key() != "" {
+		t.Errorf("expects the object straddling the split point to stay at the root, got %v", node)
+	}
+}
+
+func TestPreallocatedNodesKept(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 3, quadtree.WithLifespan(2, 2))
+	qt.Preallocate(2)
+	for i := 0; i < 100; i += 1 {
+		qt.Update(0)
+	}
+	if stats := qt.Stats(); stats.Nodes != 1+4+16 {
+		t.Errorf("expects the unused skeleton to be kept, got %+v", stats)
+	}
+
+	// once an object entered a node, the node is pruned like any other when left empty
+	obj := &TestPhysicalObject{0.5, 0.5, 1, 1}
+	qt.Insert(obj)
+	qt.Remove(obj)
+	for i := 0; i < 4; i += 1 {
+		qt.Update(0)
+	}
+	if stats := qt.Stats(); stats.Nodes != 1+4+15 {
+		t.Errorf("expects the node emptied to be pruned, got %+v", stats)
+	}
+}
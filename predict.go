@@ -0,0 +1,75 @@
+package quadtree
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Vector is a displacement or a velocity in the coordinate space of the tree, velocities are per second
+type Vector struct {
+	X, Y float64
+}
+
+// PredictedHit is an object a moving object will touch, and when
+type PredictedHit struct {
+	Object PhysicalObject
+	Time   time.Duration // time until the bounds first touch, 0 if they already overlap
+}
+
+// byTime orders hits by time
+type byTime []PredictedHit
+
+func (s byTime) Len() int           { return len(s) }
+func (s byTime) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byTime) Less(i, j int) bool { return s[i].Time < s[j].Time }
+
+// PredictIntersections sweeps the bounds of obj moving at velocity for horizon, and returns the objects
+// of the tree its bounds will touch, soonest first. Objects of the tree are assumed not to move,
+// obj itself is left out
+func (qt *Quadtree) PredictIntersections(obj PhysicalObject, velocity Vector, horizon time.Duration) []PredictedHit {
+	if !qt.ready() || horizon < 0 {
+		return nil
+	}
+	start := boundsOf(obj)
+	seconds := horizon.Seconds()
+	end := Bounds{start.X + velocity.X*seconds, start.Y + velocity.Y*seconds, start.Width, start.Height}
+	swept := start.Union(&end)
+
+	var hits []PredictedHit
+	qt.visitRegion(&swept, func(other PhysicalObject) {
+		if qt.same(other, obj) {
+			return
+		}
+		if t, ok := sweepTime(start, velocity, boundsOf(other)); ok && t <= seconds {
+			hits = append(hits, PredictedHit{other, time.Duration(t * float64(time.Second))})
+		}
+	})
+	sort.Stable(byTime(hits))
+	return hits
+}
+
+// sweepTime returns the first time, not before 0, at which b moving at velocity touches target
+func sweepTime(b *Bounds, velocity Vector, target *Bounds) (float64, bool) {
+	enter, exit := 0.0, math.Inf(1)
+	for _, axis := range [2][5]float64{
+		{b.X, b.Width, target.X, target.Width, velocity.X},
+		{b.Y, b.Height, target.Y, target.Height, velocity.Y},
+	} {
+		position, size, targetPosition, targetSize, v := axis[0], axis[1], axis[2], axis[3], axis[4]
+		// the intervals touch while targetPosition-size <= position+v*t <= targetPosition+targetSize
+		low, high := targetPosition-size-position, targetPosition+targetSize-position
+		if v == 0 {
+			if low > 0 || high < 0 {
+				return 0, false
+			}
+			continue
+		}
+		t0, t1 := low/v, high/v
+		if t0 > t1 {
+			t0, t1 = t1, t0
+		}
+		enter, exit = math.Max(enter, t0), math.Min(exit, t1)
+	}
+	return enter, enter <= exit
+}
@@ -0,0 +1,38 @@
+package quadtree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestPredictIntersections(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 100, 100}, 1, 10)
+	near := &TestPhysicalObject{20, 9, 2, 2}
+	far := &TestPhysicalObject{60, 10, 2, 2}
+	beside := &TestPhysicalObject{30, 30, 2, 2}
+	behind := &TestPhysicalObject{0, 10, 2, 2}
+	for _, obj := range []*TestPhysicalObject{far, near, beside, behind} {
+		qt.Insert(obj)
+	}
+	bullet := &TestPhysicalObject{10, 10, 1, 1}
+	qt.Insert(bullet)
+
+	hits := qt.PredictIntersections(bullet, quadtree.Vector{X: 10}, 10*time.Second)
+	if len(hits) != 2 || hits[0].Object != near || hits[1].Object != far {
+		t.Fatalf("expects near then far to be hit, got %+v", hits)
+	}
+	// the bullet spans [10, 11] and touches near at 20 after 0.9s, far at 60 after 4.9s
+	if hits[0].Time != 900*time.Millisecond || hits[1].Time != 4900*time.Millisecond {
+		t.Errorf("expects hits after 0.9s and 4.9s, got %v and %v", hits[0].Time, hits[1].Time)
+	}
+
+	if hits := qt.PredictIntersections(bullet, quadtree.Vector{X: 10}, time.Second); len(hits) != 1 {
+		t.Errorf("expects only near within a second, got %+v", hits)
+	}
+	overlapping := &TestPhysicalObject{20.5, 10, 1, 1}
+	if hits := qt.PredictIntersections(overlapping, quadtree.Vector{}, time.Second); len(hits) != 1 || hits[0].Time != 0 {
+		t.Errorf("expects an immediate hit for overlapping bounds, got %+v", hits)
+	}
+}
@@ -0,0 +1,63 @@
+package quadtree
+
+import (
+	"context"
+)
+
+// WithProfilerLabels makes Build, Update and GetIntersection run under pprof labels naming the
+// operation ("quadtree_op") and the order of magnitude of the number of objects in the tree
+// ("quadtree_objects"), so that CPU profiles attribute time to specific quadtree operations.
+// Labels are not supported by TinyGo builds, where the option has no effect. The labels the caller
+// runs under are lost unless given with SetProfilerContext
+func WithProfilerLabels() Option {
+	return func(c *config) {
+		c.profilerLabels = true
+	}
+}
+
+// SetProfilerContext sets the context carrying the pprof labels of the caller, such as the context
+// of its pprof.Do, so that the labels of WithProfilerLabels are added to them rather than replacing
+// them, and the labels of the caller are restored once an operation returns
+func (qt *Quadtree) SetProfilerContext(ctx context.Context) {
+	if qt.ready() {
+		qt.m_config.profilerContext = ctx
+	}
+}
+
+// profile runs fn, under pprof labels if they are enabled
+func (qt *Quadtree) profile(op string, fn func()) {
+	if !qt.m_config.profilerLabels {
+		fn()
+		return
+	}
+	labeled(qt.m_config.profilerContext, op, qt.root().m_objectCount, fn)
+}
+
+// countObjects returns the number of objects stored in the subtree
+func (qt *Quadtree) countObjects() int {
+	count := qt.m_Objects.Len()
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			count += qt.Nodes[index].countObjects()
+		}
+		flags >>= 1
+		index += 1
+	}
+	return count
+}
+
+var objectCountBuckets = [...]string{"<10", "<100", "<1000", "<10000", "<100000"}
+
+// objectCountBucket returns the order of magnitude of n as a label value
+func objectCountBucket(n int) string {
+	limit := 10
+	for _, bucket := range objectCountBuckets {
+		if n < limit {
+			return bucket
+		}
+		limit *= 10
+	}
+	return ">=100000"
+}
@@ -0,0 +1,13 @@
+package quadtree
+
+import (
+	"testing"
+)
+
+func TestObjectCountBucket(t *testing.T) {
+	for n, expected := range map[int]string{0: "<10", 9: "<10", 10: "<100", 999: "<1000", 99999: "<100000", 100000: ">=100000"} {
+		if bucket := objectCountBucket(n); bucket != expected {
+			t.Errorf("objectCountBucket(%d) = %q, expects %q", n, bucket, expected)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package quadtree_test
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestProfilerLabels(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 10, quadtree.WithProfilerLabels())
+	qt.UpdateTree(listOf(
+		&TestPhysicalObject{0, 0, 1, 1},
+		&TestPhysicalObject{0.5, 0.5, 1, 1},
+		&TestPhysicalObject{3, 3, 1, 1},
+	))
+	qt.Update(0)
+	if records := qt.GetIntersection(nil, nil); records.Len() != 1 {
+		t.Errorf("expects 1 intersection record under profiler labels, got %d", records.Len())
+	}
+
+	// the labels of the operations are added to those of the caller
+	pprof.Do(context.Background(), pprof.Labels("frame", "1"), func(ctx context.Context) {
+		qt.SetProfilerContext(ctx)
+		qt.Update(0)
+	})
+	if records := qt.GetIntersection(nil, nil); records.Len() != 1 {
+		t.Errorf("expects 1 intersection record under the labels of the caller, got %d", records.Len())
+	}
+}
@@ -2,17 +2,26 @@ package quadtree
 
 import (
 	"container/list"
+	"errors"
 	"math"
+	"strconv"
 	"time"
 )
 
 var (
-// Logger, _ = zap.NewDevelopmentConfig().Build()
+	// Logger, _ = zap.NewDevelopmentConfig().Build()
+
+	// ErrInvalidCoordinates is returned by Insert when the object has NaN or infinite coordinates
+	ErrInvalidCoordinates = errors.New("quadtree: object has NaN or infinite coordinates")
+	// ErrNilQuadtree is returned when inserting into a nil *Quadtree
+	ErrNilQuadtree = errors.New("quadtree: nil tree")
+	// ErrDuplicateObject is returned by Insert when the object is already in a tree rejecting duplicates
+	ErrDuplicateObject = errors.New("quadtree: object already in the tree")
 )
 
 type PhysicalObject interface {
-	X() float64                // X dimension of top left corner
-	Y() float64                // Y dimension of top left corner
+	X() float64                // X dimension of top left corner (bottom left with YUp)
+	Y() float64                // Y dimension of top left corner (bottom left with YUp)
 	Width() float64            // width of the object
 	Height() float64           // height of the object
 	Update(time.Duration) bool // update positions of moving object
@@ -55,6 +64,11 @@ type Bounds struct {
 	X, Y, Width, Height float64
 }
 
+// OriginCenteredBounds returns bounds of the given size centered at (0, 0), which is how most game worlds are laid out
+func OriginCenteredBounds(width, height float64) *Bounds {
+	return &Bounds{-width / 2, -height / 2, width, height}
+}
+
 // whether the physical object resides completely within bounding area of current tree, border overlaps are allowed
 func (b *Bounds) Contains(obj PhysicalObject) bool {
 	return obj.X() >= b.X &&
@@ -63,18 +77,36 @@ func (b *Bounds) Contains(obj PhysicalObject) bool {
 		obj.Y()+obj.Height() <= b.Y+b.Height
 }
 
-// Quadtree - The quadtree data structure
+// Quadtree - The quadtree data structure.
+// A zero value Quadtree keeps every object in its root node, use Init to give it bounds and options.
+// Methods treat a nil *Quadtree as an empty tree
 type Quadtree struct {
 	*Bounds                    // bounds of current node
 	MaxObjects    int          // Maximum objects a node can hold before splitting into 4 subnodes
 	MaxLevels     int          // max number of objects in a node
 	Level         int          // max level, that is, the maximum number of times a tree can be splitted up
 	m_Objects     *list.List   // a list of physical objects that belongs to current node, but not children
-	Nodes         [4]*Quadtree // child nodes
+	Nodes         [4]*Quadtree // child nodes, named by QuadrantName
 	m_ActiveNodes byte
 	m_curLife     int
 	m_maxLifespan int
+	m_idleTicks   int // number of consecutive Updates this node has been empty
+	m_underTicks  int // number of consecutive Updates the subtree held too few objects, with WithHysteresis
+	m_splitX      float64
+	m_splitY      float64
+	m_splitSet    bool   // whether the node splits at (m_splitX, m_splitY) instead of its midpoint
+	m_reserved    bool   // created by Preallocate and never entered by an object, kept from pruning
+	m_version     uint64 // change stamp of the last change of the objects of this node
+	m_stamp       uint64 // change stamp of the last change of the objects of this subtree
+	m_clock       uint64 // last change stamp handed out, kept by the root
+	m_objectCount int    // number of objects stored in the tree, kept by the root
+	m_nodeCount   int    // number of nodes below the root, kept by the root
+	m_evicting    bool   // whether the evictor is running, kept by the root
 	m_parent      *Quadtree
+	m_config      *config           // options shared by every node of the tree
+	m_watchers    []*watcher        // region watchers, registered on the root
+	m_aggregates  []cachedAggregate // values of the aggregators over the subtree
+	m_liveCount   cachedAggregate   // number of objects of the subtree not flagged by MarkRemoved
 }
 
 // intersection infomation between two physical objects
@@ -83,87 +115,148 @@ type IntersectionRecord struct {
 	Another PhysicalObject
 }
 
-// BuildTree determines whether to subdevide according to number of m_Objects, and the current level.
-// Upon subdeviding, it only create&replace neccessary sub trees
+// Build determines whether to subdevide according to number of m_Objects, and the current level.
+// Upon subdeviding, it only create neccessary sub trees. Build is safe to call at any time:
+// once a node is subdevided, objects residing in it are redistributed into its existing children,
+// missing children are created, and existing children are built recursively
 func (qt *Quadtree) Build() {
-	if qt.m_Objects.Len() <= qt.MaxObjects || qt.Level >= qt.MaxLevels {
+	if !qt.ready() {
 		return
 	}
+	qt.profile("Build", qt.build)
+}
 
-	subBounds := [4]*Bounds{
-		// top left
-		&Bounds{qt.X, qt.Y, qt.Width / 2, qt.Height / 2},
-		// top right
-		&Bounds{qt.X + qt.Width/2, qt.Y, qt.Width / 2, qt.Height / 2},
-		// bottom left
-		&Bounds{qt.X, qt.Y + qt.Height/2, qt.Width / 2, qt.Height / 2},
-		// bottom right
-		&Bounds{qt.X + qt.Width/2, qt.Y + qt.Height/2, qt.Width / 2, qt.Height / 2},
+func (qt *Quadtree) build() {
+	if qt.m_ActiveNodes == 0 {
+		if qt.m_Objects.Len() <= qt.maxObjects() || qt.Level >= qt.MaxLevels || qt.unitCell() {
+			qt.checkOverflow()
+			return
+		}
+		qt.chooseSplit()
 	}
 
 	var subtreeObjects [4][]PhysicalObject
-
-	horizontalMidpoint := qt.X + (qt.Width / 2)
-	verticalMidpoint := qt.Y + (qt.Height / 2)
 	var delist []*list.Element
 
 	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
 		obj := ele.Value.(PhysicalObject)
+		placement := qt.placement(obj)
+		// Logger.Info("object placement", zap.Uint8("placement", placement))
 
-		topPart := (obj.Y() >= qt.Y) && (obj.Y()+obj.Height() <= verticalMidpoint)
-		bottomPart := (obj.Y() >= verticalMidpoint) && (obj.Y()+obj.Height() <= qt.Height)
-		leftPart := (obj.X() >= qt.X) && (obj.X()+obj.Width() <= horizontalMidpoint)
-		rightPart := (obj.X() >= horizontalMidpoint) && (obj.X()+obj.Width() <= qt.Width)
-
-		index := -1
-		// obj can completely fit within the left quadrants
-		if topPart {
-			if leftPart {
-				index = 0
-			} else if rightPart {
-				index = 1
-			}
-		} else if bottomPart {
-			if leftPart {
-				index = 2
-			} else if rightPart {
-				index = 3
-			}
-		}
-		// Logger.Info("object index", zap.Int("index", index))
-
-		if index != -1 {
+		if placement != 0 {
 			delist = append(delist, ele)
-			subtreeObjects[index] = append(subtreeObjects[index], obj)
+			for index := 0; index < 4; index += 1 {
+				if placement&(1<<uint(index)) != 0 {
+					subtreeObjects[index] = append(subtreeObjects[index], obj)
+				}
+			}
 		}
 	}
 
 	for _, ele := range delist {
 		qt.m_Objects.Remove(ele)
 	}
+	if len(delist) > 0 {
+		qt.touch(-len(delist))
+	}
 
 	for i, objects := range subtreeObjects {
-		if len(objects) > 0 {
-			qt.Nodes[i] = qt.createSubtree(subBounds[i], objects...)
-			qt.Nodes[i].Build()
+		if qt.m_ActiveNodes&(1<<uint(i)) != 0 {
+			for _, obj := range objects {
+				qt.Nodes[i].m_Objects.PushBack(obj)
+			}
+			if len(objects) > 0 {
+				qt.Nodes[i].touch(len(objects))
+			}
+		} else if len(objects) > 0 {
+			qt.Nodes[i] = qt.createSubtree(qt.quadrantBounds(i), objects...)
 			qt.m_ActiveNodes |= 1 << uint(i)
+			qt.Nodes[i].touch(len(objects))
+		} else {
+			continue
 		}
+		qt.Nodes[i].build()
 	}
 }
 
 // UpdateTree rebuild the tree using the specified objects
 func (qt *Quadtree) UpdateTree(objects *list.List) {
-	qt.m_ActiveNodes = 0
-	qt.Nodes = [4]*Quadtree{}
+	if !qt.ready() {
+		return
+	}
+	stored := qt.storedObjects()
+	qt.clear()
 	qt.m_Objects = objects
+	qt.touch(objects.Len())
 	qt.Build()
+	qt.reassignIDs()
+	qt.dropLeftIntervals()
+	qt.logRebuild()
+	qt.notifyRebuilt(stored)
+	qt.enforceCapacity()
 }
 
 // Update physical objects and maintain states of the tree
 func (qt *Quadtree) Update(delta time.Duration) {
+	if !qt.ready() {
+		return
+	}
+	qt.m_config.simulation = nil
+	qt.tick(delta)
+	qt.relocateDue()
+	qt.runUpdate(delta)
+}
+
+// runUpdate runs Update once the throttled regions were ticked
+func (qt *Quadtree) runUpdate(delta time.Duration) {
+	qt.expire(delta)
+	qt.recordUpdate(delta, func() {
+		qt.profile("Update", func() {
+			if qt.m_config.straddlePolicy != StraddleDuplicate {
+				if !qt.onlyIntervals() {
+					qt.update(delta, nil)
+				}
+				qt.flushRelocations()
+			} else {
+				qt.updateDuplicates(delta)
+			}
+			if qt.m_config.hysteresis != nil {
+				qt.collapseUnderfull()
+			}
+		})
+	})
+	qt.enforceCapacity()
+	qt.root().notifyWatchers()
+	qt.sampleStats()
+	for _, stream := range qt.m_config.streams {
+		stream.advance(delta)
+	}
+}
+
+// updateDuplicates updates objects stored in several nodes once, and reinserts them from the root if they moved
+func (qt *Quadtree) updateDuplicates(delta time.Duration) {
+	tick := &updateTick{updated: make(map[PhysicalObject]bool)}
+	qt.update(delta, tick)
+	for _, obj := range tick.moved {
+		if !validCoordinates(obj) {
+			qt.dropInvalid(obj)
+			continue
+		}
+		qt.root().insert(obj)
+	}
+}
+
+// updateTick records the objects already updated during an Update of a tree duplicating straddling objects
+type updateTick struct {
+	updated map[PhysicalObject]bool // whether each updated object moved
+	moved   []PhysicalObject
+}
+
+func (qt *Quadtree) update(delta time.Duration, tick *updateTick) {
 	if qt.m_Objects.Len() == 0 {
 		// 当物体一个Node中的物体移动出去之后，如果没有其他物体进入，该Node还会存留m_maxLifespan个生命周期
 		if qt.m_ActiveNodes == 0 {
+			qt.m_idleTicks += 1
 			if qt.m_curLife == -1 {
 				qt.m_curLife = qt.m_maxLifespan
 				qt.m_curLife -= 1
@@ -173,8 +266,9 @@ func (qt *Quadtree) Update(delta time.Duration) {
 		}
 	} else {
 		// 只要节点直接有物体或者字节点中有物体，所有生命倒计时停止
+		qt.m_idleTicks = 0
 		if qt.m_curLife != -1 {
-			if qt.m_maxLifespan <= 64 {
+			if qt.m_maxLifespan <= qt.m_config.lifespanDoublingLimit {
 				qt.m_maxLifespan *= 2
 			}
 			qt.m_curLife = -1
@@ -186,9 +280,29 @@ func (qt *Quadtree) Update(delta time.Duration) {
 	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
 		obj := ele.Value.(PhysicalObject)
 		// Logger.Info("updating object previously located at", zap.Float64("X", obj.X()), zap.Float64("Y", obj.Y()))
-		if obj.Update(delta) {
+		if qt.buried(obj) {
+			// removed objects stay where they are until compacted
+			continue
+		}
+		if tick != nil {
+			moved, updated := tick.updated[obj]
+			if !updated {
+				moved = qt.updateObject(obj, delta)
+				tick.updated[obj] = moved
+				if moved {
+					tick.moved = append(tick.moved, obj)
+					qt.syncHandle(obj)
+					qt.logMove(obj)
+				}
+			}
+			if moved {
+				movedObjects = append(movedObjects, ele)
+			}
+		} else if qt.updateObject(obj, delta) {
 			// Logger.Info("object moved to", zap.Float64("X", obj.X()), zap.Float64("Y", obj.Y()))
 			movedObjects = append(movedObjects, ele)
+			qt.syncHandle(obj)
+			qt.logMove(obj)
 		}
 	}
 
@@ -197,7 +311,7 @@ func (qt *Quadtree) Update(delta time.Duration) {
 	index := 0
 	for flags > 0 {
 		if flags&1 == 1 {
-			qt.Nodes[index].Update(delta)
+			qt.Nodes[index].update(delta, tick)
 		}
 		flags >>= 1
 		index += 1
@@ -207,7 +321,23 @@ func (qt *Quadtree) Update(delta time.Duration) {
 	for _, ele := range movedObjects {
 		container := qt
 		obj := ele.Value.(PhysicalObject)
-		for !container.Contains(obj) {
+		if tick != nil {
+			// every copy is dropped here, Update reinserts the object once
+			qt.m_Objects.Remove(ele)
+			qt.touch(-1)
+			continue
+		}
+		if !validCoordinates(obj) {
+			qt.m_Objects.Remove(ele)
+			qt.touch(-1)
+			qt.dropInvalid(obj)
+			continue
+		}
+		if q := qt.m_config.relocationQueue; q != nil {
+			q.push(qt, obj)
+			continue
+		}
+		for !container.contains(obj) {
 			if container.m_parent != nil {
 				container = container.m_parent
 			} else {
@@ -215,6 +345,7 @@ func (qt *Quadtree) Update(delta time.Duration) {
 			}
 		}
 		qt.m_Objects.Remove(ele)
+		qt.touch(-1)
 		/*
 			Logger.Info(
 				"object about moved to container",
@@ -226,16 +357,16 @@ func (qt *Quadtree) Update(delta time.Duration) {
 				zap.Float64("container height", container.Height),
 			)
 		*/
-		container.Insert(obj)
+		qt.observeRelocation(container)
+		container.insert(obj)
 	}
 
 	// prune out dead subtree
 	flags = qt.m_ActiveNodes
 	index = 0
 	for flags > 0 {
-		if flags&1 == 1 && qt.Nodes[index].m_curLife == 0 {
-			qt.Nodes[index] = nil
-			qt.m_ActiveNodes = qt.m_ActiveNodes &^ (1 << uint(index))
+		if flags&1 == 1 && qt.Nodes[index].expired() {
+			qt.dropChild(index)
 		}
 		flags >>= 1
 		index += 1
@@ -244,8 +375,28 @@ func (qt *Quadtree) Update(delta time.Duration) {
 
 // Insert - Insert the object into the node. If the node exceeds the capacity,
 // it will split and add all objects to their corresponding subnodes.
-// Caller needs to make sure the physical object to be inserted is completely contained withing this node
-func (qt *Quadtree) Insert(physical PhysicalObject) {
+// Caller needs to make sure the physical object to be inserted is completely contained withing this node.
+// Objects with NaN or infinite coordinates are handled according to the InvalidCoordinatesPolicy of the tree,
+// ErrInvalidCoordinates is returned if the policy is InvalidCoordinatesReject
+func (qt *Quadtree) Insert(physical PhysicalObject) error {
+	if !qt.ready() {
+		return ErrNilQuadtree
+	}
+	if !validCoordinates(physical) {
+		return qt.handleInvalid(physical)
+	}
+	qt.exhume(physical)
+	if qt.m_config.rejectDuplicates && qt.root().FindObject(physical) != nil {
+		return ErrDuplicateObject
+	}
+	qt.insert(physical)
+	qt.assignID(physical)
+	qt.logInsert(physical)
+	qt.enforceCapacity()
+	return nil
+}
+
+func (qt *Quadtree) insert(physical PhysicalObject) {
 	/*
 		Logger.Info(
 			"inserting physical object",
@@ -261,78 +412,136 @@ func (qt *Quadtree) Insert(physical PhysicalObject) {
 	*/
 	if qt.m_ActiveNodes == 0 {
 		qt.m_Objects.PushBack(physical)
+		qt.touch(1)
 		// simply add to list if no subtree and there is no need to create one
-		if qt.m_Objects.Len() < qt.MaxObjects || qt.Level == qt.MaxLevels {
+		if qt.m_Objects.Len() < qt.maxObjects() || qt.Level == qt.MaxLevels {
 			// Logger.Info("simply add to list if no subtree and there is no need to create one")
+			qt.checkOverflow()
 		} else {
 			// rebuild the tree
 			// Logger.Info("rebuild the tree, since new objects entering the region")
-			qt.Build()
+			qt.build()
 		}
 		return
 	}
 
-	horizontalMidpoint := qt.X + (qt.Width / 2)
-	verticalMidpoint := qt.Y + (qt.Height / 2)
-
-	topPart := (physical.Y() >= qt.Y) && (physical.Y()+physical.Height() <= verticalMidpoint)
-	bottomPart := (physical.Y() >= verticalMidpoint) && (physical.Y()+physical.Height() <= qt.Height)
-	leftPart := (physical.X() >= qt.X) && (physical.X()+physical.Width() <= horizontalMidpoint)
-	rightPart := (physical.X() >= horizontalMidpoint) && (physical.X()+physical.Width() <= qt.Width)
-
-	index := -1
-	//pRect can completely fit within the left quadrants
-	if topPart {
-		if leftPart {
-			index = 0
-		} else if rightPart {
-			index = 1
-		}
-	} else if bottomPart {
-		if leftPart {
-			index = 2
-		} else if rightPart {
-			index = 3
-		}
-	}
-
-	if index == -1 {
+	placement := qt.placement(physical)
+	if placement == 0 {
 		qt.m_Objects.PushBack(physical)
-	} else {
+		qt.touch(1)
+		return
+	}
+	for index := 0; index < 4; index += 1 {
+		if placement&(1<<uint(index)) == 0 {
+			continue
+		}
 		if qt.m_ActiveNodes&(1<<uint(index)) == 0 {
-			var bounds *Bounds
-			switch index {
-			case 0:
-				// top left
-				bounds = &Bounds{qt.X, qt.Y, qt.Width / 2, qt.Height / 2}
-			case 1:
-				// top right
-				bounds = &Bounds{qt.X + qt.Width/2, qt.Y, qt.Width / 2, qt.Height / 2}
-			case 2:
-				// bottom left
-				bounds = &Bounds{qt.X, qt.Y + qt.Height/2, qt.Width / 2, qt.Height / 2}
-			case 3:
-				// bottom right
-				bounds = &Bounds{qt.X + qt.Width/2, qt.Y + qt.Height/2, qt.Width / 2, qt.Height / 2}
-			}
 			// create subtree if not exists
-			qt.Nodes[index] = qt.createSubtree(bounds)
+			qt.Nodes[index] = qt.createSubtree(qt.quadrantBounds(index))
 			qt.m_ActiveNodes |= 1 << uint(index)
-			// Logger.Info("create subtree", zap.Int("index", index), zap.Any("bounds", bounds))
+			// Logger.Info("create subtree", zap.Int("index", index), zap.Any("bounds", qt.Nodes[index].Bounds))
 		}
 		// insert into subtree
 		// Logger.Info("insert into subtree", zap.Int("subtree index", index))
-		qt.Nodes[index].Insert(physical)
+		qt.Nodes[index].insert(physical)
 	}
 }
 
 // Remove a physical object from the quadtree
 func (qt *Quadtree) Remove(target PhysicalObject) bool {
+	if !qt.ready() {
+		return false
+	}
+	removed := qt.remove(target)
+	if removed != nil {
+		if qt.left(removed) {
+			qt.forget(removed)
+		}
+		qt.notifyRemoved(removed, RemovedExplicitly)
+	}
+	return removed != nil
+}
+
+// left tells whether no occurrence of an object remains in the tree after one was removed,
+// so that the tree can forget it
+func (qt *Quadtree) left(obj PhysicalObject) bool {
+	c := qt.m_config
+	if c.rejectDuplicates || c.straddlePolicy == StraddleDuplicate {
+		// an object is stored once, or every copy was removed at once
+		return true
+	}
+	return qt.root().FindObject(obj) == nil
+}
+
+// RemoveAll removes every occurrence of the target from the tree in one traversal and returns how many
+// were removed, copies of an object duplicated across quadrants count once. The removal is reported
+// to OnRemoved once
+func (qt *Quadtree) RemoveAll(target PhysicalObject) int {
+	if !qt.ready() {
+		return 0
+	}
+	var removed PhysicalObject
+	count := 0
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		if removed = qt.removeCopies(target); removed != nil {
+			count = 1
+		}
+	} else {
+		removed, count = qt.removeEvery(target)
+	}
+	if removed != nil {
+		qt.forget(removed)
+		qt.notifyRemoved(removed, RemovedExplicitly)
+	}
+	return count
+}
+
+// removeEvery removes every occurrence of the target from the subtree, and returns the last stored
+// object removed and how many were removed
+func (qt *Quadtree) removeEvery(target PhysicalObject) (PhysicalObject, int) {
+	var removed PhysicalObject
+	count := 0
+	for ele := qt.m_Objects.Front(); ele != nil; {
+		next := ele.Next()
+		if one := ele.Value.(PhysicalObject); qt.same(one, target) {
+			qt.m_Objects.Remove(ele)
+			qt.touch(-1)
+			removed = one
+			count += 1
+		}
+		ele = next
+	}
+
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			if one, n := qt.Nodes[index].removeEvery(target); n > 0 {
+				removed = one
+				count += n
+				if qt.m_config.eagerCollapse {
+					qt.collapseChild(index)
+				}
+			}
+		}
+		flags >>= 1
+		index += 1
+	}
+	return removed, count
+}
+
+// remove removes the target from the subtree and returns the stored object, nil if it was not found
+func (qt *Quadtree) remove(target PhysicalObject) PhysicalObject {
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		return qt.removeCopies(target)
+	}
+
 	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
 		one := ele.Value.(PhysicalObject)
-		if one == target {
+		if qt.same(one, target) {
 			qt.m_Objects.Remove(ele)
-			return true
+			qt.touch(-1)
+			return one
 		}
 	}
 
@@ -340,26 +549,75 @@ func (qt *Quadtree) Remove(target PhysicalObject) bool {
 	index := 0
 	for flags > 0 {
 		if flags&1 == 1 {
-			if removed := qt.Nodes[index].Remove(target); removed {
-				return true
+			if removed := qt.Nodes[index].remove(target); removed != nil {
+				if qt.m_config.eagerCollapse {
+					qt.collapseChild(index)
+				}
+				return removed
 			}
 		}
 		flags >>= 1
 		index += 1
 	}
-	return false
+	return nil
+}
+
+// expired tells whether the node should be pruned from its parent
+func (qt *Quadtree) expired() bool {
+	if qt.m_Objects.Len() != 0 || qt.m_ActiveNodes != 0 || qt.m_reserved {
+		return false
+	}
+	if prune := qt.m_config.prunePolicy; prune != nil {
+		return prune(qt, qt.m_idleTicks)
+	}
+	return qt.m_curLife == 0
+}
+
+// Objects returns the physical objects stored directly in this node, excluding those of its children
+func (qt *Quadtree) Objects() []PhysicalObject {
+	if !qt.ready() {
+		return nil
+	}
+	objects := make([]PhysicalObject, 0, qt.m_Objects.Len())
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if obj := ele.Value.(PhysicalObject); !qt.buried(obj) {
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+// collapseChild removes the child node at index if neither it nor its subtrees hold any object
+func (qt *Quadtree) collapseChild(index int) {
+	child := qt.Nodes[index]
+	if child.m_Objects.Len() == 0 && child.m_ActiveNodes == 0 {
+		qt.dropChild(index)
+	}
 }
 
 // 广度优先遍历
 func (qt *Quadtree) Walk(walker func(PhysicalObject)) {
+	if !qt.ready() {
+		return
+	}
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		qt.walk(dedupe(walker))
+		return
+	}
+	qt.walk(walker)
+}
+
+func (qt *Quadtree) walk(walker func(PhysicalObject)) {
 	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
-		walker(ele.Value.(PhysicalObject))
+		if obj := ele.Value.(PhysicalObject); !qt.buried(obj) {
+			walker(obj)
+		}
 	}
 	flags := qt.m_ActiveNodes
 	index := 0
 	for flags > 0 {
 		if flags&1 == 1 {
-			qt.Nodes[index].Walk(walker)
+			qt.Nodes[index].walk(walker)
 		}
 		flags >>= 1
 		index += 1
@@ -369,9 +627,12 @@ func (qt *Quadtree) Walk(walker func(PhysicalObject)) {
 // FindObject returns the Quadtree that directly contains the physical object
 // TODO: 根据target的位置区间加快搜索
 func (qt *Quadtree) FindObject(target PhysicalObject) *Quadtree {
+	if !qt.ready() {
+		return nil
+	}
 	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
 		one := ele.Value.(PhysicalObject)
-		if one == target {
+		if qt.same(one, target) && !qt.buried(one) {
 			return qt
 		}
 	}
@@ -392,9 +653,12 @@ func (qt *Quadtree) FindObject(target PhysicalObject) *Quadtree {
 
 //
 func (qt *Quadtree) GetIntersectedObjectsRaw(target PhysicalObject, objects []PhysicalObject) IntersectedObjects {
+	if !qt.ready() {
+		return objects
+	}
 	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
 		obj := ele.Value.(PhysicalObject)
-		if obj == target {
+		if qt.same(obj, target) || qt.buried(obj) {
 			continue
 		}
 		if Intersect(target, obj) {
@@ -405,7 +669,7 @@ func (qt *Quadtree) GetIntersectedObjectsRaw(target PhysicalObject, objects []Ph
 	flags := qt.m_ActiveNodes
 	index := 0
 	for flags > 0 {
-		if flags&1 == 1 {
+		if flags&1 == 1 && qt.m_config.reaches(qt.Nodes[index].searchBounds(), target) {
 			objects = qt.Nodes[index].GetIntersectedObjectsRaw(target, objects)
 		}
 		flags >>= 1
@@ -414,50 +678,98 @@ func (qt *Quadtree) GetIntersectedObjectsRaw(target PhysicalObject, objects []Ph
 	return objects
 }
 
-func (qt *Quadtree) GetIntersectedObjects(target PhysicalObject) IntersectedObjects {
+// GetIntersectedObjects returns the objects of the tree intersecting target, which must be stored in the
+// tree. Options make the search start at the root, counting its work and skipping objects as Retrieve does
+func (qt *Quadtree) GetIntersectedObjects(target PhysicalObject, opts ...QueryOption) IntersectedObjects {
+	if !qt.ready() {
+		return nil
+	}
+	qt.recordQuery("intersected", strconv.FormatUint(qt.recordedID(target), 10))
 	sub := qt.FindObject(target)
 	if sub == nil {
 		return nil
 	}
-
-	var objects []PhysicalObject
-	// find intersected objects in parent trees
-	parent := sub.m_parent
-	for parent != nil {
-		for ele := parent.m_Objects.Front(); ele != nil; ele = ele.Next() {
-			obj := ele.Value.(PhysicalObject)
-			if obj == target {
-				continue
-			}
-			if Intersect(target, obj) {
-				objects = append(objects, obj)
-			}
-		}
-		parent = parent.m_parent
+	if len(opts) > 0 {
+		qc := newQueryConfig(opts)
+		objects := qt.root().intersecting(target, qc)
+		qc.report(qt.m_config)
+		return objects
 	}
-
-	// find intersected objects in current tree and its children
-	return sub.GetIntersectedObjectsRaw(target, objects)
+	// Intersect reaches beyond the bounds of the objects, objects of sibling subtrees may intersect the
+	// target whatever the straddle policy, search every node they may be stored in
+	return qt.root().intersecting(target, nil)
 }
 
-// get a list of intersection records within this quadtree
-func (qt *Quadtree) GetIntersection(intersections *list.List, potentialObjects *list.List) *list.List {
+// get a list of intersection records within this quadtree,
+// options count the nodes visited and the pairs tested, bound them and skip objects as for Retrieve
+func (qt *Quadtree) GetIntersection(intersections *list.List, potentialObjects *list.List, opts ...QueryOption) *list.List {
 	if intersections == nil {
 		intersections = &list.List{}
 	}
+	if !qt.ready() {
+		return intersections
+	}
+	qt.recordQuery("intersections", "")
+	qt.profile("GetIntersection", func() {
+		found := intersections
+		if qt.m_config.pairBudget != nil {
+			found = &list.List{}
+		}
+		if len(opts) > 0 {
+			qc := newQueryConfig(opts)
+			qt.intersections(found, potentialObjects, qc)
+			qc.report(qt.m_config)
+		} else if potentialObjects == nil && qt.m_config.results != nil {
+			qt.cachedIntersection(found)
+		} else {
+			qt.intersections(found, potentialObjects, nil)
+		}
+		if budget := qt.m_config.pairBudget; budget != nil {
+			budget.keep(found, intersections)
+		}
+	})
+	return intersections
+}
+
+// intersections collects the intersection records of the tree, counting into the query qc, which may be nil
+func (qt *Quadtree) intersections(intersections *list.List, potentialObjects *list.List, qc *queryConfig) {
 	if potentialObjects == nil {
+		if qt.m_config.straddlePolicy != StraddleKeepAtParent {
+			qt.getIntersectionByQuery(intersections, qc)
+			return
+		}
 		potentialObjects = &list.List{}
 	}
+	qt.getIntersection(intersections, potentialObjects, qc)
+}
+
+func (qt *Quadtree) getIntersection(intersections *list.List, potentialObjects *list.List, qc *queryConfig) {
+	if !qc.enter() {
+		return
+	}
+	if qt.overflowing() {
+		qt.getOverflowIntersection(intersections, potentialObjects, qc)
+		return
+	}
 	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
 		one := ele.Value.(PhysicalObject)
+		if qt.buried(one) || !qc.accepts(qt, one) {
+			continue
+		}
 		// check intersections with each physical object of parent nodes, or previous objects in current node
 		for eleParent := potentialObjects.Front(); eleParent != nil; eleParent = eleParent.Next() {
 			objParent := eleParent.Value.(PhysicalObject)
-			if Intersect(objParent, one) {
+			if !qc.test() {
+				return
+			}
+			if !qt.same(objParent, one) && Intersect(objParent, one) {
 				intersections.PushBack(&IntersectionRecord{
 					One:     objParent,
 					Another: one,
 				})
+				if qt.m_config.pairsFull(intersections) {
+					return
+				}
 			}
 		}
 		potentialObjects.PushBack(one)
@@ -467,12 +779,11 @@ func (qt *Quadtree) GetIntersection(intersections *list.List, potentialObjects *
 	index := 0
 	for flags > 0 {
 		if flags&1 == 1 {
-			qt.Nodes[index].GetIntersection(intersections, potentialObjects)
+			qt.Nodes[index].getIntersection(intersections, potentialObjects, qc)
 		}
 		flags >>= 1
 		index += 1
 	}
-	return intersections
 }
 
 // initialize a quadtree
@@ -490,14 +801,198 @@ func CreateQuadtree(bounds *Bounds,
 		MaxObjects:    maxObjectsBeforeSplit,
 		MaxLevels:     maxLevelsToSplit,
 		m_Objects:     objects,
+		m_objectCount: objects.Len(),
 		m_curLife:     -1,
-		m_maxLifespan: 64,
+		m_maxLifespan: defaultLifespan,
+		m_config:      newConfig(),
+	}
+}
+
+// NewQuadtree initialize an empty quadtree configured by the given options
+func NewQuadtree(bounds *Bounds,
+	maxObjectsBeforeSplit,
+	maxLevelsToSplit int,
+	opts ...Option) *Quadtree {
+
+	qt := CreateQuadtree(bounds, maxObjectsBeforeSplit, maxLevelsToSplit)
+	for _, opt := range opts {
+		opt(qt.m_config)
+	}
+	qt.m_config.presize()
+	qt.m_maxLifespan = qt.m_config.lifespan
+	return qt
+}
+
+// Init initialize qt, typically a zero value embedded in another struct, as an empty quadtree
+// configured by the given options, and returns it
+func (qt *Quadtree) Init(bounds *Bounds,
+	maxObjectsBeforeSplit,
+	maxLevelsToSplit int,
+	opts ...Option) *Quadtree {
+
+	*qt = *NewQuadtree(bounds, maxObjectsBeforeSplit, maxLevelsToSplit, opts...)
+	return qt
+}
+
+// ready initializes a zero value tree on first use, a zero value tree has empty bounds and never splits.
+// It returns false for a nil tree, which methods treat as empty
+func (qt *Quadtree) ready() bool {
+	if qt == nil {
+		return false
+	}
+	if qt.m_Objects == nil {
+		if qt.Bounds == nil {
+			qt.Bounds = &Bounds{}
+		}
+		qt.m_Objects = list.New()
+		qt.m_config = newConfig()
+		qt.m_curLife = -1
+		qt.m_maxLifespan = qt.m_config.lifespan
+	}
+	return true
+}
+
+// contains is Bounds.Contains, tolerating overlaps of the node border up to the configured epsilon or child overlap
+func (qt *Quadtree) contains(obj PhysicalObject) bool {
+	if qt.m_config.straddlePolicy == StraddleLoose {
+		return qt.searchBounds().Contains(obj)
+	}
+	if qt.m_config.fixedPoint {
+		return qt.fixedContains(obj)
+	}
+	eps := qt.m_config.tolerance()
+	return obj.X() >= qt.X-eps &&
+		obj.Y() >= qt.Y-eps &&
+		obj.X()+obj.Width() <= qt.X+qt.Width+eps &&
+		obj.Y()+obj.Height() <= qt.Y+qt.Height+eps
+}
+
+// SplitPoint returns the point where the node is divided into its four quadrants,
+// which is the midpoint of the node unless a split chooser is configured
+func (qt *Quadtree) SplitPoint() (x, y float64) {
+	if !qt.ready() {
+		return 0, 0
+	}
+	if qt.m_splitSet {
+		return qt.m_splitX, qt.m_splitY
+	}
+	return qt.X + (qt.Width / 2), qt.Y + (qt.Height / 2)
+}
+
+// chooseSplit asks the configured split chooser where the node should be divided
+func (qt *Quadtree) chooseSplit() {
+	qt.chooseSplitFor(qt.Objects())
+}
+
+// chooseSplitFor sets the split point of current node, chosen for the given objects
+func (qt *Quadtree) chooseSplitFor(objects []PhysicalObject) {
+	choose := qt.m_config.splitChooser
+	if choose == nil && !qt.m_config.integerGrid && !qt.m_config.fixedPoint {
+		return
+	}
+	x, y := qt.X+qt.Width/2, qt.Y+qt.Height/2
+	if choose != nil {
+		x, y = choose(qt.Bounds, objects)
+	}
+	if qt.m_config.fixedPoint {
+		x, y = qt.fixedSplit(x, y)
+	}
+	if qt.m_config.integerGrid {
+		x, y = math.Floor(x), math.Floor(y)
+	}
+	qt.m_splitX = math.Min(math.Max(x, qt.X), qt.X+qt.Width)
+	qt.m_splitY = math.Min(math.Max(y, qt.Y), qt.Y+qt.Height)
+	qt.m_splitSet = true
+}
+
+// quadrantIndex returns the index of the child quadrant which can completely contain the object,
+// or -1 if the object overlaps more than one quadrant or lies outside of current node
+func (qt *Quadtree) quadrantIndex(obj PhysicalObject) int {
+	if qt.m_config.fixedPoint {
+		return qt.fixedQuadrantIndex(obj)
+	}
+	horizontalMidpoint, verticalMidpoint := qt.SplitPoint()
+	eps := qt.m_config.tolerance()
+
+	topPart := (obj.Y() >= qt.Y-eps) && (obj.Y()+obj.Height() <= verticalMidpoint+eps)
+	bottomPart := (obj.Y() >= verticalMidpoint-eps) && (obj.Y()+obj.Height() <= qt.Y+qt.Height+eps)
+	leftPart := (obj.X() >= qt.X-eps) && (obj.X()+obj.Width() <= horizontalMidpoint+eps)
+	rightPart := (obj.X() >= horizontalMidpoint-eps) && (obj.X()+obj.Width() <= qt.X+qt.Width+eps)
+
+	index := -1
+	if topPart {
+		if leftPart {
+			index = 0
+		} else if rightPart {
+			index = 1
+		}
+	} else if bottomPart {
+		if leftPart {
+			index = 2
+		} else if rightPart {
+			index = 3
+		}
+	}
+	return index
+}
+
+// quadrantBounds returns the bounds of the child quadrant at index
+func (qt *Quadtree) quadrantBounds(index int) *Bounds {
+	if qt.m_splitSet {
+		sx, sy := qt.m_splitX, qt.m_splitY
+		switch index {
+		case 0:
+			return &Bounds{qt.X, qt.Y, sx - qt.X, sy - qt.Y}
+		case 1:
+			return &Bounds{sx, qt.Y, qt.X + qt.Width - sx, sy - qt.Y}
+		case 2:
+			return &Bounds{qt.X, sy, sx - qt.X, qt.Y + qt.Height - sy}
+		default:
+			return &Bounds{sx, sy, qt.X + qt.Width - sx, qt.Y + qt.Height - sy}
+		}
+	}
+	switch index {
+	case 0:
+		// top left, bottom left with YUp
+		return &Bounds{qt.X, qt.Y, qt.Width / 2, qt.Height / 2}
+	case 1:
+		// top right, bottom right with YUp
+		return &Bounds{qt.X + qt.Width/2, qt.Y, qt.Width / 2, qt.Height / 2}
+	case 2:
+		// bottom left, top left with YUp
+		return &Bounds{qt.X, qt.Y + qt.Height/2, qt.Width / 2, qt.Height / 2}
+	default:
+		// bottom right, top right with YUp
+		return &Bounds{qt.X + qt.Width/2, qt.Y + qt.Height/2, qt.Width / 2, qt.Height / 2}
 	}
 }
 
 func (qt *Quadtree) createSubtree(bounds *Bounds, physicals ...PhysicalObject) *Quadtree {
-	subtree := CreateQuadtree(bounds, qt.MaxObjects, qt.MaxLevels, physicals...)
-	subtree.Level = qt.Level + 1
-	subtree.m_parent = qt
+	c := qt.m_config
+	subtree := c.newNode()
+	*subtree = Quadtree{
+		Bounds:        bounds,
+		MaxObjects:    qt.MaxObjects,
+		MaxLevels:     qt.MaxLevels,
+		Level:         qt.Level + 1,
+		m_Objects:     c.newObjectList(),
+		m_objectCount: len(physicals),
+		m_curLife:     -1,
+		m_maxLifespan: c.lifespan,
+		m_parent:      qt,
+		m_config:      c,
+	}
+	for _, obj := range physicals {
+		subtree.m_Objects.PushBack(obj)
+	}
+	qt.root().m_nodeCount += 1
 	return subtree
 }
+
+// root returns the root node of the tree
+func (qt *Quadtree) root() *Quadtree {
+	for qt.m_parent != nil {
+		qt = qt.m_parent
+	}
+	return qt
+}
@@ -1,11 +1,12 @@
-package quadtree
+package quadtree_test
 
 import (
-	"bytes"
-	"fmt"
-	"strings"
+	"math/rand"
 	"testing"
 	"time"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/quadtreetest"
 )
 
 type TestPhysicalObject struct {
@@ -39,208 +40,17 @@ type TestSetup struct {
 	PhysicalObjects       []float64 // groups of (X, Y, Width, Height)
 }
 
-// QuadtreeState defines the expected state of a Quadtree
-type QuadtreeState struct {
-	PhysicalObjects []float64         // groupds of (X, Y, Width, Height), representing objects in the root node
-	SubTrees        [4]*QuadtreeState // nil element to identify that no such subtree should be created
-}
-
-func (qs *QuadtreeState) String(indent int) string {
-	var buf bytes.Buffer
-	indentString := strings.Repeat("\t", indent)
-
-	for i := 0; i < len(qs.PhysicalObjects); i += 4 {
-		buf.WriteString(indentString)
-		buf.WriteString(
-			fmt.Sprintf(
-				"%-10.2f%-10.2f%-10.2f%-10.2f\n",
-				qs.PhysicalObjects[i],
-				qs.PhysicalObjects[i+1],
-				qs.PhysicalObjects[i+2],
-				qs.PhysicalObjects[i+3],
-			),
-		)
-	}
-	for i, subTree := range qs.SubTrees {
-		if subTree == nil {
-			continue
-		}
-		buf.WriteString(indentString)
-		buf.WriteString(fmt.Sprintf("%d:\n", i))
-		buf.WriteString(subTree.String(indent + 1))
-	}
-	return buf.String()
-}
-
-func (realState *QuadtreeState) Check(state *QuadtreeState) bool {
-	if len(state.PhysicalObjects) != len(realState.PhysicalObjects) {
-		return false
-	}
-
-	usedIndex := map[int]bool{}
-	for i := 0; i < len(state.PhysicalObjects); i = i + 4 {
-		found := false
-		for k := 0; k < len(realState.PhysicalObjects); k += 4 {
-			if !usedIndex[k] &&
-				realState.PhysicalObjects[k] == state.PhysicalObjects[i] &&
-				realState.PhysicalObjects[k+1] == state.PhysicalObjects[i+1] &&
-				realState.PhysicalObjects[k+2] == state.PhysicalObjects[i+2] &&
-				realState.PhysicalObjects[k+3] == state.PhysicalObjects[i+3] {
-
-				found = true
-				usedIndex[k] = true
-				break
-			}
-		}
-		if !found {
-			return false
-		}
-	}
-
-	for i, subTreeState := range state.SubTrees {
-		if subTreeState == nil {
-			// subtree created when should not
-			if realState.SubTrees[i] != nil {
-				return false
-			}
-		} else {
-			// subtree not created when shoud
-			if realState.SubTrees[i] == nil {
-				return false
-			} else {
-				// evaluates state of subtree
-				return realState.SubTrees[i].Check(subTreeState)
-			}
-		}
-	}
-
-	return true
-}
-
-func (qt *Quadtree) DumpState() *QuadtreeState {
-	state := &QuadtreeState{}
-	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
-		obj := ele.Value.(PhysicalObject)
-		state.PhysicalObjects = append(state.PhysicalObjects, obj.X(), obj.Y(), obj.Width(), obj.Height())
-	}
-
-	flags := qt.m_ActiveNodes
-	index := 0
-	for flags > 0 {
-		if flags&1 == 1 && qt.Nodes[index] != nil {
-			state.SubTrees[index] = qt.Nodes[index].DumpState()
-		}
-		flags >>= 1
-		index += 1
-	}
-	return state
-}
-
-// list of pair of PhysicalObject representing intersected objects
-type QuadtreeIntersections []PhysicalObject
-
-func SameAs(obj PhysicalObject, another PhysicalObject) bool {
-	return obj.X() == another.X() &&
-		obj.Y() == another.Y() &&
-		obj.Width() == another.Width() &&
-		obj.Height() == another.Height()
-}
-
-func (qt *Quadtree) DumpIntersections() QuadtreeIntersections {
-	intersectionList := qt.GetIntersection(nil, nil)
-	var intersections []PhysicalObject
-	for ele := intersectionList.Front(); ele != nil; ele = ele.Next() {
-		record := ele.Value.(*IntersectionRecord)
-		intersections = append(intersections, record.One, record.Another)
-	}
-	return intersections
-}
-
-func (actual QuadtreeIntersections) Check(expected QuadtreeIntersections) bool {
-	if len(actual) != len(expected) {
-		return false
-	}
-	usedIndex := make(map[int]bool)
-	for i := 0; i < len(actual); i += 2 {
-		found := false
-		for k := 0; k < len(expected); k += 2 {
-			if !usedIndex[k] &&
-				(SameAs(actual[i], expected[k]) && SameAs(actual[i+1], expected[k+1])) ||
-				(SameAs(actual[i], expected[k+1]) && SameAs(actual[i+1], expected[k])) {
-
-				found = true
-				usedIndex[k] = true
-				break
-			}
-		}
-		if !found {
-			return false
-		}
-	}
-	return true
-}
-
-func (inter QuadtreeIntersections) String() string {
-	if len(inter) == 0 {
-		return "None"
-	}
-
-	var buf bytes.Buffer
-	for i := 0; i < len(inter); i += 2 {
-		one := inter[i]
-		another := inter[i+1]
-		buf.WriteString(
-			fmt.Sprintf(
-				"(%-10.2f%-10.2f%-10.2f%-10.2f) (%-10.2f%-10.2f%-10.2f%-10.2f)\n",
-				one.X(), one.Y(), one.Width(), one.Height(),
-				another.X(), another.Y(), another.Width(), another.Height(),
-			),
-		)
-	}
-	return buf.String()
-}
-
-func (inter IntersectedObjects) SameAs(another IntersectedObjects) bool {
-	if len(inter) != len(another) {
-		return false
-	}
-
-	usedIndex := make(map[int]bool)
-	for _, one := range inter {
-		found := false
-		for k, two := range another {
-			if !usedIndex[k] && one.X() == two.X() && one.Y() == two.Y() && one.Width() == two.Width() && one.Height() == two.Height() {
-				found = true
-				usedIndex[k] = true
-				break
-			}
-		}
-		if !found {
-			return false
-		}
-	}
-	return true
-}
-
-func (inter IntersectedObjects) String() string {
-	var buf bytes.Buffer
-	for _, obj := range inter {
-		buf.WriteString(
-			fmt.Sprintf(
-				"%-10.2f%-10.2f%-10.2f%-10.2f",
-				obj.X(), obj.Y(), obj.Width(), obj.Height(),
-			),
-		)
-	}
-	return buf.String()
-}
+type (
+	QuadtreeState         = quadtreetest.QuadtreeState
+	QuadtreeIntersections = quadtreetest.QuadtreeIntersections
+)
 
 type TestExpectation struct {
 	*QuadtreeState
 	QuadtreeIntersections
 }
 
-type OperationFunc func(*Quadtree, []PhysicalObject) []interface{}
+type OperationFunc func(*quadtree.Quadtree, []quadtree.PhysicalObject) []interface{}
 type ExpectationFunc func(*testing.T, int, []interface{})
 
 // TestOperation defined an operation bo be invoked on a Quadtree, and the expectation
@@ -255,13 +65,13 @@ type TestCase struct {
 }
 
 /* ++++++++++ START pre-defined TestOperation*/
-func OP_Build(qt *Quadtree, _ []PhysicalObject) []interface{} {
+func OP_Build(qt *quadtree.Quadtree, _ []quadtree.PhysicalObject) []interface{} {
 	qt.Build()
 	return []interface{}{qt}
 }
 
 func OP_Insert(parts ...float64) OperationFunc {
-	return func(qt *Quadtree, _ []PhysicalObject) []interface{} {
+	return func(qt *quadtree.Quadtree, _ []quadtree.PhysicalObject) []interface{} {
 		for i := 0; i < len(parts); i += 4 {
 			qt.Insert(&TestPhysicalObject{
 				x:      parts[i],
@@ -275,14 +85,14 @@ func OP_Insert(parts ...float64) OperationFunc {
 }
 
 func OP_Remove(index int) OperationFunc {
-	return func(qt *Quadtree, objects []PhysicalObject) []interface{} {
+	return func(qt *quadtree.Quadtree, objects []quadtree.PhysicalObject) []interface{} {
 		qt.Remove(objects[index])
 		return []interface{}{qt}
 	}
 }
 
 func OP_UpdateObject(index int, x, y float64, updateTimes int) OperationFunc {
-	return func(qt *Quadtree, objects []PhysicalObject) []interface{} {
+	return func(qt *quadtree.Quadtree, objects []quadtree.PhysicalObject) []interface{} {
 		obj := objects[index].(*TestPhysicalObject)
 		obj.x = x
 		obj.y = y
@@ -295,29 +105,29 @@ func OP_UpdateObject(index int, x, y float64, updateTimes int) OperationFunc {
 }
 
 func OP_GetIntersectedObjects(index int) OperationFunc {
-	return func(qt *Quadtree, objects []PhysicalObject) []interface{} {
+	return func(qt *quadtree.Quadtree, objects []quadtree.PhysicalObject) []interface{} {
 		return []interface{}{qt, qt.GetIntersectedObjects(objects[index])}
 	}
 }
 
 func OP_FindObject(index int) OperationFunc {
-	return func(qt *Quadtree, objects []PhysicalObject) []interface{} {
+	return func(qt *quadtree.Quadtree, objects []quadtree.PhysicalObject) []interface{} {
 		tree := qt.FindObject(objects[index])
 		return []interface{}{tree}
 	}
 }
 
-func EX_CheckIntersectedObjects(expectedInter IntersectedObjects) ExpectationFunc {
+func EX_CheckIntersectedObjects(expectedInter quadtree.IntersectedObjects) ExpectationFunc {
 	return func(t *testing.T, testIndex int, params []interface{}) {
-		qt := params[0].(*Quadtree)
-		realInter := params[1].(IntersectedObjects)
+		qt := params[0].(*quadtree.Quadtree)
+		realInter := params[1].(quadtree.IntersectedObjects)
 
-		if !realInter.SameAs(expectedInter) {
+		if !quadtreetest.SameObjects(realInter, expectedInter) {
 			t.Errorf("Object in Quadtree (%d) expectes intersection:\n%s\nBut has intersection:\n%s\nIts state:\n%s",
 				testIndex,
-				expectedInter.String(),
-				realInter.String(),
-				qt.DumpState().String(0),
+				quadtreetest.ObjectsString(expectedInter),
+				quadtreetest.ObjectsString(realInter),
+				quadtreetest.DumpState(qt).String(0),
 			)
 		}
 	}
@@ -325,9 +135,9 @@ func EX_CheckIntersectedObjects(expectedInter IntersectedObjects) ExpectationFun
 
 func EX_CheckStateAndIntersections(expectation *TestExpectation) ExpectationFunc {
 	return func(t *testing.T, testIndex int, params []interface{}) {
-		qt := params[0].(*Quadtree)
+		qt := params[0].(*quadtree.Quadtree)
 
-		realState := qt.DumpState()
+		realState := quadtreetest.DumpState(qt)
 		expectedState := expectation.QuadtreeState
 		if !realState.Check(expectedState) {
 			t.Errorf(
@@ -337,7 +147,7 @@ func EX_CheckStateAndIntersections(expectation *TestExpectation) ExpectationFunc
 				realState.String(0),
 			)
 		}
-		realIntersections := qt.DumpIntersections()
+		realIntersections := quadtreetest.DumpIntersections(qt)
 		expectedIntersections := expectation.QuadtreeIntersections
 		if !realIntersections.Check(expectedIntersections) {
 			t.Errorf(
@@ -353,9 +163,9 @@ func EX_CheckStateAndIntersections(expectation *TestExpectation) ExpectationFunc
 
 func EX_CheckState(expectedState *QuadtreeState) ExpectationFunc {
 	return func(t *testing.T, testIndex int, params []interface{}) {
-		qt := params[0].(*Quadtree)
+		qt := params[0].(*quadtree.Quadtree)
 
-		realState := qt.DumpState()
+		realState := quadtreetest.DumpState(qt)
 		if !realState.Check(expectedState) {
 			t.Errorf(
 				"\nQuadtree (%d) expectes to be in state:\n%s\nBut in state:\n%s",
@@ -384,11 +194,11 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{0.5, 0.5, 1, 1},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{0.5, 0.5, 1, 1},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{0, 0, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{0, 0, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 							},
 						},
@@ -398,8 +208,8 @@ var (
 					Operation: OP_Remove(1),
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{0.5, 0.5, 1, 1},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{0.5, 0.5, 1, 1},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{},
 							},
 						},
@@ -421,11 +231,11 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{0.5, 0.5, 1, 1},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{0.5, 0.5, 1, 1},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{0, 0, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{0, 0, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 							},
 						},
@@ -435,11 +245,11 @@ var (
 					Operation: OP_Remove(0),
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{0, 0, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{0, 0, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 							},
 						},
@@ -467,7 +277,7 @@ var (
 				&TestOperation{
 					Operation: OP_GetIntersectedObjects(0),
 					Expectation: []ExpectationFunc{EX_CheckIntersectedObjects(
-						[]PhysicalObject{
+						[]quadtree.PhysicalObject{
 							&TestPhysicalObject{1, 1, 1, 1},
 						},
 					)},
@@ -494,7 +304,7 @@ var (
 				&TestOperation{
 					Operation: OP_GetIntersectedObjects(4),
 					Expectation: []ExpectationFunc{EX_CheckIntersectedObjects(
-						[]PhysicalObject{
+						[]quadtree.PhysicalObject{
 							&TestPhysicalObject{1.5, 1, 1, 1},
 						},
 					)},
@@ -520,7 +330,7 @@ var (
 				&TestOperation{
 					Operation: OP_GetIntersectedObjects(1),
 					Expectation: []ExpectationFunc{EX_CheckIntersectedObjects(
-						[]PhysicalObject{
+						[]quadtree.PhysicalObject{
 							&TestPhysicalObject{1, 1, 2, 2},
 							&TestPhysicalObject{0, 1, 1, 1},
 							&TestPhysicalObject{1, 1, 1, 1},
@@ -548,8 +358,8 @@ var (
 				&TestOperation{
 					Operation: OP_FindObject(3),
 					Expectation: []ExpectationFunc{EX_CheckState(&QuadtreeState{
-						[]float64{1, 1, 1, 1},
-						[4]*QuadtreeState{},
+						PhysicalObjects: []float64{1, 1, 1, 1},
+						SubTrees:        [4]*QuadtreeState{},
 					})},
 				},
 			},
@@ -574,23 +384,23 @@ var (
 				&TestOperation{
 					Operation: OP_FindObject(0),
 					Expectation: []ExpectationFunc{EX_CheckState(&QuadtreeState{
-						[]float64{0.5, 0.5, 1, 1},
-						[4]*QuadtreeState{
+						PhysicalObjects: []float64{0.5, 0.5, 1, 1},
+						SubTrees: [4]*QuadtreeState{
 							&QuadtreeState{
-								[]float64{0, 0, 1, 1},
-								[4]*QuadtreeState{},
+								PhysicalObjects: []float64{0, 0, 1, 1},
+								SubTrees:        [4]*QuadtreeState{},
 							},
 							&QuadtreeState{
-								[]float64{1, 0, 1, 1},
-								[4]*QuadtreeState{},
+								PhysicalObjects: []float64{1, 0, 1, 1},
+								SubTrees:        [4]*QuadtreeState{},
 							},
 							&QuadtreeState{
-								[]float64{0, 1, 1, 1},
-								[4]*QuadtreeState{},
+								PhysicalObjects: []float64{0, 1, 1, 1},
+								SubTrees:        [4]*QuadtreeState{},
 							},
 							&QuadtreeState{
-								[]float64{1, 1, 1, 1},
-								[4]*QuadtreeState{},
+								PhysicalObjects: []float64{1, 1, 1, 1},
+								SubTrees:        [4]*QuadtreeState{},
 							},
 						},
 					})},
@@ -612,11 +422,11 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{},
-							[4]*QuadtreeState{
-								&QuadtreeState{[]float64{0, 0, 1, 1}, [4]*QuadtreeState{}}, // top-left subnode
-								&QuadtreeState{[]float64{1, 0, 1, 1}, [4]*QuadtreeState{}}, // top-right subnode
-								&QuadtreeState{[]float64{0, 1, 1, 1}, [4]*QuadtreeState{}}, // bottom-left subnode
+							PhysicalObjects: []float64{},
+							SubTrees: [4]*QuadtreeState{
+								&QuadtreeState{PhysicalObjects: []float64{0, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}}, // top-left subnode
+								&QuadtreeState{PhysicalObjects: []float64{1, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}}, // top-right subnode
+								&QuadtreeState{PhysicalObjects: []float64{0, 1, 1, 1}, SubTrees: [4]*QuadtreeState{}}, // bottom-left subnode
 								nil, // no bottom-right subnode
 							},
 						},
@@ -640,14 +450,14 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{1.5, 1.5, 1, 1},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{1.5, 1.5, 1, 1},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{},
-									[4]*QuadtreeState{
-										&QuadtreeState{[]float64{0, 0, 1, 1}, [4]*QuadtreeState{}},
-										&QuadtreeState{[]float64{1, 0, 1, 1}, [4]*QuadtreeState{}},
-										&QuadtreeState{[]float64{0, 1, 1, 1}, [4]*QuadtreeState{}},
+									PhysicalObjects: []float64{},
+									SubTrees: [4]*QuadtreeState{
+										&QuadtreeState{PhysicalObjects: []float64{0, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+										&QuadtreeState{PhysicalObjects: []float64{1, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+										&QuadtreeState{PhysicalObjects: []float64{0, 1, 1, 1}, SubTrees: [4]*QuadtreeState{}},
 										nil,
 									},
 								}, // top-left subnode
@@ -676,17 +486,17 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{
+							PhysicalObjects: []float64{
 								1.5, 1.5, 1, 1,
 							},
-							[4]*QuadtreeState{
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{
+									PhysicalObjects: []float64{
 										0, 0, 1, 1,
 										1, 0, 1, 1,
 										0, 1, 1, 1,
 									},
-									[4]*QuadtreeState{},
+									SubTrees: [4]*QuadtreeState{},
 								},
 								nil,
 								nil,
@@ -713,13 +523,13 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{
+							PhysicalObjects: []float64{
 								0, 0, 1, 1,
 								1, 0, 1, 1,
 								0, 1, 1, 1,
 								1, 1, 1, 1,
 							},
-							[4]*QuadtreeState{},
+							SubTrees: [4]*QuadtreeState{},
 						},
 					)},
 				},
@@ -741,12 +551,12 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{},
-							[4]*QuadtreeState{
-								&QuadtreeState{[]float64{0, 0, 1, 1}, [4]*QuadtreeState{}},
-								&QuadtreeState{[]float64{1, 0, 1, 1}, [4]*QuadtreeState{}},
-								&QuadtreeState{[]float64{0, 1, 1, 1}, [4]*QuadtreeState{}},
-								&QuadtreeState{[]float64{1, 1, 1, 1}, [4]*QuadtreeState{}},
+							PhysicalObjects: []float64{},
+							SubTrees: [4]*QuadtreeState{
+								&QuadtreeState{PhysicalObjects: []float64{0, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+								&QuadtreeState{PhysicalObjects: []float64{1, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+								&QuadtreeState{PhysicalObjects: []float64{0, 1, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+								&QuadtreeState{PhysicalObjects: []float64{1, 1, 1, 1}, SubTrees: [4]*QuadtreeState{}},
 							},
 						},
 					)},
@@ -770,25 +580,25 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{3.5, 3.5, 1, 1},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{3.5, 3.5, 1, 1},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{1.5, 1.5, 1, 1},
-									[4]*QuadtreeState{
+									PhysicalObjects: []float64{1.5, 1.5, 1, 1},
+									SubTrees: [4]*QuadtreeState{
 										&QuadtreeState{
-											[]float64{},
-											[4]*QuadtreeState{
+											PhysicalObjects: []float64{},
+											SubTrees: [4]*QuadtreeState{
 												&QuadtreeState{
-													[]float64{0, 0, 1, 1},
-													[4]*QuadtreeState{},
+													PhysicalObjects: []float64{0, 0, 1, 1},
+													SubTrees:        [4]*QuadtreeState{},
 												},
 												&QuadtreeState{
-													[]float64{1, 0, 1, 1},
-													[4]*QuadtreeState{},
+													PhysicalObjects: []float64{1, 0, 1, 1},
+													SubTrees:        [4]*QuadtreeState{},
 												},
 												&QuadtreeState{
-													[]float64{0, 1, 1, 1},
-													[4]*QuadtreeState{},
+													PhysicalObjects: []float64{0, 1, 1, 1},
+													SubTrees:        [4]*QuadtreeState{},
 												},
 											},
 										},
@@ -814,9 +624,9 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{1.5, 1.5, 1, 1},
-							[4]*QuadtreeState{
-								&QuadtreeState{[]float64{0.5, 0.5, 1, 1}, [4]*QuadtreeState{}},
+							PhysicalObjects: []float64{1.5, 1.5, 1, 1},
+							SubTrees: [4]*QuadtreeState{
+								&QuadtreeState{PhysicalObjects: []float64{0.5, 0.5, 1, 1}, SubTrees: [4]*QuadtreeState{}},
 							},
 						},
 					)},
@@ -827,12 +637,12 @@ var (
 					),
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{
+							PhysicalObjects: []float64{
 								1.5, 1.5, 1, 1,
 								3, 1.5, 1, 1,
 							},
-							[4]*QuadtreeState{
-								&QuadtreeState{[]float64{0.5, 0.5, 1, 1}, [4]*QuadtreeState{}},
+							SubTrees: [4]*QuadtreeState{
+								&QuadtreeState{PhysicalObjects: []float64{0.5, 0.5, 1, 1}, SubTrees: [4]*QuadtreeState{}},
 							},
 						},
 					)},
@@ -853,9 +663,9 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{1.5, 1.5, 1, 1},
-							[4]*QuadtreeState{
-								&QuadtreeState{[]float64{0, 0, 1, 1}, [4]*QuadtreeState{}},
+							PhysicalObjects: []float64{1.5, 1.5, 1, 1},
+							SubTrees: [4]*QuadtreeState{
+								&QuadtreeState{PhysicalObjects: []float64{0, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}},
 							},
 						},
 					)},
@@ -866,19 +676,19 @@ var (
 					),
 					Expectation: []ExpectationFunc{EX_CheckState(
 						&QuadtreeState{
-							[]float64{1.5, 1.5, 1, 1},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{1.5, 1.5, 1, 1},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{},
-									[4]*QuadtreeState{
+									PhysicalObjects: []float64{},
+									SubTrees: [4]*QuadtreeState{
 										&QuadtreeState{
-											[]float64{0, 0, 1, 1},
-											[4]*QuadtreeState{},
+											PhysicalObjects: []float64{0, 0, 1, 1},
+											SubTrees:        [4]*QuadtreeState{},
 										},
 										nil,
 										&QuadtreeState{
-											[]float64{0, 1, 1, 1},
-											[4]*QuadtreeState{},
+											PhysicalObjects: []float64{0, 1, 1, 1},
+											SubTrees:        [4]*QuadtreeState{},
 										},
 										nil,
 									},
@@ -903,9 +713,9 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{1.5, 1.5, 1, 1},
-							[4]*QuadtreeState{
-								&QuadtreeState{[]float64{0, 0.5, 1, 1}, [4]*QuadtreeState{}},
+							PhysicalObjects: []float64{1.5, 1.5, 1, 1},
+							SubTrees: [4]*QuadtreeState{
+								&QuadtreeState{PhysicalObjects: []float64{0, 0.5, 1, 1}, SubTrees: [4]*QuadtreeState{}},
 							},
 						},
 						nil,
@@ -917,16 +727,16 @@ var (
 					),
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{
+							PhysicalObjects: []float64{
 								1.5, 1.5, 1, 1,
 							},
-							[4]*QuadtreeState{
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{
+									PhysicalObjects: []float64{
 										0, 0.5, 1, 1,
 										1, 0.5, 1, 1,
 									},
-									[4]*QuadtreeState{},
+									SubTrees: [4]*QuadtreeState{},
 								},
 							},
 						},
@@ -951,13 +761,13 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{
+							PhysicalObjects: []float64{
 								0, 0, 1, 1,
 								1, 0, 1, 1,
 								0, 1, 1, 1,
 								1, 1, 1, 1,
 							},
-							[4]*QuadtreeState{},
+							SubTrees: [4]*QuadtreeState{},
 						},
 						nil,
 					})},
@@ -979,27 +789,27 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{
+							PhysicalObjects: []float64{
 								0.5, 0.5, 1, 1,
 							},
-							[4]*QuadtreeState{
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{
+									PhysicalObjects: []float64{
 										0, 0, 1, 1,
 									},
-									[4]*QuadtreeState{},
+									SubTrees: [4]*QuadtreeState{},
 								},
 								nil,
 								nil,
 								&QuadtreeState{
-									[]float64{
+									PhysicalObjects: []float64{
 										1, 1, 1, 1,
 									},
-									[4]*QuadtreeState{},
+									SubTrees: [4]*QuadtreeState{},
 								},
 							},
 						},
-						[]PhysicalObject{
+						[]quadtree.PhysicalObject{
 							&TestPhysicalObject{0.5, 0.5, 1, 1},
 							&TestPhysicalObject{0, 0, 1, 1},
 							&TestPhysicalObject{0.5, 0.5, 1, 1},
@@ -1024,14 +834,14 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{
+							PhysicalObjects: []float64{
 								0.5, 0.5, 1, 1,
 								0, 0, 1, 1,
 								1, 1, 1, 1,
 							},
-							[4]*QuadtreeState{},
+							SubTrees: [4]*QuadtreeState{},
 						},
-						[]PhysicalObject{
+						[]quadtree.PhysicalObject{
 							&TestPhysicalObject{0.5, 0.5, 1, 1},
 							&TestPhysicalObject{0, 0, 1, 1},
 							&TestPhysicalObject{0.5, 0.5, 1, 1},
@@ -1056,26 +866,26 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{
+							PhysicalObjects: []float64{
 								1.5, 1.5, 1, 1,
 							},
-							[4]*QuadtreeState{
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{},
-									[4]*QuadtreeState{
+									PhysicalObjects: []float64{},
+									SubTrees: [4]*QuadtreeState{
 										&QuadtreeState{
-											[]float64{
+											PhysicalObjects: []float64{
 												0, 0, 1, 1,
 											},
-											[4]*QuadtreeState{},
+											SubTrees: [4]*QuadtreeState{},
 										},
 										nil,
 										nil,
 										&QuadtreeState{
-											[]float64{
+											PhysicalObjects: []float64{
 												1, 1, 1, 1,
 											},
-											[4]*QuadtreeState{},
+											SubTrees: [4]*QuadtreeState{},
 										},
 									},
 								},
@@ -1084,7 +894,7 @@ var (
 								nil,
 							},
 						},
-						[]PhysicalObject{
+						[]quadtree.PhysicalObject{
 							&TestPhysicalObject{1.5, 1.5, 1, 1},
 							&TestPhysicalObject{1, 1, 1, 1},
 						},
@@ -1109,27 +919,27 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{
+							PhysicalObjects: []float64{
 								1.5, 1.5, 1, 1,
 							},
-							[4]*QuadtreeState{
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{
+									PhysicalObjects: []float64{
 										0.5, 0, 1, 1,
 									},
-									[4]*QuadtreeState{
+									SubTrees: [4]*QuadtreeState{
 										&QuadtreeState{
-											[]float64{
+											PhysicalObjects: []float64{
 												0, 0, 1, 1,
 												0, 0, 1, 1,
 											},
-											[4]*QuadtreeState{},
+											SubTrees: [4]*QuadtreeState{},
 										},
 										nil,
 										nil,
 										&QuadtreeState{
-											[]float64{1, 1, 1, 1},
-											[4]*QuadtreeState{},
+											PhysicalObjects: []float64{1, 1, 1, 1},
+											SubTrees:        [4]*QuadtreeState{},
 										},
 									},
 								},
@@ -1138,7 +948,7 @@ var (
 								nil,
 							},
 						},
-						[]PhysicalObject{
+						[]quadtree.PhysicalObject{
 							// 0 vs. 2
 							&TestPhysicalObject{1.5, 1.5, 1, 1},
 							&TestPhysicalObject{1, 1, 1, 1},
@@ -1172,15 +982,15 @@ var (
 					Operation: OP_Build,
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{
-									[]float64{0, 0, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{0, 0, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 								&QuadtreeState{
-									[]float64{1, 0, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{1, 0, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 								nil,
 								nil,
@@ -1194,16 +1004,16 @@ var (
 					Operation: OP_UpdateObject(0, 0, 1, 1),
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{}, // 旧的节点保留，但是没有物理对象
 								&QuadtreeState{
-									[]float64{1, 0, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{1, 0, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 								&QuadtreeState{
-									[]float64{0, 1, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{0, 1, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 								nil,
 							},
@@ -1216,16 +1026,16 @@ var (
 					Operation: OP_UpdateObject(0, 0, 1, 63),
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{},
+							SubTrees: [4]*QuadtreeState{
 								&QuadtreeState{}, // 旧的节点保留，但是没有物理对象
 								&QuadtreeState{
-									[]float64{1, 0, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{1, 0, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 								&QuadtreeState{
-									[]float64{0, 1, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{0, 1, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 								nil,
 							},
@@ -1238,16 +1048,16 @@ var (
 					Operation: OP_UpdateObject(0, 0, 1, 1),
 					Expectation: []ExpectationFunc{EX_CheckStateAndIntersections(&TestExpectation{
 						&QuadtreeState{
-							[]float64{},
-							[4]*QuadtreeState{
+							PhysicalObjects: []float64{},
+							SubTrees: [4]*QuadtreeState{
 								nil,
 								&QuadtreeState{
-									[]float64{1, 0, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{1, 0, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 								&QuadtreeState{
-									[]float64{0, 1, 1, 1},
-									[4]*QuadtreeState{},
+									PhysicalObjects: []float64{0, 1, 1, 1},
+									SubTrees:        [4]*QuadtreeState{},
 								},
 								nil,
 							},
@@ -1263,7 +1073,7 @@ var (
 func TestAll(t *testing.T) {
 	for testIndex, testCase := range testCases {
 		// setup
-		var objects []PhysicalObject
+		var objects []quadtree.PhysicalObject
 		for i := 0; i < len(testCase.Setup.PhysicalObjects); i += 4 {
 			objects = append(objects, &TestPhysicalObject{
 				x:      testCase.Setup.PhysicalObjects[i],
@@ -1272,8 +1082,8 @@ func TestAll(t *testing.T) {
 				height: testCase.Setup.PhysicalObjects[i+3],
 			})
 		}
-		qt := CreateQuadtree(
-			&Bounds{testCase.Setup.X, testCase.Setup.Y, testCase.Setup.Width, testCase.Setup.Height},
+		qt := quadtree.CreateQuadtree(
+			&quadtree.Bounds{testCase.Setup.X, testCase.Setup.Y, testCase.Setup.Width, testCase.Setup.Height},
 			testCase.Setup.MaxObjects,
 			testCase.Setup.MaxLevels,
 			objects...,
@@ -1288,3 +1098,151 @@ func TestAll(t *testing.T) {
 		}
 	}
 }
+
+func TestNegativeOrigin(t *testing.T) {
+	for _, bounds := range []*quadtree.Bounds{
+		&quadtree.Bounds{-4, -4, 4, 4},
+		&quadtree.Bounds{10, 20, 4, 4},
+		quadtree.OriginCenteredBounds(4, 4),
+	} {
+		objects := []quadtree.PhysicalObject{
+			&TestPhysicalObject{bounds.X, bounds.Y, 1, 1},
+			&TestPhysicalObject{bounds.X + 3, bounds.Y, 1, 1},
+			&TestPhysicalObject{bounds.X, bounds.Y + 3, 1, 1},
+			&TestPhysicalObject{bounds.X + 3, bounds.Y + 3, 1, 1},
+			&TestPhysicalObject{bounds.X + 1.5, bounds.Y + 1.5, 1, 1},
+		}
+		qt := quadtree.CreateQuadtree(bounds, 1, 1, objects...)
+		qt.Build()
+
+		expected := &QuadtreeState{
+			PhysicalObjects: []float64{bounds.X + 1.5, bounds.Y + 1.5, 1, 1},
+			SubTrees: [4]*QuadtreeState{
+				&QuadtreeState{PhysicalObjects: []float64{bounds.X, bounds.Y, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+				&QuadtreeState{PhysicalObjects: []float64{bounds.X + 3, bounds.Y, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+				&QuadtreeState{PhysicalObjects: []float64{bounds.X, bounds.Y + 3, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+				&QuadtreeState{PhysicalObjects: []float64{bounds.X + 3, bounds.Y + 3, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+			},
+		}
+		if realState := quadtreetest.DumpState(qt); !realState.Check(expected) {
+			t.Errorf("Quadtree with bounds %+v expects to be in state:\n%s\nBut in state:\n%s", *bounds, expected.String(0), realState.String(0))
+		}
+
+		inserted := quadtree.CreateQuadtree(bounds, 1, 1)
+		for _, obj := range objects {
+			inserted.Insert(obj)
+		}
+		if realState := quadtreetest.DumpState(inserted); !realState.Check(expected) {
+			t.Errorf("Quadtree with bounds %+v expects to be in state after Insert:\n%s\nBut in state:\n%s", *bounds, expected.String(0), realState.String(0))
+		}
+	}
+}
+
+func TestBuildIncremental(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 10)
+	qt.Insert(&TestPhysicalObject{0, 0, 1, 1})
+	qt.Insert(&TestPhysicalObject{3, 3, 1, 1})
+	straddler := &TestPhysicalObject{1.5, 1.5, 1, 1}
+	qt.Insert(straddler)
+
+	expected := &QuadtreeState{
+		PhysicalObjects: []float64{1.5, 1.5, 1, 1},
+		SubTrees: [4]*QuadtreeState{
+			&QuadtreeState{PhysicalObjects: []float64{0, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+			nil,
+			nil,
+			&QuadtreeState{PhysicalObjects: []float64{3, 3, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+		},
+	}
+	for i := 0; i < 2; i += 1 {
+		qt.Build()
+		if realState := quadtreetest.DumpState(qt); !realState.Check(expected) {
+			t.Fatalf("Quadtree expects to be in state after Build %d:\n%s\nBut in state:\n%s", i+1, expected.String(0), realState.String(0))
+		}
+	}
+
+	// objects left in a subdevided node after moving are redistributed into existing children
+	straddler.x, straddler.y = 1, 0
+	qt.Insert(&TestPhysicalObject{2, 0, 1, 1})
+	qt.Build()
+	expected = &QuadtreeState{
+		PhysicalObjects: []float64{},
+		SubTrees: [4]*QuadtreeState{
+			&QuadtreeState{
+				PhysicalObjects: []float64{},
+				SubTrees: [4]*QuadtreeState{
+					&QuadtreeState{PhysicalObjects: []float64{0, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+					&QuadtreeState{PhysicalObjects: []float64{1, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+				},
+			},
+			&QuadtreeState{PhysicalObjects: []float64{2, 0, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+			nil,
+			&QuadtreeState{PhysicalObjects: []float64{3, 3, 1, 1}, SubTrees: [4]*QuadtreeState{}},
+		},
+	}
+	if realState := quadtreetest.DumpState(qt); !realState.Check(expected) {
+		t.Errorf("Quadtree expects to be in state:\n%s\nBut in state:\n%s", expected.String(0), realState.String(0))
+	}
+}
+
+func TestZeroValue(t *testing.T) {
+	var zero quadtree.Quadtree
+	obj := &TestPhysicalObject{1, 1, 1, 1}
+	if err := zero.Insert(obj); err != nil {
+		t.Fatal(err)
+	}
+	zero.Update(0)
+	if found := zero.Retrieve(&quadtree.Bounds{0, 0, 2, 2}); len(found) != 1 || zero.FindObject(obj) != &zero {
+		t.Errorf("zero value tree expects to hold the inserted object, got %v", found)
+	}
+
+	var embedded struct {
+		index quadtree.Quadtree
+	}
+	embedded.index.Init(&quadtree.Bounds{0, 0, 4, 4}, 1, 10)
+	embedded.index.Insert(&TestPhysicalObject{0, 0, 1, 1})
+	embedded.index.Insert(&TestPhysicalObject{3, 3, 1, 1})
+	if embedded.index.Nodes[0] == nil || embedded.index.Nodes[3] == nil {
+		t.Errorf("initialized tree expects to split:\n%s", embedded.index.String())
+	}
+
+	var nilTree *quadtree.Quadtree
+	if nilTree.Insert(obj) != quadtree.ErrNilQuadtree {
+		t.Errorf("inserting into a nil tree expects ErrNilQuadtree")
+	}
+	nilTree.Update(0)
+	nilTree.Walk(func(quadtree.PhysicalObject) { t.Errorf("nil tree expects no objects") })
+	if nilTree.Retrieve(&quadtree.Bounds{0, 0, 1, 1}) != nil || nilTree.FindObject(obj) != nil ||
+		nilTree.Remove(obj) || nilTree.GetIntersection(nil, nil).Len() != 0 || nilTree.String() != "" {
+		t.Errorf("nil tree expects empty results")
+	}
+}
+
+func TestGetIntersectedObjectsPruning(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	for _, opts := range [][]quadtree.Option{nil, {quadtree.WithStraddlePolicy(quadtree.StraddleLoose)}, {quadtree.WithEpsilon(0.5)}} {
+		qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 64, 64}, 2, 5, opts...)
+		var objects []quadtree.PhysicalObject
+		for i := 0; i < 300; i += 1 {
+			obj := &TestPhysicalObject{rng.Float64() * 62, rng.Float64() * 62, rng.Float64() * 2, rng.Float64() * 2}
+			objects = append(objects, obj)
+			qt.Insert(obj)
+		}
+		// straddlers of the midpoints stay at the root
+		for _, obj := range []*TestPhysicalObject{{31, 10, 3, 1}, {10, 31.5, 1, 1}, {31.5, 31.5, 1, 1}, {20, 20, 30, 2}} {
+			objects = append(objects, obj)
+			qt.Insert(obj)
+		}
+		for _, target := range objects {
+			var expected []quadtree.PhysicalObject
+			for _, obj := range objects {
+				if obj != target && quadtree.Intersect(target, obj) {
+					expected = append(expected, obj)
+				}
+			}
+			if found := qt.GetIntersectedObjectsRaw(target, nil); !sameObjects(found, expected...) {
+				t.Fatalf("expects %v to intersect %v, got %v", target, expected, found)
+			}
+		}
+	}
+}
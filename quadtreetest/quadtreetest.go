@@ -0,0 +1,210 @@
+// Package quadtreetest provides helpers to assert the shape and the intersections of a quadtree in tests
+package quadtreetest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// QuadtreeState defines the expected state of a Quadtree
+type QuadtreeState struct {
+	PhysicalObjects []float64         // groupds of (X, Y, Width, Height), representing objects in the root node
+	SubTrees        [4]*QuadtreeState // nil element to identify that no such subtree should be created
+}
+
+func (qs *QuadtreeState) String(indent int) string {
+	var buf bytes.Buffer
+	indentString := strings.Repeat("\t", indent)
+
+	for i := 0; i < len(qs.PhysicalObjects); i += 4 {
+		buf.WriteString(indentString)
+		buf.WriteString(
+			fmt.Sprintf(
+				"%-10.2f%-10.2f%-10.2f%-10.2f\n",
+				qs.PhysicalObjects[i],
+				qs.PhysicalObjects[i+1],
+				qs.PhysicalObjects[i+2],
+				qs.PhysicalObjects[i+3],
+			),
+		)
+	}
+	for i, subTree := range qs.SubTrees {
+		if subTree == nil {
+			continue
+		}
+		buf.WriteString(indentString)
+		buf.WriteString(fmt.Sprintf("%d:\n", i))
+		buf.WriteString(subTree.String(indent + 1))
+	}
+	return buf.String()
+}
+
+// Check whether the real state matches the expected state, ignoring the order of objects within a node
+func (realState *QuadtreeState) Check(state *QuadtreeState) bool {
+	if len(state.PhysicalObjects) != len(realState.PhysicalObjects) {
+		return false
+	}
+
+	usedIndex := map[int]bool{}
+	for i := 0; i < len(state.PhysicalObjects); i = i + 4 {
+		found := false
+		for k := 0; k < len(realState.PhysicalObjects); k += 4 {
+			if !usedIndex[k] &&
+				realState.PhysicalObjects[k] == state.PhysicalObjects[i] &&
+				realState.PhysicalObjects[k+1] == state.PhysicalObjects[i+1] &&
+				realState.PhysicalObjects[k+2] == state.PhysicalObjects[i+2] &&
+				realState.PhysicalObjects[k+3] == state.PhysicalObjects[i+3] {
+
+				found = true
+				usedIndex[k] = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	for i, subTreeState := range state.SubTrees {
+		if subTreeState == nil {
+			// subtree created when should not
+			if realState.SubTrees[i] != nil {
+				return false
+			}
+		} else {
+			// subtree not created when shoud
+			if realState.SubTrees[i] == nil {
+				return false
+			} else {
+				// evaluates state of subtree
+				if !realState.SubTrees[i].Check(subTreeState) {
+					return false
+				}
+			}
+		}
+	}
+
+	return true
+}
+
+// DumpState captures the current state of the quadtree
+func DumpState(qt *quadtree.Quadtree) *QuadtreeState {
+	state := &QuadtreeState{}
+	for _, obj := range qt.Objects() {
+		state.PhysicalObjects = append(state.PhysicalObjects, obj.X(), obj.Y(), obj.Width(), obj.Height())
+	}
+
+	for index, node := range qt.Nodes {
+		if node != nil {
+			state.SubTrees[index] = DumpState(node)
+		}
+	}
+	return state
+}
+
+// list of pair of PhysicalObject representing intersected objects
+type QuadtreeIntersections []quadtree.PhysicalObject
+
+// SameAs checks whether two physical objects have the same position and size
+func SameAs(obj quadtree.PhysicalObject, another quadtree.PhysicalObject) bool {
+	return obj.X() == another.X() &&
+		obj.Y() == another.Y() &&
+		obj.Width() == another.Width() &&
+		obj.Height() == another.Height()
+}
+
+// DumpIntersections captures the intersection records of the quadtree
+func DumpIntersections(qt *quadtree.Quadtree) QuadtreeIntersections {
+	intersectionList := qt.GetIntersection(nil, nil)
+	var intersections []quadtree.PhysicalObject
+	for ele := intersectionList.Front(); ele != nil; ele = ele.Next() {
+		record := ele.Value.(*quadtree.IntersectionRecord)
+		intersections = append(intersections, record.One, record.Another)
+	}
+	return intersections
+}
+
+// Check whether the actual intersections match the expected ones, ignoring order of pairs and within pairs
+func (actual QuadtreeIntersections) Check(expected QuadtreeIntersections) bool {
+	if len(actual) != len(expected) {
+		return false
+	}
+	usedIndex := make(map[int]bool)
+	for i := 0; i < len(actual); i += 2 {
+		found := false
+		for k := 0; k < len(expected); k += 2 {
+			if !usedIndex[k] &&
+				(SameAs(actual[i], expected[k]) && SameAs(actual[i+1], expected[k+1])) ||
+				(SameAs(actual[i], expected[k+1]) && SameAs(actual[i+1], expected[k])) {
+
+				found = true
+				usedIndex[k] = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (inter QuadtreeIntersections) String() string {
+	if len(inter) == 0 {
+		return "None"
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < len(inter); i += 2 {
+		one := inter[i]
+		another := inter[i+1]
+		buf.WriteString(
+			fmt.Sprintf(
+				"(%-10.2f%-10.2f%-10.2f%-10.2f) (%-10.2f%-10.2f%-10.2f%-10.2f)\n",
+				one.X(), one.Y(), one.Width(), one.Height(),
+				another.X(), another.Y(), another.Width(), another.Height(),
+			),
+		)
+	}
+	return buf.String()
+}
+
+// SameObjects checks whether two lists of intersected objects hold the same objects, ignoring order
+func SameObjects(inter, another quadtree.IntersectedObjects) bool {
+	if len(inter) != len(another) {
+		return false
+	}
+
+	usedIndex := make(map[int]bool)
+	for _, one := range inter {
+		found := false
+		for k, two := range another {
+			if !usedIndex[k] && SameAs(one, two) {
+				found = true
+				usedIndex[k] = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ObjectsString formats a list of intersected objects
+func ObjectsString(inter quadtree.IntersectedObjects) string {
+	var buf bytes.Buffer
+	for _, obj := range inter {
+		buf.WriteString(
+			fmt.Sprintf(
+				"%-10.2f%-10.2f%-10.2f%-10.2f",
+				obj.X(), obj.Y(), obj.Width(), obj.Height(),
+			),
+		)
+	}
+	return buf.String()
+}
@@ -0,0 +1,234 @@
+package quadtree
+
+// boundsOf returns the bounding box of the physical object
+func boundsOf(obj PhysicalObject) *Bounds {
+	return &Bounds{obj.X(), obj.Y(), obj.Width(), obj.Height()}
+}
+
+// visitRegion calls fn with every object stored in nodes which may hold objects overlapping region.
+// Objects are candidates only, callers test them against the region themselves
+func (qt *Quadtree) visitRegion(region *Bounds, fn func(PhysicalObject)) {
+	qt.visitWhere(func(b *Bounds) bool {
+		return b.Intersects(region)
+	}, fn)
+}
+
+// visitWhere calls fn with every object stored in current node, and in the child nodes whose search
+// bounds are accepted by filter, recursively. Objects stored in several nodes are visited once
+func (qt *Quadtree) visitWhere(filter func(*Bounds) bool, fn func(PhysicalObject)) {
+	if qt.m_config.straddlePolicy == StraddleDuplicate {
+		fn = dedupe(fn)
+	}
+	qt.visitWhereRaw(filter, fn)
+}
+
+func (qt *Quadtree) visitWhereRaw(filter func(*Bounds) bool, fn func(PhysicalObject)) {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if obj := ele.Value.(PhysicalObject); !qt.buried(obj) {
+			fn(obj)
+		}
+	}
+
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 && filter(qt.Nodes[index].searchBounds()) {
+			qt.Nodes[index].visitWhereRaw(filter, fn)
+		}
+		flags >>= 1
+		index += 1
+	}
+}
+
+// QueryOption configures a single query
+type QueryOption func(*queryConfig)
+
+type queryConfig struct {
+	maxDepth   int                       // deepest level of the tree the query descends to, -1 for no limit
+	stats      *QueryStats               // sink counting the work of the query, may be nil
+	accept     func(PhysicalObject) bool // objects rejected are skipped before being tested, may be nil
+	budget     int                       // nodes the query may visit, 0 for no limit
+	visited    int                       // nodes visited so far
+	candidates int                       // objects the query may test, 0 for no limit
+	tested     int                       // objects tested so far
+	truncated  bool                      // the query ran out of budget
+	except     map[PhysicalObject]bool   // objects skipped by the query, may be nil
+	handles    HandleSet                 // handles of the objects skipped by the query, may be nil
+	borrow     bool                      // results are appended to the buffer of the tree
+}
+
+func newQueryConfig(opts []QueryOption) *queryConfig {
+	qc := &queryConfig{maxDepth: -1}
+	for _, opt := range opts {
+		opt(qc)
+	}
+	return qc
+}
+
+// MaxDepth stops the query from descending past the given level of the tree,
+// deeper objects are summarized by the Cluster of their node at that level
+func MaxDepth(level int) QueryOption {
+	return func(qc *queryConfig) {
+		qc.maxDepth = level
+	}
+}
+
+// QueryStats counts the work done by queries, telling apart the cost of traversing the tree
+// from the cost of testing the objects it yields
+type QueryStats struct {
+	NodesVisited     int  // nodes whose objects were tested
+	CandidatesTested int  // objects tested against the query shape
+	Truncated        bool // the query ran out of budget, its result is incomplete
+}
+
+// WithStats makes the query add the work it does to stats
+func WithStats(stats *QueryStats) QueryOption {
+	return func(qc *queryConfig) {
+		qc.stats = stats
+	}
+}
+
+// Except skips the objects during the traversal, such as the body parts and the mount of the object
+// querying its surroundings, without testing their bounds
+func Except(objs ...PhysicalObject) QueryOption {
+	return func(qc *queryConfig) {
+		if qc.except == nil {
+			qc.except = make(map[PhysicalObject]bool, len(objs))
+		}
+		for _, obj := range objs {
+			qc.except[obj] = true
+		}
+	}
+}
+
+// ExceptHandles skips the objects of the handles in set during the traversal, see Except
+func ExceptHandles(set HandleSet) QueryOption {
+	return func(qc *queryConfig) {
+		qc.handles = set
+	}
+}
+
+// accepts tells whether the query tests an object of node, rather than skipping it.
+// A nil query accepts every object, as do enter and test
+func (qc *queryConfig) accepts(node *Quadtree, obj PhysicalObject) bool {
+	if qc == nil {
+		return true
+	}
+	if qc.except[obj] {
+		return false
+	}
+	if qc.handles != nil {
+		if store := node.m_config.handles; store != nil {
+			if h, ok := store.handles[obj]; ok && qc.handles.Contains(h) {
+				return false
+			}
+		}
+	}
+	return qc.accept == nil || qc.accept(obj)
+}
+
+// acceptOnly makes the query skip the objects accept rejects, on top of those it already skips
+func acceptOnly(accept func(PhysicalObject) bool) QueryOption {
+	return func(qc *queryConfig) {
+		if previous := qc.accept; previous != nil {
+			qc.accept = func(obj PhysicalObject) bool {
+				return previous(obj) && accept(obj)
+			}
+			return
+		}
+		qc.accept = accept
+	}
+}
+
+// WithBudget stops the query once it visited maxNodes nodes, bounding its worst case cost on degenerate
+// scenes such as every object stacked at one point. The result then only holds the objects of the nodes
+// visited, QueryTruncated, Cursor.Truncated and WithStats report the truncation
+func WithBudget(maxNodes int) QueryOption {
+	return func(qc *queryConfig) {
+		qc.budget = maxNodes
+	}
+}
+
+// WithCandidateBudget stops the query once it tested maxCandidates objects, which WithBudget alone
+// does not bound when many objects are stored in a single node. The truncation is reported as for
+// WithBudget
+func WithCandidateBudget(maxCandidates int) QueryOption {
+	return func(qc *queryConfig) {
+		qc.candidates = maxCandidates
+	}
+}
+
+// QueryTruncated tells whether the last Retrieve, RetrieveClusters, QueryRing, QueryAlongPath, or
+// GetIntersection, GetIntersectedObjects or NearestInDirection given options, of the tree ran out of
+// budget, so that its result is incomplete
+func (qt *Quadtree) QueryTruncated() bool {
+	return qt.ready() && qt.m_config.queryTruncated
+}
+
+// truncate records that the query ran out of budget
+func (qc *queryConfig) truncate() {
+	qc.truncated = true
+	if qc.stats != nil {
+		qc.stats.Truncated = true
+	}
+}
+
+// enter counts a node the query is about to visit, and tells whether the budget allows it
+func (qc *queryConfig) enter() bool {
+	if qc == nil {
+		return true
+	}
+	if qc.truncated {
+		return false
+	}
+	if qc.budget > 0 && qc.visited >= qc.budget {
+		qc.truncate()
+		return false
+	}
+	qc.visited += 1
+	if qc.stats != nil {
+		qc.stats.NodesVisited += 1
+	}
+	return true
+}
+
+// test counts an object the query is about to test, and tells whether the budget allows it
+func (qc *queryConfig) test() bool {
+	if qc == nil {
+		return true
+	}
+	if qc.truncated {
+		return false
+	}
+	if qc.candidates > 0 && qc.tested >= qc.candidates {
+		qc.truncate()
+		return false
+	}
+	qc.tested += 1
+	if qc.stats != nil {
+		qc.stats.CandidatesTested += 1
+	}
+	return true
+}
+
+// report records the truncation of the query for QueryTruncated
+func (qc *queryConfig) report(c *config) {
+	c.queryTruncated = qc.truncated
+}
+
+// instrument wraps the node filter and the object visitor of a traversal starting at node, so that
+// they count into the stats and the budget of the query, and skip the objects the query excludes
+func (qc *queryConfig) instrument(node *Quadtree, filter func(*Bounds) bool, fn func(PhysicalObject)) (func(*Bounds) bool, func(PhysicalObject)) {
+	if qc == nil || qc.stats == nil && qc.budget == 0 && qc.candidates == 0 && qc.except == nil && qc.handles == nil && qc.accept == nil {
+		return filter, fn
+	}
+	qc.enter()
+	return func(b *Bounds) bool {
+			return filter(b) && qc.enter()
+		}, func(obj PhysicalObject) {
+			if !qc.accepts(node, obj) || !qc.test() {
+				return
+			}
+			fn(obj)
+		}
+}
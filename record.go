@@ -0,0 +1,208 @@
+package quadtree
+
+import (
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// RecordingVersion is the version of the recording format written by Record
+const RecordingVersion = 1
+
+// recorder writes a timestamped stream of the operations on a tree, see Record
+type recorder struct {
+	w        io.Writer
+	start    time.Time
+	last     uint64
+	ids      map[PhysicalObject]uint64
+	updating bool  // whether moves are reported by Update
+	err      error // first write error
+}
+
+// Record starts recording every operation on the tree to w, replacing any previous recording,
+// Record(nil) stops recording. It returns the first error met while writing the previous recording.
+// Each line starts with the nanoseconds elapsed since recording started, followed by one of:
+//
+//	version VERSION
+//	option NAME VALUE...
+//	tree X Y WIDTH HEIGHT MAXOBJECTS MAXLEVELS
+//	insert ID X Y WIDTH HEIGHT
+//	move ID X Y WIDTH HEIGHT
+//	remove ID
+//	reset
+//	updated ID X Y WIDTH HEIGHT
+//	update DELTA
+//	retrieve X Y WIDTH HEIGHT
+//	intersected ID
+//	intersections
+//
+// The recording starts with the RecordingVersion of the format, an option line for each option of the
+// tree which changes where objects are stored, the tree line and the insertion of the objects already
+// in the tree. Options are named after their Option without the With prefix, e.g. straddle-policy or
+// fixed-point, followed by its arguments.
+// Objects are numbered in the order they entered the tree. An Update is recorded once done,
+// after the objects it moved. A reset means the tree was rebuilt, its objects are inserted again.
+// Options taking functions, such as WithMaxObjectsFunc or WithSplitChooser, are not recorded, nor the
+// function arguments of the options recorded. The quadtree-replay command runs a recording again
+func (qt *Quadtree) Record(w io.Writer) error {
+	if !qt.ready() {
+		return ErrNilQuadtree
+	}
+	var err error
+	if old := qt.m_config.recorder; old != nil {
+		err = old.err
+	}
+	qt.m_config.recorder = nil
+	if w == nil {
+		return err
+	}
+
+	root := qt.root()
+	rec := &recorder{w: w, start: time.Now(), ids: make(map[PhysicalObject]uint64)}
+	rec.write("version", strconv.Itoa(RecordingVersion))
+	for _, option := range root.m_config.recordedOptions() {
+		rec.write("option", option)
+	}
+	rec.write("tree", formatFields(root.X, root.Y, root.Width, root.Height,
+		float64(root.MaxObjects), float64(root.MaxLevels)))
+	root.m_config.recorder = rec
+	root.Walk(rec.inserted)
+	return err
+}
+
+// formatFields formats numbers in their shortest exact representation, separated by spaces
+func formatFields(values ...float64) string {
+	s := ""
+	for i, v := range values {
+		if i > 0 {
+			s += " "
+		}
+		s += formatFloat(v)
+	}
+	return s
+}
+
+// recordedOptions describes the options of the tree which change how it stores objects, as the name of
+// the option followed by its arguments, for the options which differ from the defaults
+func (c *config) recordedOptions() []string {
+	var options []string
+	add := func(name string, args ...float64) {
+		if len(args) > 0 {
+			name += " " + formatFields(args...)
+		}
+		options = append(options, name)
+	}
+	if c.straddlePolicy != StraddleKeepAtParent {
+		add("straddle-policy", float64(c.straddlePolicy))
+	}
+	if c.childOverlap != 0 {
+		add("child-overlap", c.childOverlap)
+	}
+	if c.epsilon != 0 {
+		add("epsilon", c.epsilon)
+	}
+	if c.lifespan != defaultLifespan || c.lifespanDoublingLimit != defaultLifespan {
+		add("lifespan", float64(c.lifespan), float64(c.lifespanDoublingLimit))
+	}
+	if c.eagerCollapse {
+		add("eager-collapse")
+	}
+	if c.integerGrid {
+		add("integer-grid")
+	}
+	if c.fixedPoint {
+		add("fixed-point", math.Log2(c.fixedScale))
+	}
+	if c.rejectDuplicates {
+		add("reject-duplicates")
+	}
+	if c.invalidPolicy != 0 {
+		add("invalid-coordinates-policy", float64(c.invalidPolicy))
+	}
+	if q := c.relocationQueue; q != nil {
+		add("relocation-budget", float64(q.budget))
+	}
+	if c.yAxis != 0 {
+		add("y-axis", float64(c.yAxis))
+	}
+	if c.curveOrder != 0 {
+		add("curve-order", float64(c.curveOrder))
+	}
+	return options
+}
+
+func formatObject(id uint64, obj PhysicalObject) string {
+	return strconv.FormatUint(id, 10) + " " + formatFields(obj.X(), obj.Y(), obj.Width(), obj.Height())
+}
+
+func (r *recorder) write(op string, args string) {
+	if r.err != nil {
+		return
+	}
+	line := strconv.FormatInt(int64(time.Since(r.start)), 10) + " " + op
+	if args != "" {
+		line += " " + args
+	}
+	_, r.err = io.WriteString(r.w, line+"\n")
+}
+
+func (r *recorder) inserted(obj PhysicalObject) {
+	if _, ok := r.ids[obj]; ok {
+		return
+	}
+	r.last += 1
+	r.ids[obj] = r.last
+	r.write("insert", formatObject(r.last, obj))
+}
+
+func (r *recorder) moved(obj PhysicalObject) {
+	if id, ok := r.ids[obj]; ok {
+		op := "move"
+		if r.updating {
+			op = "updated"
+		}
+		r.write(op, formatObject(id, obj))
+	}
+}
+
+func (r *recorder) removed(obj PhysicalObject) {
+	if id, ok := r.ids[obj]; ok {
+		delete(r.ids, obj)
+		r.write("remove", strconv.FormatUint(id, 10))
+	}
+}
+
+func (r *recorder) rebuilt(root *Quadtree) {
+	r.ids = make(map[PhysicalObject]uint64)
+	r.write("reset", "")
+	root.Walk(r.inserted)
+}
+
+// recordUpdate runs an Update, recording the moves it reports and then the Update itself
+func (qt *Quadtree) recordUpdate(delta time.Duration, update func()) {
+	rec := qt.m_config.recorder
+	if rec == nil {
+		update()
+		return
+	}
+	rec.updating = true
+	update()
+	rec.updating = false
+	rec.write("update", delta.String())
+}
+
+// recordQuery records a query, args describe its parameters
+func (qt *Quadtree) recordQuery(op string, args string) {
+	if rec := qt.m_config.recorder; rec != nil {
+		rec.write(op, args)
+	}
+}
+
+// recordedID returns the ID of the object in the recording, 0 if it has none
+func (qt *Quadtree) recordedID(obj PhysicalObject) uint64 {
+	if rec := qt.m_config.recorder; rec != nil {
+		return rec.ids[obj]
+	}
+	return 0
+}
@@ -0,0 +1,82 @@
+package quadtree_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// stripTimestamps drops the timestamp starting every line of a recording
+func stripTimestamps(recording string) string {
+	lines := strings.Split(strings.TrimSuffix(recording, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = line[strings.Index(line, " ")+1:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestRecord(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	a := &TestPhysicalObject{1, 1, 1, 1}
+	qt.Insert(a)
+
+	var buf bytes.Buffer
+	if err := qt.Record(&buf); err != nil {
+		t.Fatal(err)
+	}
+	b := &TestPhysicalObject{6, 6, 1, 1}
+	qt.Insert(b)
+	qt.Update(16 * time.Millisecond)
+	qt.Retrieve(&quadtree.Bounds{0, 0, 4, 4})
+	qt.GetIntersectedObjects(b)
+	b.x = 5
+	qt.InsertOrMove(b)
+	qt.Remove(a)
+	if err := qt.Record(nil); err != nil {
+		t.Fatal(err)
+	}
+	qt.Remove(b)
+
+	want := strings.Join([]string{
+		"version 1",
+		"tree 0 0 8 8 1 10",
+		"insert 1 1 1 1 1",
+		"insert 2 6 6 1 1",
+		"updated 1 1 1 1 1",
+		"updated 2 6 6 1 1",
+		"update 16ms",
+		"retrieve 0 0 4 4",
+		"intersected 2",
+		"move 2 5 6 1 1",
+		"remove 1",
+	}, "\n")
+	if got := stripTimestamps(buf.String()); got != want {
+		t.Errorf("expects recording\n%s\ngot\n%s", want, got)
+	}
+}
+
+func TestRecordOptions(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10,
+		quadtree.WithStraddlePolicy(quadtree.StraddleLoose), quadtree.WithChildOverlap(0.5),
+		quadtree.WithLifespan(4, 32), quadtree.WithFixedPoint(8), quadtree.WithRejectDuplicates())
+
+	var buf bytes.Buffer
+	if err := qt.Record(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"version 1",
+		"option straddle-policy 2",
+		"option child-overlap 0.5",
+		"option lifespan 4 32",
+		"option fixed-point 8",
+		"option reject-duplicates",
+		"tree 0 0 8 8 1 10",
+	}, "\n")
+	if got := stripTimestamps(buf.String()); got != want {
+		t.Errorf("expects recording\n%s\ngot\n%s", want, got)
+	}
+}
@@ -0,0 +1,92 @@
+package regiontree
+
+import (
+	"image"
+	"image/color"
+	"sort"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// HitMaskFromAlpha decomposes the pixels of img whose alpha is at least threshold into rectangles, in
+// pixel coordinates, ready to be inserted as the colliders of a sprite. The opaque pixels are merged into
+// the leaves of a region quadtree, then leaves sharing a whole side are merged into larger rectangles
+func HitMaskFromAlpha(img image.Image, threshold uint8) []quadtree.Bounds {
+	r := img.Bounds()
+	bounds := &quadtree.Bounds{
+		X:      float64(r.Min.X),
+		Y:      float64(r.Min.Y),
+		Width:  float64(r.Dx()),
+		Height: float64(r.Dy()),
+	}
+	tree := build(bounds, r.Dx(), r.Dy(), 1, 0, func(col, row int, values []float64) {
+		values[0] = 0
+		if color.NRGBAModel.Convert(img.At(r.Min.X+col, r.Min.Y+row)).(color.NRGBA).A >= threshold {
+			values[0] = 1
+		}
+	})
+
+	var rects []quadtree.Bounds
+	for _, leaf := range tree.Leaves() {
+		if leaf.Values[0] == 1 {
+			rects = append(rects, leaf.Bounds)
+		}
+	}
+	for mergeSides(rects) {
+		rects = compact(rects)
+	}
+	sort.Sort(byPosition(rects))
+	return rects
+}
+
+// mergeSides merges every pair of rectangles sharing a whole side into the first of the pair, emptying
+// the second, and tells whether any were merged. The rectangles do not overlap, so the rectangle on the
+// right or below another one is found by its top left corner
+func mergeSides(rects []quadtree.Bounds) bool {
+	corners := make(map[[2]float64]int, len(rects))
+	for i, b := range rects {
+		corners[[2]float64{b.X, b.Y}] = i
+	}
+	merged := false
+	for i := range rects {
+		a := &rects[i]
+		for a.Width != 0 {
+			if j, ok := corners[[2]float64{a.X + a.Width, a.Y}]; ok && rects[j].Height == a.Height {
+				a.Width += rects[j].Width
+				delete(corners, [2]float64{rects[j].X, rects[j].Y})
+				rects[j] = quadtree.Bounds{}
+			} else if j, ok := corners[[2]float64{a.X, a.Y + a.Height}]; ok && rects[j].Width == a.Width {
+				a.Height += rects[j].Height
+				delete(corners, [2]float64{rects[j].X, rects[j].Y})
+				rects[j] = quadtree.Bounds{}
+			} else {
+				break
+			}
+			merged = true
+		}
+	}
+	return merged
+}
+
+// compact removes the rectangles emptied by mergeSides
+func compact(rects []quadtree.Bounds) []quadtree.Bounds {
+	kept := rects[:0]
+	for _, b := range rects {
+		if b.Width != 0 {
+			kept = append(kept, b)
+		}
+	}
+	return kept
+}
+
+// byPosition sorts rectangles by row then column of their top left corner
+type byPosition []quadtree.Bounds
+
+func (b byPosition) Len() int      { return len(b) }
+func (b byPosition) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byPosition) Less(i, j int) bool {
+	if b[i].Y != b[j].Y {
+		return b[i].Y < b[j].Y
+	}
+	return b[i].X < b[j].X
+}
@@ -0,0 +1,76 @@
+package regiontree
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestHitMaskFromAlpha(t *testing.T) {
+	// an L shaped sprite: a full column on the left and a full row at the bottom
+	img := image.NewNRGBA(image.Rect(10, 20, 18, 28))
+	for y := 20; y < 28; y += 1 {
+		for x := 10; x < 18; x += 1 {
+			a := uint8(0x10)
+			if x < 12 || y >= 26 {
+				a = 0xff
+			}
+			img.SetNRGBA(x, y, color.NRGBA{0, 0, 0xff, a})
+		}
+	}
+
+	mask := HitMaskFromAlpha(img, 0x80)
+	expected := []quadtree.Bounds{
+		{X: 10, Y: 20, Width: 2, Height: 8},
+		{X: 12, Y: 26, Width: 6, Height: 2},
+	}
+	if !reflect.DeepEqual(mask, expected) {
+		t.Errorf("expects %v, got %v", expected, mask)
+	}
+
+	// every pixel is covered exactly when it passes the threshold
+	for y := 20; y < 28; y += 1 {
+		for x := 10; x < 18; x += 1 {
+			covered := 0
+			for _, b := range mask {
+				if float64(x) >= b.X && float64(x) < b.X+b.Width && float64(y) >= b.Y && float64(y) < b.Y+b.Height {
+					covered += 1
+				}
+			}
+			expected := 0
+			if img.NRGBAAt(x, y).A >= 0x80 {
+				expected = 1
+			}
+			if covered != expected {
+				t.Errorf("expects pixel (%d, %d) to be covered %d times, got %d", x, y, expected, covered)
+			}
+		}
+	}
+
+	if mask := HitMaskFromAlpha(img, 0); len(mask) != 1 || mask[0] != (quadtree.Bounds{X: 10, Y: 20, Width: 8, Height: 8}) {
+		t.Errorf("expects the whole sprite, got %v", mask)
+	}
+}
+
+func TestHitMaskMergesRows(t *testing.T) {
+	// opaque odd rows split into as many leaves as pixels, merged back into one rectangle per row
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 1; y < 64; y += 2 {
+		for x := 0; x < 64; x += 1 {
+			img.SetNRGBA(x, y, color.NRGBA{0, 0, 0, 0xff})
+		}
+	}
+
+	mask := HitMaskFromAlpha(img, 0x80)
+	if len(mask) != 32 {
+		t.Fatalf("expects one rectangle per opaque row, got %v", mask)
+	}
+	for i, b := range mask {
+		if expected := (quadtree.Bounds{X: 0, Y: float64(2*i + 1), Width: 64, Height: 1}); b != expected {
+			t.Errorf("expects rectangle %d to be %v, got %v", i, expected, b)
+		}
+	}
+}
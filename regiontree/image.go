@@ -0,0 +1,72 @@
+package regiontree
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// FromImage builds a tree with the four channels red, green, blue and alpha of img, scaled to [0, 1]
+// and not premultiplied, one sample per pixel. The tree covers the bounds of img in pixels. Quadrants
+// whose channels differ by no more than tolerance are merged into a leaf holding their mean color
+func FromImage(img image.Image, tolerance float64) *RegionQuadtree {
+	r := img.Bounds()
+	bounds := &quadtree.Bounds{
+		X:      float64(r.Min.X),
+		Y:      float64(r.Min.Y),
+		Width:  float64(r.Dx()),
+		Height: float64(r.Dy()),
+	}
+	return build(bounds, r.Dx(), r.Dy(), 4, tolerance, func(col, row int, values []float64) {
+		c := color.NRGBA64Model.Convert(img.At(r.Min.X+col, r.Min.Y+row)).(color.NRGBA64)
+		values[0] = float64(c.R) / 0xffff
+		values[1] = float64(c.G) / 0xffff
+		values[2] = float64(c.B) / 0xffff
+		values[3] = float64(c.A) / 0xffff
+	})
+}
+
+// ToImage paints every leaf with its value on an image of the size of the grid the tree was built from,
+// so that ToImage(FromImage(img, 0)) has the pixels of img when img is an *image.NRGBA. Trees with four
+// channels are painted as colors, others as shades of gray of their first channel
+func (t *RegionQuadtree) ToImage() *image.NRGBA {
+	origin := t.nodes[0].bounds
+	dst := image.NewNRGBA(image.Rect(0, 0, t.cols, t.rows))
+	if t.cols == 0 || t.rows == 0 {
+		return dst
+	}
+	if origin.X == math.Trunc(origin.X) && origin.Y == math.Trunc(origin.Y) {
+		dst.Rect = dst.Rect.Add(image.Pt(int(origin.X), int(origin.Y)))
+	}
+	cellWidth := origin.Width / float64(t.cols)
+	cellHeight := origin.Height / float64(t.rows)
+	for _, leaf := range t.Leaves() {
+		c0 := int(math.Round((leaf.Bounds.X - origin.X) / cellWidth))
+		r0 := int(math.Round((leaf.Bounds.Y - origin.Y) / cellHeight))
+		c1 := int(math.Round((leaf.Bounds.X + leaf.Bounds.Width - origin.X) / cellWidth))
+		r1 := int(math.Round((leaf.Bounds.Y + leaf.Bounds.Height - origin.Y) / cellHeight))
+		c := t.color(leaf.Values)
+		for row := r0; row < r1; row += 1 {
+			for col := c0; col < c1; col += 1 {
+				dst.SetNRGBA(dst.Rect.Min.X+col, dst.Rect.Min.Y+row, c)
+			}
+		}
+	}
+	return dst
+}
+
+// color converts the values of a leaf to a color
+func (t *RegionQuadtree) color(values []float64) color.NRGBA {
+	if t.channels == 4 {
+		return color.NRGBA{channel(values[0]), channel(values[1]), channel(values[2]), channel(values[3])}
+	}
+	gray := channel(values[0])
+	return color.NRGBA{gray, gray, gray, 0xff}
+}
+
+// channel converts a value in [0, 1] to an 8 bits channel
+func channel(v float64) uint8 {
+	return uint8(math.Round(math.Min(math.Max(v, 0), 1) * 0xff))
+}
@@ -0,0 +1,45 @@
+package regiontree
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestImageRoundTrip(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(2, 3, 10, 11))
+	for y := 3; y < 11; y += 1 {
+		for x := 2; x < 10; x += 1 {
+			c := color.NRGBA{0xff, 0, 0, 0xff}
+			if x >= 6 {
+				c = color.NRGBA{uint8(x * 20), uint8(y * 10), 0x40, uint8(0x80 + y)}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	tree := FromImage(img, 0)
+	// the red half merges into two leaves, the other half keeps a leaf per pixel
+	if tree.Len() != 2+32 {
+		t.Errorf("expects 34 leaves, got %d", tree.Len())
+	}
+	out := tree.ToImage()
+	if out.Bounds() != img.Bounds() {
+		t.Fatalf("expects bounds %v, got %v", img.Bounds(), out.Bounds())
+	}
+	for y := 3; y < 11; y += 1 {
+		for x := 2; x < 10; x += 1 {
+			if out.NRGBAAt(x, y) != img.NRGBAAt(x, y) {
+				t.Errorf("expects %v at (%d, %d), got %v", img.NRGBAAt(x, y), x, y, out.NRGBAAt(x, y))
+			}
+		}
+	}
+
+	flat := FromImage(img, 1)
+	if flat.Len() != 1 {
+		t.Errorf("expects a single leaf, got %d", flat.Len())
+	}
+	if c := flat.ToImage().NRGBAAt(9, 10); c != flat.ToImage().NRGBAAt(2, 3) {
+		t.Errorf("expects a uniform image, got %v", c)
+	}
+}
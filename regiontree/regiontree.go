@@ -0,0 +1,318 @@
+// Package regiontree is a region quadtree: it divides a grid of samples, such as a height map or an
+// image, into cells of uniform value, merging the quadrants whose samples differ by no more than a
+// tolerance, so that flat areas are stored as a few large cells and detailed ones as many small cells
+package regiontree
+
+import (
+	"math"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// node of the tree, children are indexes into the nodes of the tree, 0 for none since the root is never a child
+type node struct {
+	bounds   quadtree.Bounds
+	values   []float64 // mean of the samples of a leaf, one per channel, nil for inner nodes
+	children [4]int32
+}
+
+// RegionQuadtree is a region quadtree of samples with one or more channels, its nodes live in a single slice
+type RegionQuadtree struct {
+	nodes      []node
+	channels   int
+	cols, rows int // size of the grid of samples the tree was built from
+}
+
+// Leaf is a cell of uniform value
+type Leaf struct {
+	Bounds quadtree.Bounds
+	Values []float64 // one value per channel
+}
+
+// sampler writes the channels of the sample at col and row into values
+type sampler func(col, row int, values []float64)
+
+// FromGrid builds a tree with one channel from grid, indexed by row then column, whose samples are
+// spread evenly over bounds. Quadrants whose samples differ by no more than tolerance are merged into
+// a leaf holding their mean
+func FromGrid(bounds *quadtree.Bounds, grid [][]float64, tolerance float64) *RegionQuadtree {
+	rows := len(grid)
+	cols := 0
+	if rows > 0 {
+		cols = len(grid[0])
+	}
+	return build(bounds, cols, rows, 1, tolerance, func(col, row int, values []float64) {
+		values[0] = grid[row][col]
+	})
+}
+
+// build builds a tree of cols by rows samples with the given number of channels
+func build(bounds *quadtree.Bounds, cols, rows, channels int, tolerance float64, sample sampler) *RegionQuadtree {
+	t := &RegionQuadtree{channels: channels, cols: cols, rows: rows}
+	t.nodes = append(t.nodes, node{bounds: *bounds})
+	if cols <= 0 || rows <= 0 {
+		return t
+	}
+	b := builder{
+		tree:       t,
+		sample:     sample,
+		tolerance:  tolerance,
+		cellWidth:  bounds.Width / float64(cols),
+		cellHeight: bounds.Height / float64(rows),
+		origin:     *bounds,
+	}
+	b.build(0, 0, 0, cols, rows)
+	return t
+}
+
+// builder holds the state of a build
+type builder struct {
+	tree                  *RegionQuadtree
+	sample                sampler
+	tolerance             float64
+	cellWidth, cellHeight float64
+	origin                quadtree.Bounds
+}
+
+// summary describes the samples of a subtree
+type summary struct {
+	min, max, sum []float64
+	count         int
+}
+
+// build fills the node at index with the samples between columns c0 and c1 and rows r0 and r1, excluded
+func (b *builder) build(index, c0, r0, c1, r1 int) summary {
+	t := b.tree
+	t.nodes[index].bounds = quadtree.Bounds{
+		X:      b.origin.X + float64(c0)*b.cellWidth,
+		Y:      b.origin.Y + float64(r0)*b.cellHeight,
+		Width:  float64(c1-c0) * b.cellWidth,
+		Height: float64(r1-r0) * b.cellHeight,
+	}
+	if c1-c0 == 1 && r1-r0 == 1 {
+		values := make([]float64, t.channels)
+		b.sample(c0, r0, values)
+		t.nodes[index].values = values
+		return summary{
+			min:   append([]float64(nil), values...),
+			max:   append([]float64(nil), values...),
+			sum:   append([]float64(nil), values...),
+			count: 1,
+		}
+	}
+
+	cm, rm := c1, r1
+	if c1-c0 > 1 {
+		cm = (c0 + c1) / 2
+	}
+	if r1-r0 > 1 {
+		rm = (r0 + r1) / 2
+	}
+	ranges := [4][4]int{{c0, r0, cm, rm}, {cm, r0, c1, rm}, {c0, rm, cm, r1}, {cm, rm, c1, r1}}
+	first := len(t.nodes)
+	total := summary{
+		min: fill(t.channels, math.Inf(1)),
+		max: fill(t.channels, math.Inf(-1)),
+		sum: make([]float64, t.channels),
+	}
+	leaves := true
+	for quadrant, r := range ranges {
+		if r[0] >= r[2] || r[1] >= r[3] {
+			continue
+		}
+		t.nodes = append(t.nodes, node{})
+		child := len(t.nodes) - 1
+		t.nodes[index].children[quadrant] = int32(child)
+		s := b.build(child, r[0], r[1], r[2], r[3])
+		leaves = leaves && t.nodes[child].values != nil
+		for ch := 0; ch < t.channels; ch += 1 {
+			total.min[ch] = math.Min(total.min[ch], s.min[ch])
+			total.max[ch] = math.Max(total.max[ch], s.max[ch])
+			total.sum[ch] += s.sum[ch]
+		}
+		total.count += s.count
+	}
+
+	if !leaves {
+		return total
+	}
+	for ch := 0; ch < t.channels; ch += 1 {
+		if total.max[ch]-total.min[ch] > b.tolerance {
+			return total
+		}
+	}
+	// the children are the last nodes of the tree, they are merged into a leaf
+	t.nodes = t.nodes[:first]
+	t.nodes[index].children = [4]int32{}
+	values := make([]float64, t.channels)
+	for ch := range values {
+		values[ch] = total.sum[ch] / float64(total.count)
+	}
+	t.nodes[index].values = values
+	return total
+}
+
+// fill returns a slice of n copies of v
+func fill(n int, v float64) []float64 {
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = v
+	}
+	return values
+}
+
+// Bounds returns the bounds covered by the tree
+func (t *RegionQuadtree) Bounds() quadtree.Bounds {
+	return t.nodes[0].bounds
+}
+
+// Channels returns the number of values of every sample
+func (t *RegionQuadtree) Channels() int {
+	return t.channels
+}
+
+// Len returns the number of leaves of the tree
+func (t *RegionQuadtree) Len() int {
+	count := 0
+	for i := range t.nodes {
+		if t.nodes[i].values != nil {
+			count += 1
+		}
+	}
+	return count
+}
+
+// Leaves returns the leaves of the tree, in depth first order
+func (t *RegionQuadtree) Leaves() []Leaf {
+	var leaves []Leaf
+	var visit func(index int)
+	visit = func(index int) {
+		n := &t.nodes[index]
+		if n.values != nil {
+			leaves = append(leaves, Leaf{Bounds: n.bounds, Values: n.values})
+			return
+		}
+		for _, c := range n.children {
+			if c != 0 {
+				visit(int(c))
+			}
+		}
+	}
+	visit(0)
+	return leaves
+}
+
+// leaf returns the index of the leaf containing (x, y), -1 if the point lies outside of the tree
+func (t *RegionQuadtree) leaf(x, y float64) int {
+	if !t.nodes[0].bounds.ContainsPoint(x, y) {
+		return -1
+	}
+	index := 0
+	for t.nodes[index].values == nil {
+		next := -1
+		for _, c := range t.nodes[index].children {
+			if c != 0 && t.nodes[c].bounds.ContainsPoint(x, y) {
+				next = int(c)
+				break
+			}
+		}
+		if next == -1 {
+			return -1
+		}
+		index = next
+	}
+	return index
+}
+
+// Value returns the values of the leaf containing (x, y), false if the point lies outside of the tree
+func (t *RegionQuadtree) Value(x, y float64) ([]float64, bool) {
+	index := t.leaf(x, y)
+	if index == -1 {
+		return nil, false
+	}
+	return t.nodes[index].values, true
+}
+
+// SampleHeight returns the first channel of the tree at (x, y), interpolated bilinearly between the
+// heights of the corners of the leaf containing the point, so that a terrain stored at various levels
+// of detail can be queried as a continuous surface. A corner takes the mean height of the leaves
+// sharing it, unless it lies in the middle of a side of a larger leaf, where it takes the height of
+// that side so that the surface does not tear where leaves of different sizes meet. Points outside of
+// the tree are clamped onto its bounds, an empty tree returns 0
+func (t *RegionQuadtree) SampleHeight(x, y float64) float64 {
+	b := t.nodes[0].bounds
+	x = math.Min(math.Max(x, b.X), b.X+b.Width)
+	y = math.Min(math.Max(y, b.Y), b.Y+b.Height)
+	index := t.leaf(x, y)
+	if index == -1 {
+		return 0
+	}
+	c0, r0, c1, r1 := t.cells(index)
+	cell := t.nodes[index].bounds
+	u, v := 0.0, 0.0
+	if cell.Width > 0 {
+		u = (x - cell.X) / cell.Width
+	}
+	if cell.Height > 0 {
+		v = (y - cell.Y) / cell.Height
+	}
+	top := lerp(t.cornerHeight(c0, r0), t.cornerHeight(c1, r0), u)
+	bottom := lerp(t.cornerHeight(c0, r1), t.cornerHeight(c1, r1), u)
+	return lerp(top, bottom, v)
+}
+
+// lerp interpolates linearly from a to b
+func lerp(a, b, t float64) float64 {
+	return a + (b-a)*t
+}
+
+// cells returns the range of samples covered by the node at index, columns c0 to c1 and rows r0 to r1 excluded
+func (t *RegionQuadtree) cells(index int) (c0, r0, c1, r1 int) {
+	origin := t.nodes[0].bounds
+	b := t.nodes[index].bounds
+	cellWidth := origin.Width / float64(t.cols)
+	cellHeight := origin.Height / float64(t.rows)
+	c0 = int(math.Round((b.X - origin.X) / cellWidth))
+	r0 = int(math.Round((b.Y - origin.Y) / cellHeight))
+	c1 = int(math.Round((b.X + b.Width - origin.X) / cellWidth))
+	r1 = int(math.Round((b.Y + b.Height - origin.Y) / cellHeight))
+	return c0, r0, c1, r1
+}
+
+// cellLeaf returns the index of the leaf holding the sample at col and row, -1 outside of the grid
+func (t *RegionQuadtree) cellLeaf(col, row int) int {
+	if col < 0 || row < 0 || col >= t.cols || row >= t.rows {
+		return -1
+	}
+	origin := t.nodes[0].bounds
+	return t.leaf(origin.X+(float64(col)+0.5)*origin.Width/float64(t.cols),
+		origin.Y+(float64(row)+0.5)*origin.Height/float64(t.rows))
+}
+
+// cornerHeight returns the height of the grid vertex between columns col-1 and col and rows row-1 and row
+func (t *RegionQuadtree) cornerHeight(col, row int) float64 {
+	sum, count := 0.0, 0
+	seen := [4]int{-1, -1, -1, -1}
+	for i, cell := range [4][2]int{{col - 1, row - 1}, {col, row - 1}, {col - 1, row}, {col, row}} {
+		leaf := t.cellLeaf(cell[0], cell[1])
+		if leaf == -1 || leaf == seen[0] || leaf == seen[1] || leaf == seen[2] {
+			continue
+		}
+		seen[i] = leaf
+		c0, r0, c1, r1 := t.cells(leaf)
+		switch {
+		case (col == c0 || col == c1) && (row == r0 || row == r1):
+			sum += t.nodes[leaf].values[0]
+			count += 1
+		case col == c0 || col == c1:
+			// the vertex lies in the middle of a vertical side of a larger leaf
+			return lerp(t.cornerHeight(col, r0), t.cornerHeight(col, r1), float64(row-r0)/float64(r1-r0))
+		default:
+			return lerp(t.cornerHeight(c0, row), t.cornerHeight(c1, row), float64(col-c0)/float64(c1-c0))
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
@@ -0,0 +1,112 @@
+package regiontree
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestFromGrid(t *testing.T) {
+	grid := [][]float64{
+		{0, 0, 10, 10},
+		{0, 0, 10, 10},
+		{0, 0, 10, 11},
+		{0, 0, 10, 10},
+	}
+	tree := FromGrid(&quadtree.Bounds{X: 0, Y: 0, Width: 4, Height: 4}, grid, 0)
+	// three quadrants are uniform, the bottom right one keeps its four samples
+	if tree.Len() != 7 {
+		t.Errorf("expects 7 leaves, got %d", tree.Len())
+	}
+	if values, ok := tree.Value(1, 1); !ok || values[0] != 0 {
+		t.Errorf("expects 0 at (1, 1), got %v", values)
+	}
+	if values, ok := tree.Value(3.5, 2.5); !ok || values[0] != 11 {
+		t.Errorf("expects 11 at (3.5, 2.5), got %v", values)
+	}
+	if _, ok := tree.Value(5, 1); ok {
+		t.Errorf("expects no value outside of the tree")
+	}
+
+	merged := FromGrid(&quadtree.Bounds{X: 0, Y: 0, Width: 4, Height: 4}, grid, 1)
+	if merged.Len() != 4 {
+		t.Errorf("expects 4 leaves within tolerance, got %d", merged.Len())
+	}
+	if values, _ := merged.Value(3.5, 3.5); values[0] != 10.25 {
+		t.Errorf("expects the mean 10.25, got %v", values[0])
+	}
+
+	flat := FromGrid(&quadtree.Bounds{X: 0, Y: 0, Width: 30, Height: 50}, [][]float64{
+		{2, 2, 2}, {2, 2, 2}, {2, 2, 2}, {2, 2, 2}, {2, 2, 2},
+	}, 0)
+	if flat.Len() != 1 || flat.Leaves()[0].Bounds != flat.Bounds() {
+		t.Errorf("expects a single leaf covering the tree, got %v", flat.Leaves())
+	}
+}
+
+func TestSampleHeight(t *testing.T) {
+	grid := make([][]float64, 4)
+	for row := range grid {
+		grid[row] = []float64{0, 1, 2, 3}
+	}
+	slope := FromGrid(&quadtree.Bounds{X: 0, Y: 0, Width: 4, Height: 4}, grid, 0)
+	// the corners between two columns hold the mean of both
+	for _, x := range []float64{1, 1.75, 2.5, 3} {
+		for _, y := range []float64{0, 1.2, 3.9} {
+			if h := slope.SampleHeight(x, y); math.Abs(h-(x-0.5)) > 1e-9 {
+				t.Errorf("expects %v at (%v, %v), got %v", x-0.5, x, y, h)
+			}
+		}
+	}
+	// the edges of the tree hold the value of the outer cells
+	if h := slope.SampleHeight(0, 2); h != 0 {
+		t.Errorf("expects 0 at the left edge, got %v", h)
+	}
+	if h := slope.SampleHeight(0.5, 2); math.Abs(h-0.25) > 1e-9 {
+		t.Errorf("expects 0.25 halfway to the first corner, got %v", h)
+	}
+	if h := slope.SampleHeight(10, 2); h != 3 {
+		t.Errorf("expects points outside of the tree to be clamped, got %v", h)
+	}
+
+	steps := FromGrid(&quadtree.Bounds{X: 0, Y: 0, Width: 4, Height: 4}, [][]float64{
+		{0, 0, 10, 10},
+		{0, 0, 10, 10},
+		{0, 0, 10, 10},
+		{0, 0, 10, 10},
+	}, 0)
+	// interpolated from 0 at the left edge to 10 at the right edge through the mean 5 of the shared side
+	for x, expected := range map[float64]float64{0: 0, 1: 2.5, 1.5: 3.75, 2: 5, 2.5: 6.25, 3: 7.5, 4: 10} {
+		if h := steps.SampleHeight(x, 2); math.Abs(h-expected) > 1e-9 {
+			t.Errorf("expects %v at x = %v, got %v", expected, x, h)
+		}
+	}
+
+	if h := FromGrid(&quadtree.Bounds{Width: 1, Height: 1}, nil, 0).SampleHeight(0.5, 0.5); h != 0 {
+		t.Errorf("expects 0 for an empty tree, got %v", h)
+	}
+}
+
+func TestSampleHeightContinuity(t *testing.T) {
+	grid := make([][]float64, 4)
+	for row := range grid {
+		grid[row] = make([]float64, 4)
+	}
+	grid[2][0] = 10
+	tree := FromGrid(&quadtree.Bounds{X: 0, Y: 0, Width: 4, Height: 4}, grid, 0)
+	// the large upper left leaf meets the small leaves of the lower left quadrant at y = 2
+	// and the large lower right leaf meets them at x = 2
+	const epsilon = 1e-9
+	for _, edge := range []float64{0.5, 0.9, 1, 1.5, 1.999, 2, 2.5, 3, 3.5} {
+		if a, b := tree.SampleHeight(edge, 2-epsilon), tree.SampleHeight(edge, 2+epsilon); math.Abs(a-b) > 1e-6 {
+			t.Errorf("expects the surface to be continuous across y = 2 at x = %v, got %v and %v", edge, a, b)
+		}
+		if a, b := tree.SampleHeight(2-epsilon, edge), tree.SampleHeight(2+epsilon, edge); math.Abs(a-b) > 1e-6 {
+			t.Errorf("expects the surface to be continuous across x = 2 at y = %v, got %v and %v", edge, a, b)
+		}
+	}
+	if h := tree.SampleHeight(0.5, 2.5); h <= 0 || h > 10 {
+		t.Errorf("expects the raised cell to rise the surface, got %v", h)
+	}
+}
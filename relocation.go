@@ -0,0 +1,192 @@
+package quadtree
+
+import (
+	"math"
+	"sort"
+)
+
+// RelocationHistogram counts the objects relocated by Updates by the number of levels they climbed
+// before descending into their new node. Many relocations climbing up to the root reveal objects
+// with bad bounds, or moving too fast for their nodes, which loose bounds or fat bounds would help
+type RelocationHistogram struct {
+	Counts []uint64 // Counts[n] is the number of relocations which climbed n levels
+	ToRoot uint64   // relocations which climbed up to the root, or escaped it
+}
+
+// Total returns the number of relocations counted
+func (h RelocationHistogram) Total() uint64 {
+	var total uint64
+	for _, count := range h.Counts {
+		total += count
+	}
+	return total
+}
+
+// relocations is the configuration and state of WithRelocationHistogram
+type relocations struct {
+	histogram RelocationHistogram
+	observe   func(levels int, toRoot bool)
+}
+
+// WithRelocationHistogram makes Updates count how many levels every relocated object climbs in the
+// tree, for RelocationHistogram. observe (if not nil) is called with every relocation too, e.g. to
+// feed a Prometheus histogram. Trees with StraddleDuplicate reinsert moved objects from the root and
+// do not count them
+func WithRelocationHistogram(observe func(levels int, toRoot bool)) Option {
+	return func(c *config) {
+		c.relocations = &relocations{observe: observe}
+	}
+}
+
+// observeRelocation counts an object of current node relocated from container
+func (qt *Quadtree) observeRelocation(container *Quadtree) {
+	r := qt.m_config.relocations
+	if r == nil {
+		return
+	}
+	levels := qt.Level - container.Level
+	toRoot := container.m_parent == nil
+	for len(r.histogram.Counts) <= levels {
+		r.histogram.Counts = append(r.histogram.Counts, 0)
+	}
+	r.histogram.Counts[levels] += 1
+	if toRoot {
+		r.histogram.ToRoot += 1
+	}
+	if r.observe != nil {
+		r.observe(levels, toRoot)
+	}
+}
+
+// RelocationHistogram returns the relocations counted WithRelocationHistogram since the tree was created
+func (qt *Quadtree) RelocationHistogram() RelocationHistogram {
+	if !qt.ready() || qt.m_config.relocations == nil {
+		return RelocationHistogram{}
+	}
+	h := qt.m_config.relocations.histogram
+	h.Counts = append([]uint64(nil), h.Counts...)
+	return h
+}
+
+// relocationQueue is the configuration and state of WithRelocationBudget
+type relocationQueue struct {
+	budget  int
+	entries []queuedRelocation
+	queued  map[PhysicalObject]bool
+}
+
+// queuedRelocation is an object which moved, waiting in the node it is still stored in
+type queuedRelocation struct {
+	node     *Quadtree
+	obj      PhysicalObject
+	distance float64 // how far the center of the object lies outside of the node
+}
+
+// WithRelocationBudget relocates at most budget objects per Update. When more objects moved out of
+// their nodes, as happens during mass migrations, the excess stays in its old nodes, where region
+// queries may miss it, and is relocated during the next Updates, the objects which moved farthest
+// from their nodes first. Trees with StraddleDuplicate relocate every object
+func WithRelocationBudget(budget int) Option {
+	return func(c *config) {
+		c.relocationQueue = &relocationQueue{budget: budget, queued: make(map[PhysicalObject]bool)}
+	}
+}
+
+// push queues an object of the node which moved
+func (q *relocationQueue) push(node *Quadtree, obj PhysicalObject) {
+	if !q.queued[obj] {
+		q.queued[obj] = true
+		q.entries = append(q.entries, queuedRelocation{node: node, obj: obj})
+	}
+}
+
+// drop removes the object from the queue
+func (q *relocationQueue) drop(obj PhysicalObject) {
+	if !q.queued[obj] {
+		return
+	}
+	delete(q.queued, obj)
+	for i, entry := range q.entries {
+		if entry.obj == obj {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// reset empties the queue
+func (q *relocationQueue) reset() {
+	q.entries = nil
+	q.queued = make(map[PhysicalObject]bool)
+}
+
+type byDistanceMoved []queuedRelocation
+
+func (s byDistanceMoved) Len() int           { return len(s) }
+func (s byDistanceMoved) Less(i, j int) bool { return s[i].distance > s[j].distance }
+func (s byDistanceMoved) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// flushRelocations relocates the queued objects which moved farthest, within the budget of an Update
+func (qt *Quadtree) flushRelocations() {
+	q := qt.m_config.relocationQueue
+	if q == nil || len(q.entries) == 0 {
+		return
+	}
+	for i := range q.entries {
+		entry := &q.entries[i]
+		x, y := center(entry.obj)
+		entry.distance = entry.node.distanceTo(x, y)
+	}
+	sort.Stable(byDistanceMoved(q.entries))
+	relocated := 0
+	kept := q.entries[:0]
+	for _, entry := range q.entries {
+		// objects still within their nodes may only descend into a child, which is cheap
+		if relocated >= q.budget && entry.distance > 0 {
+			kept = append(kept, entry)
+			continue
+		}
+		delete(q.queued, entry.obj)
+		node := entry.node
+		if !node.holds(entry.obj) {
+			if node = qt.root().FindObject(entry.obj); node == nil {
+				continue
+			}
+		}
+		if entry.distance > 0 {
+			relocated += 1
+			container := node
+			for !container.contains(entry.obj) && container.m_parent != nil {
+				container = container.m_parent
+			}
+			node.observeRelocation(container)
+		}
+		node.relocate(entry.obj)
+	}
+	q.entries = kept
+}
+
+// distanceTo returns how far the point lies outside of the bounds of the node, 0 within them
+func (qt *Quadtree) distanceTo(x, y float64) float64 {
+	dx := math.Max(0, math.Max(qt.X-x, x-(qt.X+qt.Width)))
+	dy := math.Max(0, math.Max(qt.Y-y, y-(qt.Y+qt.Height)))
+	return math.Hypot(dx, dy)
+}
+
+// holds tells whether the object, or an equal one, is stored in current node
+func (qt *Quadtree) holds(obj PhysicalObject) bool {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if qt.same(ele.Value.(PhysicalObject), obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeferredRelocations returns the number of objects waiting to be relocated WithRelocationBudget
+func (qt *Quadtree) DeferredRelocations() int {
+	if !qt.ready() || qt.m_config.relocationQueue == nil {
+		return 0
+	}
+	return len(qt.m_config.relocationQueue.entries)
+}
@@ -0,0 +1,89 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestRelocationHistogram(t *testing.T) {
+	var observed []int
+	roots := 0
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 2, quadtree.WithRelocationHistogram(func(levels int, toRoot bool) {
+		observed = append(observed, levels)
+		if toRoot {
+			roots += 1
+		}
+	}))
+	near := &steeredObject{TestPhysicalObject: TestPhysicalObject{0, 0, 1, 1}}
+	far := &steeredObject{TestPhysicalObject: TestPhysicalObject{2.5, 2.5, 1, 1}}
+	other := &steeredObject{TestPhysicalObject: TestPhysicalObject{7, 7, 1, 1}}
+	qt.Insert(near)
+	qt.Insert(far)
+	qt.Insert(other)
+
+	// near moves to a sibling leaf, far to the opposite quadrant of the root
+	near.moveTo(2.5, 0)
+	far.moveTo(5, 1)
+	qt.Update(0)
+
+	h := qt.RelocationHistogram()
+	if h.Total() != 2 || h.ToRoot != 1 || roots != 1 {
+		t.Errorf("expects 2 relocations, 1 to the root, got %+v and %d observed", h, roots)
+	}
+	if len(h.Counts) != 3 || h.Counts[1] != 1 || h.Counts[2] != 1 {
+		t.Errorf("expects a relocation climbing 1 level and another 2, got %v", h.Counts)
+	}
+	if len(observed) != 2 {
+		t.Errorf("expects every relocation to be observed, got %v", observed)
+	}
+
+	h.Counts[1] = 10
+	if qt.RelocationHistogram().Counts[1] != 1 {
+		t.Error("expects RelocationHistogram to return a copy")
+	}
+	if h := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 2).RelocationHistogram(); h.Total() != 0 {
+		t.Errorf("expects no relocations without the option, got %+v", h)
+	}
+}
+
+func TestRelocationBudget(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 16, 16}, 1, 2, quadtree.WithRelocationBudget(1))
+	short := &steeredObject{TestPhysicalObject: TestPhysicalObject{1, 1, 1, 1}}
+	long := &steeredObject{TestPhysicalObject: TestPhysicalObject{1, 9, 1, 1}}
+	staying := &steeredObject{TestPhysicalObject: TestPhysicalObject{9, 1, 1, 1}}
+	for _, obj := range []quadtree.PhysicalObject{short, long, staying} {
+		qt.Insert(obj)
+	}
+	homeOfShort := qt.FindObject(short)
+
+	short.moveTo(9, 9)
+	long.moveTo(14, 1)
+	staying.moveTo(10, 1)
+	qt.Update(0)
+	if qt.DeferredRelocations() != 1 {
+		t.Errorf("expects the excess relocation to be deferred, got %d", qt.DeferredRelocations())
+	}
+	if node := qt.FindObject(long); node == nil || !node.Contains(long) {
+		t.Errorf("expects the object which moved farthest to be relocated first, got %v", node)
+	}
+	if node := qt.FindObject(staying); node == nil || !node.Contains(staying) {
+		t.Errorf("expects objects moving within their node not to count, got %v", node)
+	}
+	if qt.FindObject(short) != homeOfShort {
+		t.Error("expects the deferred object to stay in its old node")
+	}
+
+	qt.Update(0)
+	if node := qt.FindObject(short); qt.DeferredRelocations() != 0 || node == nil || !node.Contains(short) {
+		t.Errorf("expects the deferred object to be relocated during the next Update, got %v", node)
+	}
+
+	short.moveTo(1, 1)
+	long.moveTo(1, 14)
+	qt.Update(0)
+	qt.Remove(short)
+	if qt.DeferredRelocations() != 0 {
+		t.Errorf("expects removed objects to leave the queue, got %d", qt.DeferredRelocations())
+	}
+}
@@ -0,0 +1,91 @@
+package quadtree
+
+import (
+	"fmt"
+)
+
+// RemoveReason tells why an object left the tree
+type RemoveReason int
+
+const (
+	// RemovedExplicitly is the reason of Remove, RemoveAll, the removals of Batch and DetachChunk
+	RemovedExplicitly RemoveReason = iota
+	// RemovedEvicted is the reason of the objects the Evictor of WithCapacity removes
+	RemovedEvicted
+	// RemovedInvalid is the reason of the objects dropped by the InvalidCoordinatesPolicy during Update
+	RemovedInvalid
+	// RemovedMarked is the reason of the objects flagged by MarkRemoved
+	RemovedMarked
+	// RemovedRebuilt is the reason of the objects left out of the objects of UpdateTree or BuildPacked
+	RemovedRebuilt
+	// RemovedExpired is the reason of the objects of InsertWithTTL and InsertWithLiveness which died
+	RemovedExpired
+)
+
+var removeReasonNames = [...]string{"explicit", "evicted", "invalid", "marked", "rebuilt", "expired"}
+
+func (r RemoveReason) String() string {
+	if r < 0 || int(r) >= len(removeReasonNames) {
+		return fmt.Sprintf("RemoveReason(%d)", int(r))
+	}
+	return removeReasonNames[r]
+}
+
+// WithOnRemoved calls fn with every object leaving the tree and the reason it left, so that systems
+// holding on to the object, or to a handle of it, can release it. Objects flagged by MarkRemoved are
+// reported once, when they are flagged
+func WithOnRemoved(fn func(obj PhysicalObject, reason RemoveReason)) Option {
+	return func(c *config) {
+		c.onRemoved = fn
+	}
+}
+
+// notifyRemoved reports an object which left the tree, objects removed while the evictor runs are evicted
+func (qt *Quadtree) notifyRemoved(obj PhysicalObject, reason RemoveReason) {
+	onRemoved := qt.m_config.onRemoved
+	if onRemoved == nil {
+		return
+	}
+	if reason == RemovedExplicitly && qt.root().m_evicting {
+		reason = RemovedEvicted
+	}
+	onRemoved(obj, reason)
+}
+
+// dropInvalid applies the invalid coordinates policy to an object Update took out of the tree
+func (qt *Quadtree) dropInvalid(obj PhysicalObject) {
+	qt.handleInvalid(obj)
+	if qt.m_config.invalidPolicy != InvalidCoordinatesClamp {
+		qt.notifyRemoved(obj, RemovedInvalid)
+	}
+}
+
+// storedObjects returns the objects of the subtree about to be rebuilt, nil if nobody is to be told
+// about the objects the rebuild leaves out
+func (qt *Quadtree) storedObjects() []PhysicalObject {
+	if qt.m_config.onRemoved == nil {
+		return nil
+	}
+	var stored []PhysicalObject
+	qt.walk(func(obj PhysicalObject) {
+		stored = append(stored, obj)
+	})
+	return stored
+}
+
+// notifyRebuilt reports the objects of stored the rebuilt subtree no longer holds
+func (qt *Quadtree) notifyRebuilt(stored []PhysicalObject) {
+	if len(stored) == 0 {
+		return
+	}
+	kept := make(map[PhysicalObject]bool)
+	qt.walk(func(obj PhysicalObject) {
+		kept[obj] = true
+	})
+	for _, obj := range stored {
+		if !kept[obj] {
+			kept[obj] = true
+			qt.notifyRemoved(obj, RemovedRebuilt)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package quadtree_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestOnRemoved(t *testing.T) {
+	reasons := make(map[quadtree.PhysicalObject][]quadtree.RemoveReason)
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 4,
+		quadtree.WithInvalidCoordinatesPolicy(quadtree.InvalidCoordinatesDrop, nil),
+		quadtree.WithOnRemoved(func(obj quadtree.PhysicalObject, reason quadtree.RemoveReason) {
+			reasons[obj] = append(reasons[obj], reason)
+		}))
+	removed := &TestPhysicalObject{1, 1, 1, 1}
+	marked := &TestPhysicalObject{2, 2, 1, 1}
+	invalid := &steeredObject{TestPhysicalObject: TestPhysicalObject{3, 3, 1, 1}}
+	rebuilt := &TestPhysicalObject{4, 4, 1, 1}
+	kept := &TestPhysicalObject{5, 5, 1, 1}
+	for _, obj := range []quadtree.PhysicalObject{removed, marked, invalid, rebuilt, kept} {
+		qt.Insert(obj)
+	}
+
+	qt.Remove(removed)
+	qt.MarkRemoved(marked)
+	qt.MarkRemoved(marked)
+	qt.CompactTombstones()
+	invalid.moveTo(math.NaN(), 3)
+	qt.Update(time.Millisecond)
+	qt.UpdateTree(listOf(kept))
+
+	expected := map[quadtree.PhysicalObject]quadtree.RemoveReason{
+		removed: quadtree.RemovedExplicitly,
+		marked:  quadtree.RemovedMarked,
+		invalid: quadtree.RemovedInvalid,
+		rebuilt: quadtree.RemovedRebuilt,
+	}
+	if len(reasons) != len(expected) {
+		t.Errorf("OnRemoved expects %v objects to be reported, got %v", len(expected), reasons)
+	}
+	for obj, reason := range expected {
+		if got := reasons[obj]; len(got) != 1 || got[0] != reason {
+			t.Errorf("OnRemoved expects %v to be reported once as %v, got %v", obj, reason, got)
+		}
+	}
+}
+
+func TestOnRemovedEvicted(t *testing.T) {
+	var reasons []quadtree.RemoveReason
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 4,
+		quadtree.WithCapacity(2, 0, quadtree.EvictFarthest(0, 0)),
+		quadtree.WithOnRemoved(func(obj quadtree.PhysicalObject, reason quadtree.RemoveReason) {
+			reasons = append(reasons, reason)
+		}))
+	for i := 0; i < 3; i += 1 {
+		qt.Insert(&TestPhysicalObject{float64(i), float64(i), 1, 1})
+	}
+	if len(reasons) != 1 || reasons[0] != quadtree.RemovedEvicted {
+		t.Errorf("OnRemoved expects the evicted object to be reported, got %v", reasons)
+	}
+}
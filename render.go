@@ -0,0 +1,234 @@
+package quadtree
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"strconv"
+)
+
+// Color is an ANSI terminal color used by RenderOptions
+type Color int
+
+const (
+	// ColorNone leaves the terminal color unchanged
+	ColorNone Color = iota
+	ColorRed
+	ColorGreen
+	ColorYellow
+	ColorBlue
+	ColorMagenta
+	ColorCyan
+	ColorWhite
+)
+
+// levelColors are the colors of the node levels with ColorByLevel
+var levelColors = [...]Color{ColorWhite, ColorCyan, ColorGreen, ColorYellow, ColorMagenta, ColorBlue, ColorRed}
+
+// RenderOption configures the colors of RenderASCII and RenderASCIIView
+type RenderOption func(*renderConfig)
+
+type renderConfig struct {
+	nodeColor   func(node *Quadtree) Color
+	objectColor func(obj PhysicalObject) Color
+	query       *Bounds // region of the query whose traversal is highlighted, nil for none
+	queryColor  Color
+}
+
+// ColorByLevel colors the boundaries of the nodes by level
+func ColorByLevel() RenderOption {
+	return func(rc *renderConfig) {
+		rc.nodeColor = func(node *Quadtree) Color {
+			return levelColors[node.Level%len(levelColors)]
+		}
+	}
+}
+
+// ColorByOccupancy colors the boundaries of the nodes by the number of objects they store:
+// blue when empty, green below MaxObjects, yellow at MaxObjects and red above
+func ColorByOccupancy() RenderOption {
+	return func(rc *renderConfig) {
+		rc.nodeColor = func(node *Quadtree) Color {
+			count, limit := node.m_Objects.Len(), node.maxObjects()
+			switch {
+			case count == 0:
+				return ColorBlue
+			case count < limit:
+				return ColorGreen
+			case count == limit:
+				return ColorYellow
+			}
+			return ColorRed
+		}
+	}
+}
+
+// ColorObjects colors every object with the color returned by color, such as the color of its team
+func ColorObjects(color func(obj PhysicalObject) Color) RenderOption {
+	return func(rc *renderConfig) {
+		rc.objectColor = color
+	}
+}
+
+// HighlightQuery draws the nodes a Retrieve of region visits, and the objects it returns, with color
+// and the '=', '!' and '@' characters instead of '-', '|' and '#'
+func HighlightQuery(region *Bounds, color Color) RenderOption {
+	return func(rc *renderConfig) {
+		rc.query = region
+		rc.queryColor = color
+	}
+}
+
+// RenderASCII draws the boundaries of every node ('+', '-' and '|') and the area covered by every
+// object ('#') of the tree onto a grid of cols by rows characters spanning the bounds of the tree.
+// Options color the drawing with ANSI escape sequences
+func (qt *Quadtree) RenderASCII(w io.Writer, cols, rows int, opts ...RenderOption) error {
+	if !qt.ready() {
+		return nil
+	}
+	return qt.RenderASCIIView(w, qt.Bounds, cols, rows, opts...)
+}
+
+// RenderASCIIView is RenderASCII with the grid spanning view instead of the bounds of the tree,
+// which allows panning and zooming
+func (qt *Quadtree) RenderASCIIView(w io.Writer, view *Bounds, cols, rows int, opts ...RenderOption) error {
+	if !qt.ready() {
+		return nil
+	}
+	if cols < 2 || rows < 2 || view.Width <= 0 || view.Height <= 0 {
+		return nil
+	}
+	rc := &renderConfig{}
+	for _, opt := range opts {
+		opt(rc)
+	}
+	grid := make([][]byte, rows)
+	colors := make([][]Color, rows)
+	for r := range grid {
+		grid[r] = make([]byte, cols)
+		colors[r] = make([]Color, cols)
+		for c := range grid[r] {
+			grid[r][c] = ' '
+		}
+	}
+
+	column := func(x float64) int {
+		return int(math.Round((x - view.X) / view.Width * float64(cols-1)))
+	}
+	row := func(y float64) int {
+		return int(math.Round((y - view.Y) / view.Height * float64(rows-1)))
+	}
+	highlighted := func(ch byte) bool {
+		return ch == '=' || ch == '!'
+	}
+	horizontal := func(ch byte) bool {
+		return ch == '-' || ch == '='
+	}
+	set := func(r, c int, ch byte, color Color) {
+		if r < 0 || r >= rows || c < 0 || c >= cols {
+			return
+		}
+		existing := grid[r][c]
+		if highlighted(existing) && !highlighted(ch) {
+			// the traversal of the query stays visible over the borders of other nodes
+			if horizontal(existing) != horizontal(ch) {
+				grid[r][c] = '+'
+			}
+			return
+		}
+		if color != ColorNone {
+			colors[r][c] = color
+		}
+		switch {
+		case existing == '+':
+		case existing != ' ' && horizontal(existing) != horizontal(ch):
+			grid[r][c] = '+'
+		default:
+			grid[r][c] = ch
+		}
+	}
+
+	var drawNode func(node *Quadtree, visited bool)
+	drawNode = func(node *Quadtree, visited bool) {
+		across, down, color := byte('-'), byte('|'), ColorNone
+		if rc.nodeColor != nil {
+			color = rc.nodeColor(node)
+		}
+		if visited {
+			across, down, color = '=', '!', rc.queryColor
+		}
+		left, right := clampInt(column(node.X), -1, cols), clampInt(column(node.X+node.Width), -1, cols)
+		y0, y1 := qt.m_config.screenSpan(view, node.Y, node.Height)
+		top, bottom := clampInt(row(y0), -1, rows), clampInt(row(y1), -1, rows)
+		for c := left; c <= right; c += 1 {
+			set(top, c, across, color)
+			set(bottom, c, across, color)
+		}
+		for r := top; r <= bottom; r += 1 {
+			set(r, left, down, color)
+			set(r, right, down, color)
+		}
+		flags := node.m_ActiveNodes
+		index := 0
+		for flags > 0 {
+			if flags&1 == 1 {
+				child := node.Nodes[index]
+				drawNode(child, visited && child.searchBounds().Intersects(rc.query))
+			}
+			flags >>= 1
+			index += 1
+		}
+	}
+	drawNode(qt, rc.query != nil)
+
+	qt.Walk(func(obj PhysicalObject) {
+		ch, color := byte('#'), ColorNone
+		if rc.objectColor != nil {
+			color = rc.objectColor(obj)
+		}
+		if rc.query != nil && rc.query.Intersects(boundsOf(obj)) {
+			ch, color = '@', rc.queryColor
+		}
+		y0, y1 := qt.m_config.screenSpan(view, obj.Y(), obj.Height())
+		top, bottom := clampInt(row(y0), 0, rows), clampInt(row(y1), -1, rows-1)
+		left, right := clampInt(column(obj.X()), 0, cols), clampInt(column(obj.X()+obj.Width()), -1, cols-1)
+		for r := top; r <= bottom; r += 1 {
+			for c := left; c <= right; c += 1 {
+				grid[r][c] = ch
+				colors[r][c] = color
+			}
+		}
+	})
+
+	bw := bufio.NewWriter(w)
+	for r, line := range grid {
+		current := ColorNone
+		for c, ch := range line {
+			if colors[r][c] != current {
+				if current != ColorNone {
+					bw.WriteString("\x1b[0m")
+				}
+				if colors[r][c] != ColorNone {
+					bw.WriteString("\x1b[3" + strconv.Itoa(int(colors[r][c])) + "m")
+				}
+				current = colors[r][c]
+			}
+			bw.WriteByte(ch)
+		}
+		if current != ColorNone {
+			bw.WriteString("\x1b[0m")
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func clampInt(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
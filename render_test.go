@@ -0,0 +1,81 @@
+package quadtree_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestRenderASCII(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 1)
+	qt.UpdateTree(listOf(
+		&TestPhysicalObject{1, 1, 1, 1},
+		&TestPhysicalObject{5, 5, 2, 1},
+	))
+
+	var buf bytes.Buffer
+	if err := qt.RenderASCII(&buf, 17, 9); err != nil {
+		t.Fatal(err)
+	}
+	expected := `+-------+-------+
+| ###   |       |
+| ###   |       |
+|       |       |
++-------+-------+
+|       | ##### |
+|       | ##### |
+|       |       |
++-------+-------+
+`
+	if buf.String() != expected {
+		t.Errorf("Quadtree expects to be rendered as:\n%s\nBut rendered as:\n%s", expected, buf.String())
+	}
+}
+
+func TestRenderHighlightQuery(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 1)
+	qt.UpdateTree(listOf(
+		&TestPhysicalObject{1, 1, 1, 1},
+		&TestPhysicalObject{5, 5, 2, 1},
+	))
+
+	var buf bytes.Buffer
+	if err := qt.RenderASCII(&buf, 17, 9, quadtree.HighlightQuery(&quadtree.Bounds{0, 0, 3, 3}, quadtree.ColorNone)); err != nil {
+		t.Fatal(err)
+	}
+	expected := `+=======+=======+
+! @@@   !       !
+! @@@   !       !
+!       !       !
++=======+-------+
+!       | ##### !
+!       | ##### !
+!       |       !
++=======+=======+
+`
+	if buf.String() != expected {
+		t.Errorf("Quadtree expects to be rendered as:\n%s\nBut rendered as:\n%s", expected, buf.String())
+	}
+}
+
+func TestRenderColors(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 1)
+	qt.UpdateTree(listOf(&TestPhysicalObject{1, 1, 1, 1}))
+
+	var buf bytes.Buffer
+	err := qt.RenderASCII(&buf, 9, 5, quadtree.ColorByLevel(), quadtree.ColorObjects(func(quadtree.PhysicalObject) quadtree.Color {
+		return quadtree.ColorRed
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "\x1b[37m+-------+\x1b[0m\n" +
+		"\x1b[37m|\x1b[0m\x1b[31m##\x1b[0m     \x1b[37m|\x1b[0m\n" +
+		"\x1b[37m|\x1b[0m       \x1b[37m|\x1b[0m\n" +
+		"\x1b[37m|\x1b[0m       \x1b[37m|\x1b[0m\n" +
+		"\x1b[37m+-------+\x1b[0m\n"
+	if buf.String() != expected {
+		t.Errorf("Quadtree expects to be rendered as:\n%q\nBut rendered as:\n%q", expected, buf.String())
+	}
+}
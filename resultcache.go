@@ -0,0 +1,84 @@
+package quadtree
+
+import (
+	"container/list"
+)
+
+// resultCache memoizes the last Retrieve and GetIntersection of a tree
+type resultCache struct {
+	moves     uint64 // objects moved since the tree was created, changes the results without a change stamp
+	retrieve  cachedResult
+	intersect cachedResult
+}
+
+// cachedResult is the result of a query, valid while the tree is unchanged
+type cachedResult struct {
+	node    *Quadtree
+	region  Bounds
+	objects []PhysicalObject
+	records []*IntersectionRecord
+	stamp   uint64 // change stamp of the root when the result was computed
+	epoch   uint64 // aggregate epoch of the tree when the result was computed
+	moves   uint64
+	valid   bool
+}
+
+// WithResultCache memoizes the last Retrieve and the last GetIntersection of the tree, which return the
+// memoized result when called again with the same arguments and nothing moved, was inserted or was removed
+// since. Paused games and replays querying an unchanged world skip the search. Only queries without
+// QueryOption, and GetIntersection without potential objects, are cached
+func WithResultCache() Option {
+	return func(c *config) {
+		c.results = &resultCache{}
+	}
+}
+
+// fresh tells whether the cached result is the one of a query of node and region
+func (qt *Quadtree) fresh(cached *cachedResult, region *Bounds) bool {
+	c := qt.m_config
+	return cached.valid && cached.node == qt && cached.region == *region &&
+		cached.stamp == qt.root().m_stamp && cached.epoch == c.aggregateEpoch && cached.moves == c.results.moves
+}
+
+// remember stores the result of a query of node and region
+func (qt *Quadtree) remember(cached *cachedResult, region *Bounds) {
+	c := qt.m_config
+	cached.node = qt
+	cached.region = *region
+	cached.stamp = qt.root().m_stamp
+	cached.epoch = c.aggregateEpoch
+	cached.moves = c.results.moves
+	cached.valid = true
+}
+
+// cachedRetrieve is Retrieve going through the result cache
+func (qt *Quadtree) cachedRetrieve(region *Bounds) IntersectedObjects {
+	cached := &qt.m_config.results.retrieve
+	if !qt.fresh(cached, region) {
+		cached.objects, _ = qt.RetrieveClusters(region)
+		qt.remember(cached, region)
+	}
+	if cached.objects == nil {
+		return nil
+	}
+	// callers own the returned slice
+	return append([]PhysicalObject(nil), cached.objects...)
+}
+
+// cachedIntersection is GetIntersection without potential objects going through the result cache
+func (qt *Quadtree) cachedIntersection(intersections *list.List) {
+	cached := &qt.m_config.results.intersect
+	if !qt.fresh(cached, qt.Bounds) {
+		found := &list.List{}
+		qt.intersections(found, nil, nil)
+		cached.records = cached.records[:0]
+		for ele := found.Front(); ele != nil; ele = ele.Next() {
+			cached.records = append(cached.records, ele.Value.(*IntersectionRecord))
+		}
+		qt.remember(cached, qt.Bounds)
+	}
+	for _, record := range cached.records {
+		copied := *record
+		intersections.PushBack(&copied)
+	}
+}
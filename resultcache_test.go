@@ -0,0 +1,82 @@
+package quadtree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// steeredObject only reports a move when its position was changed since the last Update
+type steeredObject struct {
+	TestPhysicalObject
+	moved bool
+}
+
+func (po *steeredObject) Update(delta time.Duration) bool {
+	moved := po.moved
+	po.moved = false
+	return moved
+}
+
+func (po *steeredObject) moveTo(x, y float64) {
+	po.x, po.y = x, y
+	po.moved = true
+}
+
+func TestResultCacheRetrieve(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 4, quadtree.WithResultCache())
+	obj := &steeredObject{TestPhysicalObject: TestPhysicalObject{1, 1, 1, 1}}
+	qt.Insert(obj)
+	region := &quadtree.Bounds{0, 0, 3, 3}
+
+	if got := qt.Retrieve(region); len(got) != 1 {
+		t.Fatalf("Retrieve expects one object, got %v", got)
+	}
+	// a move the tree was not told about keeps the memoized result
+	obj.moveTo(5, 5)
+	if got := qt.Retrieve(region); len(got) != 1 {
+		t.Errorf("Retrieve expects the memoized result, got %v", got)
+	}
+	qt.Update(time.Millisecond)
+	if got := qt.Retrieve(region); len(got) != 0 {
+		t.Errorf("Retrieve expects the result to be invalidated by the move, got %v", got)
+	}
+
+	other := &TestPhysicalObject{2, 2, 1, 1}
+	qt.Insert(other)
+	if got := qt.Retrieve(region); len(got) != 1 || got[0] != other {
+		t.Errorf("Retrieve expects the result to be invalidated by the insertion, got %v", got)
+	}
+	qt.MarkRemoved(other)
+	if got := qt.Retrieve(region); len(got) != 0 {
+		t.Errorf("Retrieve expects the result to be invalidated by the removal, got %v", got)
+	}
+	if got := qt.Retrieve(&quadtree.Bounds{4, 4, 4, 4}); len(got) != 1 || got[0] != obj {
+		t.Errorf("Retrieve expects another region to be searched, got %v", got)
+	}
+}
+
+func TestResultCacheGetIntersection(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 4, quadtree.WithResultCache())
+	one := &steeredObject{TestPhysicalObject: TestPhysicalObject{1, 1, 2, 2}}
+	another := &steeredObject{TestPhysicalObject: TestPhysicalObject{2, 2, 2, 2}}
+	qt.Insert(one)
+	qt.Insert(another)
+
+	if got := qt.GetIntersection(nil, nil); got.Len() != 1 {
+		t.Fatalf("GetIntersection expects one record, got %v", got.Len())
+	}
+	// records are copied, callers may modify them
+	record := qt.GetIntersection(nil, nil).Front().Value.(*quadtree.IntersectionRecord)
+	record.One = nil
+	if got := qt.GetIntersection(nil, nil); got.Len() != 1 || got.Front().Value.(*quadtree.IntersectionRecord).One == nil {
+		t.Errorf("GetIntersection expects the memoized records to be unchanged")
+	}
+
+	another.moveTo(6, 6)
+	qt.Update(time.Millisecond)
+	if got := qt.GetIntersection(nil, nil); got.Len() != 0 {
+		t.Errorf("GetIntersection expects the result to be invalidated by the move, got %v records", got.Len())
+	}
+}
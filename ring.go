@@ -0,0 +1,41 @@
+package quadtree
+
+import (
+	"math"
+)
+
+// pointBoundsMaxDistance returns the distance from (x, y) to the farthest point of b
+func pointBoundsMaxDistance(x, y float64, b *Bounds) float64 {
+	dx := math.Max(math.Abs(x-b.X), math.Abs(x-(b.X+b.Width)))
+	dy := math.Max(math.Abs(y-b.Y), math.Abs(y-(b.Y+b.Height)))
+	return math.Hypot(dx, dy)
+}
+
+// ringOverlaps checks whether b overlaps the ring centered at (cx, cy) between rInner and rOuter
+func ringOverlaps(cx, cy, rInner, rOuter float64, b *Bounds) bool {
+	return pointBoundsDistance(cx, cy, b) <= rOuter && pointBoundsMaxDistance(cx, cy, b) >= rInner
+}
+
+// QueryRing returns the objects overlapping the ring centered at (cx, cy) between the radiuses
+// rInner and rOuter, that is objects neither completely outside the outer circle nor completely
+// inside the inner circle. Nodes lying in either excluded area are not visited
+func (qt *Quadtree) QueryRing(cx, cy, rInner, rOuter float64, opts ...QueryOption) IntersectedObjects {
+	if !qt.ready() {
+		return nil
+	}
+	qc := newQueryConfig(opts)
+	objects := qc.resultBuffer(qt.m_config)
+	qt.visitWhere(qc.instrument(qt,
+		func(b *Bounds) bool {
+			return ringOverlaps(cx, cy, rInner, rOuter, b)
+		},
+		func(obj PhysicalObject) {
+			if ringOverlaps(cx, cy, rInner, rOuter, boundsOf(obj)) {
+				objects = append(objects, obj)
+			}
+		},
+	))
+	qc.lend(qt.m_config, objects)
+	qc.report(qt.m_config)
+	return objects
+}
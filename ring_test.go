@@ -0,0 +1,22 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/quadtreetest"
+)
+
+func TestQueryRing(t *testing.T) {
+	tooNear := &TestPhysicalObject{4.5, 4.5, 1, 1}
+	inRing := &TestPhysicalObject{7.5, 4.5, 1, 1}
+	crossingInner := &TestPhysicalObject{5, 1.5, 1, 2}
+	tooFar := &TestPhysicalObject{0, 0, 1, 1}
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 10, 10}, 1, 10)
+	qt.UpdateTree(listOf(tooNear, inRing, crossingInner, tooFar))
+
+	expected := quadtree.IntersectedObjects{inRing, crossingInner}
+	if result := qt.QueryRing(5, 5, 2, 4); !quadtreetest.SameObjects(result, expected) {
+		t.Errorf("expects objects:\n%s\nBut got:\n%s", quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(result))
+	}
+}
@@ -0,0 +1,161 @@
+package quadtree
+
+import (
+	"math/rand"
+)
+
+// liveCount returns the number of objects of the subtree not flagged by MarkRemoved, cached until the subtree changes
+func (qt *Quadtree) liveCount() int {
+	c := qt.m_config
+	cached := &qt.m_liveCount
+	if cached.valid && cached.stamp == qt.m_stamp && cached.epoch == c.aggregateEpoch {
+		return cached.value.(int)
+	}
+	count := 0
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if !qt.buried(ele.Value.(PhysicalObject)) {
+			count += 1
+		}
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			count += qt.Nodes[index].liveCount()
+		}
+		flags >>= 1
+		index += 1
+	}
+	*cached = cachedAggregate{value: count, stamp: qt.m_stamp, epoch: c.aggregateEpoch, valid: true}
+	return count
+}
+
+// nthObject returns the object at index n of the subtree, in depth first order
+func (qt *Quadtree) nthObject(n int) PhysicalObject {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if obj := ele.Value.(PhysicalObject); !qt.buried(obj) {
+			if n == 0 {
+				return obj
+			}
+			n -= 1
+		}
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			count := qt.Nodes[index].liveCount()
+			if n < count {
+				return qt.Nodes[index].nthObject(n)
+			}
+			n -= count
+		}
+		flags >>= 1
+		index += 1
+	}
+	return nil
+}
+
+// sampleBlock is a set of candidates of a sample: a whole subtree, or a single object
+type sampleBlock struct {
+	node  *Quadtree
+	obj   PhysicalObject
+	count int
+}
+
+// sampleBlocks appends the blocks of the objects of the subtree overlapping region
+func (qt *Quadtree) sampleBlocks(region *Bounds, blocks []sampleBlock) []sampleBlock {
+	// objects of the root may lie outside of its bounds, the root is never taken as a whole
+	if qt.m_parent != nil && covers(region, qt.searchBounds()) {
+		if count := qt.liveCount(); count > 0 {
+			blocks = append(blocks, sampleBlock{node: qt, count: count})
+		}
+		return blocks
+	}
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		obj := ele.Value.(PhysicalObject)
+		if !qt.buried(obj) && region.Intersects(boundsOf(obj)) {
+			blocks = append(blocks, sampleBlock{obj: obj, count: 1})
+		}
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 && qt.Nodes[index].searchBounds().Intersects(region) {
+			blocks = qt.Nodes[index].sampleBlocks(region, blocks)
+		}
+		flags >>= 1
+		index += 1
+	}
+	return blocks
+}
+
+// SampleInRegion returns up to n distinct objects drawn uniformly at random among the objects whose
+// bounds overlap region, in random order. Subtrees region fully covers are sampled through cached
+// object counts, without listing their objects. With StraddleDuplicate, objects stored in several
+// nodes are more likely to be drawn
+func (qt *Quadtree) SampleInRegion(b *Bounds, n int, rng *rand.Rand) []PhysicalObject {
+	if !qt.ready() || n <= 0 {
+		return nil
+	}
+	blocks := qt.sampleBlocks(b, nil)
+	total := 0
+	for _, block := range blocks {
+		total += block.count
+	}
+	if total == 0 {
+		return nil
+	}
+
+	var sample []PhysicalObject
+	picked := make(map[PhysicalObject]bool)
+	if n < total {
+		for attempts := 0; len(sample) < n && attempts < 4*n; attempts += 1 {
+			obj := pickFromBlocks(blocks, rng.Intn(total))
+			if !picked[obj] {
+				picked[obj] = true
+				sample = append(sample, obj)
+			}
+		}
+		if len(sample) == n {
+			return sample
+		}
+	}
+
+	// the sample covers most candidates, list them all
+	var all []PhysicalObject
+	for _, block := range blocks {
+		if block.node == nil {
+			all = append(all, block.obj)
+			continue
+		}
+		block.node.walk(func(obj PhysicalObject) {
+			all = append(all, obj)
+		})
+	}
+	rng.Shuffle(len(all), func(i, j int) { all[i], all[j] = all[j], all[i] })
+	for _, obj := range all {
+		if len(sample) == n {
+			break
+		}
+		if !picked[obj] {
+			picked[obj] = true
+			sample = append(sample, obj)
+		}
+	}
+	return sample
+}
+
+// pickFromBlocks returns the candidate at index i of the blocks
+func pickFromBlocks(blocks []sampleBlock, i int) PhysicalObject {
+	for _, block := range blocks {
+		if i < block.count {
+			if block.node == nil {
+				return block.obj
+			}
+			return block.node.nthObject(i)
+		}
+		i -= block.count
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package quadtree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestSampleInRegion(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 100, 100}, 2, 8)
+	inside := map[quadtree.PhysicalObject]bool{}
+	for i := 0; i < 400; i += 1 {
+		obj := &TestPhysicalObject{rng.Float64() * 99, rng.Float64() * 99, 1, 1}
+		qt.Insert(obj)
+		if obj.x <= 50 && obj.y <= 50 {
+			inside[obj] = true
+		}
+	}
+	region := &quadtree.Bounds{0, 0, 50, 50}
+
+	hits := map[quadtree.PhysicalObject]int{}
+	for round := 0; round < 2000; round += 1 {
+		sample := qt.SampleInRegion(region, 5, rng)
+		if len(sample) != 5 {
+			t.Fatalf("expects 5 objects, got %d", len(sample))
+		}
+		seen := map[quadtree.PhysicalObject]bool{}
+		for _, obj := range sample {
+			if !inside[obj] || seen[obj] {
+				t.Fatalf("expects distinct objects overlapping the region, got %v", obj)
+			}
+			seen[obj] = true
+			hits[obj] += 1
+		}
+	}
+	// every object is expected 2000 * 5 / len(inside) times
+	expected := 10000 / len(inside)
+	for obj := range inside {
+		if hits[obj] < expected/3 || hits[obj] > expected*3 {
+			t.Errorf("expects a uniform sample, %v was drawn %d times instead of about %d", obj, hits[obj], expected)
+		}
+	}
+
+	all := qt.SampleInRegion(region, 10000, rng)
+	if len(all) != len(inside) {
+		t.Errorf("expects every one of the %d objects when asking for more, got %d", len(inside), len(all))
+	}
+	if got := qt.SampleInRegion(&quadtree.Bounds{200, 200, 1, 1}, 3, rng); len(got) != 0 {
+		t.Errorf("expects no object outside of the tree, got %v", got)
+	}
+}
@@ -0,0 +1,89 @@
+package quadtree
+
+import (
+	"math"
+	"sort"
+)
+
+// Segment is a line segment from A to B
+type Segment struct {
+	A, B Point
+}
+
+// CastShadows returns the visibility polygon of a light at (lx, ly) reaching radius, as the segments
+// joining its vertices in increasing angle order. The objects of the tree within reach occlude the
+// light with the edges of their bounds, objects containing the light are ignored. The polygon is
+// clipped to the square of half side radius around the light
+func (qt *Quadtree) CastShadows(lx, ly float64, radius float64) []Segment {
+	if !qt.ready() || radius <= 0 {
+		return nil
+	}
+	light := Point{lx, ly}
+	reach := &Bounds{lx - radius, ly - radius, 2 * radius, 2 * radius}
+
+	edges := boundsEdges(reach)
+	qt.visitRegion(reach, func(obj PhysicalObject) {
+		b := boundsOf(obj)
+		if reach.Intersects(b) && !(b.X < lx && lx < b.X+b.Width && b.Y < ly && ly < b.Y+b.Height) {
+			edges = append(edges, boundsEdges(b)...)
+		}
+	})
+
+	// cast rays toward every edge end, and slightly beside it to see past corners
+	var angles []float64
+	for _, edge := range edges {
+		for _, p := range [2]Point{edge.A, edge.B} {
+			angle := math.Atan2(p.Y-ly, p.X-lx)
+			angles = append(angles, angle-1e-5, angle, angle+1e-5)
+		}
+	}
+	sort.Float64s(angles)
+
+	var vertices []Point
+	for _, angle := range angles {
+		if hit, ok := castRay(light, angle, edges); ok {
+			if n := len(vertices); n == 0 || vertices[n-1] != hit {
+				vertices = append(vertices, hit)
+			}
+		}
+	}
+	if len(vertices) < 2 {
+		return nil
+	}
+	segments := make([]Segment, len(vertices))
+	for i, v := range vertices {
+		segments[i] = Segment{v, vertices[(i+1)%len(vertices)]}
+	}
+	return segments
+}
+
+// boundsEdges returns the four edges of b
+func boundsEdges(b *Bounds) []Segment {
+	topLeft, topRight := Point{b.X, b.Y}, Point{b.X + b.Width, b.Y}
+	bottomLeft, bottomRight := Point{b.X, b.Y + b.Height}, Point{b.X + b.Width, b.Y + b.Height}
+	return []Segment{{topLeft, topRight}, {topRight, bottomRight}, {bottomRight, bottomLeft}, {bottomLeft, topLeft}}
+}
+
+// castRay returns the closest point where the ray from origin at angle meets an edge
+func castRay(origin Point, angle float64, edges []Segment) (Point, bool) {
+	dx, dy := math.Cos(angle), math.Sin(angle)
+	best := math.Inf(1)
+	for _, edge := range edges {
+		ex, ey := edge.B.X-edge.A.X, edge.B.Y-edge.A.Y
+		denominator := dx*ey - dy*ex
+		if denominator == 0 {
+			continue
+		}
+		// origin + t*(dx, dy) = A + u*(ex, ey)
+		ax, ay := edge.A.X-origin.X, edge.A.Y-origin.Y
+		t := (ax*ey - ay*ex) / denominator
+		u := (ax*dy - ay*dx) / denominator
+		if t >= 0 && u >= 0 && u <= 1 && t < best {
+			best = t
+		}
+	}
+	if math.IsInf(best, 1) {
+		return Point{}, false
+	}
+	return Point{origin.X + best*dx, origin.Y + best*dy}, true
+}
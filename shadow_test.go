@@ -0,0 +1,45 @@
+package quadtree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestCastShadows(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 100, 100}, 1, 10)
+	qt.Insert(&TestPhysicalObject{60, 45, 10, 10})
+	qt.Insert(&TestPhysicalObject{95, 95, 2, 2})
+
+	segments := qt.CastShadows(50, 50, 20)
+	if len(segments) == 0 {
+		t.Fatal("expects a visibility polygon")
+	}
+	for i, s := range segments {
+		if s.B != segments[(i+1)%len(segments)].A {
+			t.Errorf("expects a closed polygon, segment %d ends at %v", i, s.B)
+		}
+		for _, p := range []quadtree.Point{s.A, s.B} {
+			if p.X < 30-1e-9 || p.X > 70+1e-9 || p.Y < 30-1e-9 || p.Y > 70+1e-9 {
+				t.Errorf("expects vertices within reach of the light, got %v", p)
+			}
+		}
+	}
+
+	// the box casts a shadow to its right, the polygon follows its face at x = 60 instead
+	face := 0
+	for _, s := range segments {
+		for _, p := range []quadtree.Point{s.A, s.B} {
+			if p.X > 60+1e-3 && math.Abs(p.Y-50) < 4 {
+				t.Errorf("expects no light in the shadow of the box, got %v", p)
+			}
+			if math.Abs(p.X-60) < 1e-3 && math.Abs(p.Y-50) <= 5+1e-3 {
+				face += 1
+			}
+		}
+	}
+	if face == 0 {
+		t.Errorf("expects the polygon to follow the face of the box, got %v", segments)
+	}
+}
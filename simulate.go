@@ -0,0 +1,60 @@
+package quadtree
+
+import (
+	"time"
+)
+
+// simulation records the objects which moved during Simulate, until Commit relocates them
+type simulation struct {
+	delta time.Duration
+	moved map[PhysicalObject]bool
+	order []PhysicalObject // moved objects, in the order they were updated
+}
+
+// Simulate runs the first half of Update: it calls the Update of every object but leaves the tree
+// untouched, and returns the objects which moved since the pending simulation started. Game code may
+// then veto or adjust the movements, e.g. to resolve collisions, by changing the bounds of the objects
+// before Commit relocates them. Queries between Simulate and Commit see the objects at their new
+// bounds, in their old nodes. Simulating again before Commit adds to the pending simulation, Update
+// discards it
+func (qt *Quadtree) Simulate(delta time.Duration) []PhysicalObject {
+	if !qt.ready() {
+		return nil
+	}
+	root := qt.root()
+	s := root.m_config.simulation
+	if s == nil {
+		s = &simulation{moved: make(map[PhysicalObject]bool)}
+		root.m_config.simulation = s
+	}
+	s.delta += delta
+	root.tick(delta)
+	root.visitStored(dedupe(func(obj PhysicalObject) {
+		if !root.buried(obj) && root.updateObject(obj, delta) && !s.moved[obj] {
+			s.moved[obj] = true
+			s.order = append(s.order, obj)
+		}
+	}))
+	return append([]PhysicalObject(nil), s.order...)
+}
+
+// Commit runs the second half of Update after Simulate: objects which moved are relocated, along with
+// the objects game code pushed between Simulate and Commit, whose bounds no longer match their node.
+// Empty nodes are pruned, and everything following the changes of the tree (watchers, pairs, logs,
+// etc.) is told, as Update would. Commit without a pending simulation does nothing
+func (qt *Quadtree) Commit() {
+	if !qt.ready() || qt.m_config.simulation == nil {
+		return
+	}
+	c := qt.m_config
+	s := c.simulation
+	c.simulation = nil
+	c.committing = s
+	qt.root().runUpdate(s.delta)
+	c.committing = nil
+}
+
+// misplaced tells whether obj, stored in current node, no longer belongs to it
+func (qt *Quadtree) misplaced(obj PhysicalObject) bool {
+	return !qt.contains(obj) || (qt.m_ActiveNodes != 0 && qt.placement(obj) != 0)
+}
@@ -0,0 +1,63 @@
+package quadtree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestSimulateCommit(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 3)
+	runner := &steeredObject{TestPhysicalObject: TestPhysicalObject{0, 0, 1, 1}}
+	blocked := &steeredObject{TestPhysicalObject: TestPhysicalObject{0, 7, 1, 1}}
+	idle := &steeredObject{TestPhysicalObject: TestPhysicalObject{7, 7, 1, 1}}
+	qt.Insert(runner)
+	qt.Insert(blocked)
+	qt.Insert(idle)
+
+	runner.moveTo(6, 0)
+	blocked.moveTo(7, 7)
+	moved := qt.Simulate(time.Second)
+	if len(moved) != 2 {
+		t.Fatalf("expects the 2 moving objects, got %v", moved)
+	}
+	if node := qt.FindObject(runner); node == nil || node.Contains(runner) {
+		t.Errorf("expects Simulate to leave runner in its old node, got %v", node)
+	}
+
+	// the movement of blocked is vetoed before the relocations are applied
+	blocked.x, blocked.y = 0, 7
+	qt.Commit()
+	if node := qt.FindObject(runner); node == nil || !node.Contains(runner) {
+		t.Errorf("expects runner to be relocated, got %v", node)
+	}
+	if node := qt.FindObject(blocked); node == nil || !node.Contains(blocked) || node.X != 0 {
+		t.Errorf("expects blocked to stay in its node, got %v", node)
+	}
+
+	if moved := qt.Simulate(time.Second); len(moved) != 0 {
+		t.Errorf("expects no pending move after Commit, got %v", moved)
+	}
+	runner.moveTo(1, 1)
+	qt.Update(time.Second)
+	qt.Commit()
+	if node := qt.FindObject(runner); node == nil || !node.Contains(runner) {
+		t.Errorf("expects Update to relocate objects after a discarded simulation, got %v", node)
+	}
+}
+
+func TestCommitAdjusted(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 3)
+	pushed := &steeredObject{TestPhysicalObject: TestPhysicalObject{7, 7, 1, 1}}
+	qt.Insert(pushed)
+	qt.Insert(&steeredObject{TestPhysicalObject: TestPhysicalObject{0, 0, 1, 1}})
+
+	qt.Simulate(time.Second)
+	// a collision response pushes a stationary object between the two phases
+	pushed.x, pushed.y = 0.2, 0.2
+	qt.Commit()
+	if node := qt.FindObject(pushed); node == nil || !node.Contains(pushed) {
+		t.Errorf("expects the pushed object to be relocated by Commit, got %v", node)
+	}
+}
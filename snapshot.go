@@ -0,0 +1,125 @@
+//go:build !tinygo
+// +build !tinygo
+
+package quadtree
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// SnapshotVersion is the version of the snapshot format written by Save
+const SnapshotVersion = 1
+
+// ErrSnapshotVersion is returned by Load for snapshots written by a newer release, or older ones
+// without a registered migration
+var ErrSnapshotVersion = errors.New("quadtree: unsupported snapshot version")
+
+// snapshot is the JSON document written by Save
+type snapshot struct {
+	Version    int               `json:"version"`
+	Bounds     Bounds            `json:"bounds"`
+	MaxObjects int               `json:"maxObjects"`
+	MaxLevels  int               `json:"maxLevels"`
+	Objects    []json.RawMessage `json:"objects"`
+}
+
+// Migration upgrades the fields of a snapshot document from one version to the next
+type Migration func(fields map[string]json.RawMessage) error
+
+var (
+	migrationsMu sync.Mutex
+	migrations   = make(map[int]Migration)
+)
+
+// RegisterMigration registers the migration upgrading snapshots of version from to version from+1,
+// Load chains migrations up to SnapshotVersion. Registering a version twice replaces its migration
+func RegisterMigration(from int, migrate Migration) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	migrations[from] = migrate
+}
+
+// Save writes a JSON snapshot of the tree to w: the format version, the bounds and split limits of
+// the root, and the objects as returned by encode. Options of the tree are not saved
+func (qt *Quadtree) Save(w io.Writer, encode func(PhysicalObject) (json.RawMessage, error)) error {
+	if !qt.ready() {
+		return ErrNilQuadtree
+	}
+	root := qt.root()
+	doc := snapshot{
+		Version:    SnapshotVersion,
+		Bounds:     *root.Bounds,
+		MaxObjects: root.MaxObjects,
+		MaxLevels:  root.MaxLevels,
+		Objects:    []json.RawMessage{},
+	}
+	var err error
+	root.Walk(func(obj PhysicalObject) {
+		if err != nil {
+			return
+		}
+		var data json.RawMessage
+		if data, err = encode(obj); err == nil {
+			doc.Objects = append(doc.Objects, data)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(&doc)
+}
+
+// Load reads a snapshot written by Save, migrating it from older versions, and returns a new tree
+// configured by opts holding the objects decoded by decode
+func Load(r io.Reader, decode func(json.RawMessage) (PhysicalObject, error), opts ...Option) (*Quadtree, error) {
+	var fields map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&fields); err != nil {
+		return nil, err
+	}
+	version := 0
+	if raw, ok := fields["version"]; ok {
+		if err := json.Unmarshal(raw, &version); err != nil {
+			return nil, err
+		}
+	}
+	for version < SnapshotVersion {
+		migrationsMu.Lock()
+		migrate := migrations[version]
+		migrationsMu.Unlock()
+		if migrate == nil {
+			return nil, ErrSnapshotVersion
+		}
+		if err := migrate(fields); err != nil {
+			return nil, fmt.Errorf("quadtree: migrating snapshot from version %d: %v", version, err)
+		}
+		version += 1
+		fields["version"] = json.RawMessage(fmt.Sprint(version))
+	}
+	if version > SnapshotVersion {
+		return nil, ErrSnapshotVersion
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	var doc snapshot
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	qt := NewQuadtree(&doc.Bounds, doc.MaxObjects, doc.MaxLevels, opts...)
+	for _, raw := range doc.Objects {
+		obj, err := decode(raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := qt.Insert(obj); err != nil {
+			return nil, err
+		}
+	}
+	return qt, nil
+}
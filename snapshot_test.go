@@ -0,0 +1,73 @@
+//go:build !tinygo
+// +build !tinygo
+
+package quadtree_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func encodeJSON(obj quadtree.PhysicalObject) (json.RawMessage, error) {
+	return json.Marshal([]float64{obj.X(), obj.Y(), obj.Width(), obj.Height()})
+}
+
+func decodeJSON(data json.RawMessage) (quadtree.PhysicalObject, error) {
+	var v []float64
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, err
+	}
+	return &TestPhysicalObject{v[0], v[1], v[2], v[3]}, nil
+}
+
+func TestSaveLoad(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	qt.Insert(&TestPhysicalObject{1, 1, 1, 1})
+	qt.Insert(&TestPhysicalObject{6, 6, 1, 1})
+	qt.Insert(&TestPhysicalObject{3, 3, 2, 2})
+
+	var buf bytes.Buffer
+	if err := qt.Save(&buf, encodeJSON); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"version":1`) {
+		t.Errorf("expects the format version in the snapshot, got %s", buf.String())
+	}
+	loaded, err := quadtree.Load(&buf, decodeJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.String() != qt.String() {
+		t.Errorf("expects the loaded tree\n%s\nto match\n%s", loaded.String(), qt.String())
+	}
+}
+
+func TestLoadMigration(t *testing.T) {
+	// version 0 snapshots had no version and called objects "items"
+	old := `{"bounds":{"X":0,"Y":0,"Width":8,"Height":8},"maxObjects":1,"maxLevels":10,"items":[[1,1,1,1],[6,6,1,1]]}`
+	if _, err := quadtree.Load(strings.NewReader(old), decodeJSON); err != quadtree.ErrSnapshotVersion {
+		t.Errorf("expects ErrSnapshotVersion without a migration, got %v", err)
+	}
+
+	quadtree.RegisterMigration(0, func(fields map[string]json.RawMessage) error {
+		fields["objects"] = fields["items"]
+		delete(fields, "items")
+		return nil
+	})
+	defer quadtree.RegisterMigration(0, nil)
+	loaded, err := quadtree.Load(strings.NewReader(old), decodeJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if objects, _ := loaded.Size(); objects != 2 {
+		t.Errorf("expects 2 objects in the migrated tree, got %d", objects)
+	}
+
+	if _, err := quadtree.Load(strings.NewReader(`{"version":99}`), decodeJSON); err != quadtree.ErrSnapshotVersion {
+		t.Errorf("expects ErrSnapshotVersion for a newer snapshot, got %v", err)
+	}
+}
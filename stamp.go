@@ -0,0 +1,27 @@
+package quadtree
+
+// ChangeStamp returns the change stamp of the subtree, the stamps increase every time objects are
+// inserted into, removed from or relocated within the tree. A subtree whose stamp did not change
+// since it was last seen holds the same objects in the same nodes
+func (qt *Quadtree) ChangeStamp() uint64 {
+	if !qt.ready() {
+		return 0
+	}
+	return qt.m_stamp
+}
+
+// touch records a change of the objects of current node, stamping the node and its ancestors.
+// delta is the change of the number of objects stored in the node
+func (qt *Quadtree) touch(delta int) {
+	root := qt.root()
+	root.m_config.expireBorrowed()
+	root.m_objectCount += delta
+	root.m_clock += 1
+	qt.m_version = root.m_clock
+	if delta > 0 {
+		qt.m_reserved = false
+	}
+	for node := qt; node != nil; node = node.m_parent {
+		node.m_stamp = root.m_clock
+	}
+}
@@ -0,0 +1,35 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestChangeStamp(t *testing.T) {
+	mover := &steeredObject{TestPhysicalObject: TestPhysicalObject{1, 1, 1, 1}}
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	qt.UpdateTree(listOf(mover, &steeredObject{TestPhysicalObject: TestPhysicalObject{6, 6, 1, 1}}))
+
+	left, right := qt.Nodes[0], qt.Nodes[3]
+	rootStamp, leftStamp, rightStamp := qt.ChangeStamp(), left.ChangeStamp(), right.ChangeStamp()
+	if rootStamp == 0 || rootStamp < leftStamp || rootStamp < rightStamp {
+		t.Fatalf("root expects the latest stamp, got root %d, children %d and %d", rootStamp, leftStamp, rightStamp)
+	}
+
+	qt.Update(0)
+	if qt.ChangeStamp() != rootStamp || left.ChangeStamp() != leftStamp || right.ChangeStamp() != rightStamp {
+		t.Errorf("stamps expect to stay unchanged when no object relocates")
+	}
+
+	qt.Insert(&TestPhysicalObject{6.5, 6.5, 1, 1})
+	if left.ChangeStamp() != leftStamp || right.ChangeStamp() <= rightStamp || qt.ChangeStamp() != right.ChangeStamp() {
+		t.Errorf("insert expects to bump the stamps of the inserted subtree only")
+	}
+
+	rootStamp = qt.ChangeStamp()
+	qt.Remove(mover)
+	if left.ChangeStamp() <= leftStamp || qt.ChangeStamp() <= rootStamp {
+		t.Errorf("remove expects to bump the stamps of the subtree")
+	}
+}
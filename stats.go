@@ -0,0 +1,109 @@
+package quadtree
+
+// TreeStats describes the shape of a tree
+type TreeStats struct {
+	Objects         int     // stored objects, copies included
+	Nodes           int     // nodes, current node included
+	Leaves          int     // nodes without children
+	Depth           int     // number of levels below current node
+	MaxLeafObjects  int     // objects of the fullest leaf
+	MeanLeafObjects float64 // objects per leaf
+}
+
+// Stats returns the statistics of the subtree
+func (qt *Quadtree) Stats() TreeStats {
+	var stats TreeStats
+	if !qt.ready() {
+		return stats
+	}
+	leafObjects := qt.collectStats(qt.Level, &stats)
+	stats.MeanLeafObjects = float64(leafObjects) / float64(stats.Leaves)
+	return stats
+}
+
+// collectStats adds the subtree to stats, and returns the number of objects stored in its leaves
+func (qt *Quadtree) collectStats(top int, stats *TreeStats) int {
+	count := qt.m_Objects.Len()
+	stats.Objects += count
+	stats.Nodes += 1
+	if depth := qt.Level - top; depth > stats.Depth {
+		stats.Depth = depth
+	}
+	if qt.m_ActiveNodes == 0 {
+		stats.Leaves += 1
+		if count > stats.MaxLeafObjects {
+			stats.MaxLeafObjects = count
+		}
+		return count
+	}
+	leafObjects := 0
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			leafObjects += qt.Nodes[index].collectStats(top, stats)
+		}
+		flags >>= 1
+		index += 1
+	}
+	return leafObjects
+}
+
+// StatsSample is the statistics of a tree after one of its Updates
+type StatsSample struct {
+	Update int // number of Updates of the tree when the sample was taken
+	TreeStats
+}
+
+// statsHistory is a ring buffer of the statistics of a tree
+type statsHistory struct {
+	every   int
+	updates int
+	samples []StatsSample
+	next    int // index of the oldest sample once the buffer is full
+}
+
+// WithStatsHistory samples the Stats of the tree every given number of Updates, and keeps the last
+// capacity samples for StatsHistory, to follow trends of the depth and the occupancy of the tree.
+// A capacity below 1 keeps no samples
+func WithStatsHistory(every, capacity int) Option {
+	return func(c *config) {
+		if every < 1 {
+			every = 1
+		}
+		if capacity < 0 {
+			capacity = 0
+		}
+		c.statsHistory = &statsHistory{every: every, samples: make([]StatsSample, 0, capacity)}
+	}
+}
+
+// sampleStats counts an Update of the tree, and samples its statistics when due
+func (qt *Quadtree) sampleStats() {
+	h := qt.m_config.statsHistory
+	if h == nil || cap(h.samples) == 0 {
+		return
+	}
+	h.updates += 1
+	if h.updates%h.every != 0 {
+		return
+	}
+	sample := StatsSample{Update: h.updates, TreeStats: qt.root().Stats()}
+	if len(h.samples) < cap(h.samples) {
+		h.samples = append(h.samples, sample)
+		return
+	}
+	h.samples[h.next] = sample
+	h.next = (h.next + 1) % len(h.samples)
+}
+
+// StatsHistory returns the samples taken WithStatsHistory, oldest first
+func (qt *Quadtree) StatsHistory() []StatsSample {
+	if !qt.ready() || qt.m_config.statsHistory == nil {
+		return nil
+	}
+	h := qt.m_config.statsHistory
+	samples := make([]StatsSample, 0, len(h.samples))
+	samples = append(samples, h.samples[h.next:]...)
+	return append(samples, h.samples[:h.next]...)
+}
@@ -0,0 +1,55 @@
+package quadtree_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestStats(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 2)
+	qt.UpdateTree(listOf(
+		&TestPhysicalObject{1, 1, 0.5, 0.5},
+		&TestPhysicalObject{2.5, 2.5, 0.5, 0.5},
+		&TestPhysicalObject{5, 5, 1, 1},
+	))
+	stats := qt.Stats()
+	expected := quadtree.TreeStats{
+		Objects:         3,
+		Nodes:           5,
+		Leaves:          3,
+		Depth:           2,
+		MaxLeafObjects:  1,
+		MeanLeafObjects: 1,
+	}
+	if stats != expected {
+		t.Errorf("Stats expects %+v, got %+v", expected, stats)
+	}
+}
+
+func TestStatsHistory(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 4, quadtree.WithStatsHistory(2, 3))
+	for i := 0; i < 10; i += 1 {
+		qt.Insert(&TestPhysicalObject{float64(i % 8), 1, 0.5, 0.5})
+		qt.Update(time.Millisecond)
+	}
+	history := qt.StatsHistory()
+	if len(history) != 3 {
+		t.Fatalf("StatsHistory expects the last 3 samples, got %v", len(history))
+	}
+	for i, sample := range history {
+		if update := 6 + 2*i; sample.Update != update || sample.Objects != update {
+			t.Errorf("Sample %d expects to be taken after Update %d with as many objects, got %+v", i, update, sample)
+		}
+	}
+}
+
+func TestStatsHistoryNegativeCapacity(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 4, quadtree.WithStatsHistory(1, -1))
+	qt.Insert(&TestPhysicalObject{1, 1, 1, 1})
+	qt.Update(time.Millisecond)
+	if history := qt.StatsHistory(); len(history) != 0 {
+		t.Errorf("StatsHistory expects no samples for a negative capacity, got %v", history)
+	}
+}
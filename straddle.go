@@ -0,0 +1,201 @@
+package quadtree
+
+import (
+	"container/list"
+	"math"
+)
+
+// StraddlePolicy decides where objects spanning the split point of a node are stored
+type StraddlePolicy int
+
+const (
+	// StraddleKeepAtParent keeps straddling objects in the node itself
+	StraddleKeepAtParent StraddlePolicy = iota
+	// StraddleDuplicate stores a straddling object in every child quadrant it overlaps,
+	// query results are deduplicated
+	StraddleDuplicate
+	// StraddleLoose places objects by their center into child quadrants whose loose bounds,
+	// the quadrant expanded by half its size on every side, contain them
+	StraddleLoose
+)
+
+// WithStraddlePolicy sets where objects spanning the split point of a node are stored
+func WithStraddlePolicy(policy StraddlePolicy) Option {
+	return func(c *config) {
+		c.straddlePolicy = policy
+	}
+}
+
+// placement returns the bitmask of child quadrants the object is stored in, 0 if it stays in current node
+func (qt *Quadtree) placement(obj PhysicalObject) byte {
+	if qt.m_config.straddlePolicy == StraddleLoose {
+		if index := qt.looseQuadrantIndex(obj); index != -1 {
+			return 1 << uint(index)
+		}
+		return 0
+	}
+	if index := qt.quadrantIndex(obj); index != -1 {
+		return 1 << uint(index)
+	}
+	if c := qt.m_config; c.straddlePolicy == StraddleDuplicate {
+		// copies do not fit in the nodes holding them, reaches needs the largest of them
+		c.straddleWidth = math.Max(c.straddleWidth, obj.Width())
+		c.straddleHeight = math.Max(c.straddleHeight, obj.Height())
+		return qt.overlappedQuadrants(obj)
+	}
+	return 0
+}
+
+// overlappedQuadrants returns the bitmask of child quadrants the object overlaps
+func (qt *Quadtree) overlappedQuadrants(obj PhysicalObject) byte {
+	var mask byte
+	objBounds := boundsOf(obj)
+	for index := 0; index < 4; index += 1 {
+		b := qt.quadrantBounds(index)
+		if objBounds.X < b.X+b.Width && b.X < objBounds.X+objBounds.Width &&
+			objBounds.Y < b.Y+b.Height && b.Y < objBounds.Y+objBounds.Height {
+			mask |= 1 << uint(index)
+		}
+	}
+	return mask
+}
+
+// looseQuadrantIndex returns the index of the child quadrant containing the center of the object
+// if the object fits within the loose bounds of that quadrant, -1 otherwise
+func (qt *Quadtree) looseQuadrantIndex(obj PhysicalObject) int {
+	cx := obj.X() + obj.Width()/2
+	cy := obj.Y() + obj.Height()/2
+	if cx < qt.X || cx > qt.X+qt.Width || cy < qt.Y || cy > qt.Y+qt.Height {
+		return -1
+	}
+
+	splitX, splitY := qt.SplitPoint()
+	index := 0
+	if cx >= splitX {
+		index |= 1
+	}
+	if cy >= splitY {
+		index |= 2
+	}
+	if !looseBounds(qt.quadrantBounds(index)).Contains(obj) {
+		return -1
+	}
+	return index
+}
+
+// looseBounds expands b by half its size on every side
+func looseBounds(b *Bounds) *Bounds {
+	return &Bounds{b.X - b.Width/2, b.Y - b.Height/2, b.Width * 2, b.Height * 2}
+}
+
+// searchBounds returns the area in which objects of this subtree may lie, used to prune queries
+func (qt *Quadtree) searchBounds() *Bounds {
+	b := qt.Bounds
+	if qt.m_config.straddlePolicy == StraddleLoose {
+		b = looseBounds(b)
+	}
+	if margin := qt.m_config.childOverlap; margin > 0 {
+		expanded := b.Expand(margin)
+		b = &expanded
+	}
+	return b
+}
+
+// removeCopies removes every copy of the target from the subtree, and returns the stored object,
+// nil if it was not found
+func (qt *Quadtree) removeCopies(target PhysicalObject) PhysicalObject {
+	var removed PhysicalObject
+	for ele := qt.m_Objects.Front(); ele != nil; {
+		next := ele.Next()
+		if one := ele.Value.(PhysicalObject); qt.same(one, target) {
+			qt.m_Objects.Remove(ele)
+			qt.touch(-1)
+			removed = one
+		}
+		ele = next
+	}
+
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			if one := qt.Nodes[index].removeCopies(target); one != nil {
+				removed = one
+				if qt.m_config.eagerCollapse {
+					qt.collapseChild(index)
+				}
+			}
+		}
+		flags >>= 1
+		index += 1
+	}
+	return removed
+}
+
+// dedupe wraps fn so that it is called at most once per object
+func dedupe(fn func(PhysicalObject)) func(PhysicalObject) {
+	seen := make(map[PhysicalObject]bool)
+	return func(obj PhysicalObject) {
+		if !seen[obj] {
+			seen[obj] = true
+			fn(obj)
+		}
+	}
+}
+
+// reaches tells whether objects stored in a node with search bounds b may intersect target.
+// Intersect measures the distance between the positions of the objects against their mean size, so
+// objects fitting in b can only intersect the target if b reaches the position of the target minus
+// half its size, and does not start beyond its position plus half its size plus half the size of b.
+// Pruning nodes with the bounds of the target instead would make intersections asymmetric.
+// With StraddleDuplicate, copies only overlap the nodes holding them: their position may lie before
+// b by as much as the largest object duplicated, which also bounds their reach
+func (c *config) reaches(b *Bounds, target PhysicalObject) bool {
+	e := b.Expand(c.epsilon)
+	tx, ty := target.X(), target.Y()
+	halfWidth, halfHeight := target.Width()/2, target.Height()/2
+	if c.straddlePolicy == StraddleDuplicate {
+		w, h := math.Max(e.Width, c.straddleWidth), math.Max(e.Height, c.straddleHeight)
+		return e.X+e.Width >= tx-halfWidth-w/2 && e.X-w <= tx+halfWidth+w/2 &&
+			e.Y+e.Height >= ty-halfHeight-h/2 && e.Y-h <= ty+halfHeight+h/2
+	}
+	return e.X+e.Width >= tx-halfWidth && e.X <= tx+halfWidth+e.Width/2 &&
+		e.Y+e.Height >= ty-halfHeight && e.Y <= ty+halfHeight+e.Height/2
+}
+
+// intersecting returns the objects of the subtree intersecting the target, except the target itself,
+// once each. The search counts into the query qc, which may be nil
+func (qt *Quadtree) intersecting(target PhysicalObject, qc *queryConfig) IntersectedObjects {
+	var objects []PhysicalObject
+	c := qt.m_config
+	qt.visitWhere(qc.instrument(qt, func(b *Bounds) bool {
+		return c.reaches(b, target)
+	}, func(obj PhysicalObject) {
+		if !qt.same(obj, target) && Intersect(target, obj) {
+			objects = append(objects, obj)
+		}
+	}))
+	return objects
+}
+
+// getIntersectionByQuery collects intersection records by querying the tree with every object,
+// used when objects of sibling subtrees may overlap each other
+func (qt *Quadtree) getIntersectionByQuery(intersections *list.List, qc *queryConfig) *list.List {
+	recorded := make(map[[2]PhysicalObject]bool)
+	qt.Walk(func(one PhysicalObject) {
+		if qt.m_config.pairsFull(intersections) || qc != nil && qc.truncated || !qc.accepts(qt, one) {
+			return
+		}
+		for _, another := range qt.intersecting(one, qc) {
+			if recorded[[2]PhysicalObject{another, one}] || qt.m_config.pairsFull(intersections) {
+				continue
+			}
+			recorded[[2]PhysicalObject{one, another}] = true
+			intersections.PushBack(&IntersectionRecord{
+				One:     one,
+				Another: another,
+			})
+		}
+	})
+	return intersections
+}
@@ -0,0 +1,123 @@
+package quadtree_test
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+	"github.com/gmlewis/quadtree/quadtreetest"
+)
+
+type countingPhysicalObject struct {
+	TestPhysicalObject
+	updates int
+}
+
+func (po *countingPhysicalObject) Update(delta time.Duration) bool {
+	po.updates += 1
+	return true
+}
+
+func TestStraddleDuplicate(t *testing.T) {
+	straddler := &countingPhysicalObject{TestPhysicalObject: TestPhysicalObject{1.5, 1.5, 1, 1}}
+	objects := []quadtree.PhysicalObject{
+		straddler,
+		&TestPhysicalObject{0, 0, 1, 1},
+		&TestPhysicalObject{3, 3, 1, 1},
+		&TestPhysicalObject{2, 2, 0.5, 0.5},
+	}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 10, quadtree.WithStraddlePolicy(quadtree.StraddleDuplicate))
+	qt.UpdateTree(listOf(objects...))
+
+	if len(qt.Objects()) != 0 || qt.Nodes[0] == nil || qt.Nodes[1] == nil || qt.Nodes[2] == nil || qt.Nodes[3] == nil {
+		t.Fatalf("straddling object expects to be duplicated into every quadrant:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+	walked := 0
+	qt.Walk(func(quadtree.PhysicalObject) { walked += 1 })
+	if walked != len(objects) {
+		t.Errorf("Walk visits %d objects, expects %d", walked, len(objects))
+	}
+
+	expected := quadtree.IntersectedObjects{&TestPhysicalObject{2, 2, 0.5, 0.5}}
+	if inter := qt.GetIntersectedObjects(straddler); !quadtreetest.SameObjects(inter, expected) {
+		t.Errorf("expects intersection:\n%s\nBut has intersection:\n%s", quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(inter))
+	}
+	if records := qt.GetIntersection(nil, nil); records.Len() != 1 {
+		t.Errorf("expects 1 intersection record, got %d", records.Len())
+	}
+
+	qt.Update(0)
+	if straddler.updates != 1 {
+		t.Errorf("duplicated object updated %d times, expects once", straddler.updates)
+	}
+	walked = 0
+	qt.Walk(func(quadtree.PhysicalObject) { walked += 1 })
+	if walked != len(objects) {
+		t.Errorf("Walk visits %d objects after Update, expects %d", walked, len(objects))
+	}
+
+	if !qt.Remove(straddler) || qt.FindObject(straddler) != nil {
+		t.Errorf("every copy of the removed object expects to be gone:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+}
+
+func TestStraddleLoose(t *testing.T) {
+	straddler := &TestPhysicalObject{1.5, 1.5, 1, 1}
+	neighbour := &TestPhysicalObject{0.9, 0.9, 1, 1}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 10, quadtree.WithStraddlePolicy(quadtree.StraddleLoose))
+	qt.UpdateTree(listOf(straddler, neighbour))
+
+	if qt.FindObject(straddler) != qt.Nodes[3] {
+		t.Errorf("straddling object expects to be placed by its center into the bottom right quadrant:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+	if qt.FindObject(neighbour) != qt.Nodes[0] {
+		t.Errorf("object expects to be placed by its center into the top left quadrant:\n%s", quadtreetest.DumpState(qt).String(0))
+	}
+
+	expected := quadtree.IntersectedObjects{neighbour}
+	if inter := qt.GetIntersectedObjects(straddler); !quadtreetest.SameObjects(inter, expected) {
+		t.Errorf("expects intersection:\n%s\nBut has intersection:\n%s", quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(inter))
+	}
+	if records := qt.GetIntersection(nil, nil); records.Len() != 1 {
+		t.Errorf("expects 1 intersection record, got %d", records.Len())
+	}
+}
+
+func TestStraddleDuplicatePruning(t *testing.T) {
+	rng := rand.New(rand.NewSource(11))
+	bounds := &quadtree.Bounds{0, 0, 64, 64}
+	def := quadtree.NewQuadtree(bounds, 1, 6)
+	dup := quadtree.NewQuadtree(bounds, 1, 6, quadtree.WithStraddlePolicy(quadtree.StraddleDuplicate))
+	var objects []quadtree.PhysicalObject
+	for i := 0; i < 200; i += 1 {
+		size := 0.5
+		if i%20 == 0 {
+			// straddlers spanning many nodes
+			size = 12
+		}
+		obj := &TestPhysicalObject{rng.Float64() * (64 - size), rng.Float64() * (64 - size), size, size}
+		objects = append(objects, obj)
+		def.Insert(obj)
+		dup.Insert(obj)
+	}
+
+	visited := 0
+	for _, obj := range objects {
+		expected := def.GetIntersectedObjects(obj)
+		var stats quadtree.QueryStats
+		if got := dup.GetIntersectedObjects(obj, quadtree.WithStats(&stats)); !quadtreetest.SameObjects(got, expected) {
+			t.Errorf("expects %v to intersect\n%s\nas with the default policy, got\n%s", obj,
+				quadtreetest.ObjectsString(expected), quadtreetest.ObjectsString(got))
+		}
+		visited += stats.NodesVisited
+	}
+	if expected, got := def.GetIntersection(nil, nil).Len(), dup.GetIntersection(nil, nil).Len(); got != expected {
+		t.Errorf("expects %d pairs as with the default policy, got %d", expected, got)
+	}
+
+	_, nodes := dup.Size()
+	if visited >= nodes*len(objects)/2 {
+		t.Errorf("expects searches to prune nodes, visited %d of %d nodes on average", visited/len(objects), nodes)
+	}
+}
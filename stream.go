@@ -0,0 +1,139 @@
+//go:build !tinygo
+// +build !tinygo
+
+package quadtree
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// streamPatch is a JSON document written by StreamChanges
+type streamPatch struct {
+	Elapsed int64          `json:"elapsed"` // update time since the stream started, in nanoseconds
+	Changes []streamChange `json:"changes"`
+}
+
+// streamChange is a change of a streamPatch. Op is the name of the ChangeKind, or "object updated"
+// for an object whose bounds changed while staying in the same node
+type streamChange struct {
+	Op     string  `json:"op"`
+	Path   string  `json:"path"`
+	From   string  `json:"from,omitempty"`
+	ID     uint64  `json:"id,omitempty"`
+	Bounds *Bounds `json:"bounds,omitempty"`
+}
+
+// changeStream remembers the layout last written by StreamChanges
+type changeStream struct {
+	root     *Quadtree
+	w        io.Writer
+	interval time.Duration
+	elapsed  time.Duration
+	pending  time.Duration // update time since the last patch
+	previous *treeLayout
+	ids      map[PhysicalObject]uint64 // identifiers of the published objects
+	nextID   uint64                    // last identifier given, when the tree does not assign IDs
+	err      error
+}
+
+// StreamChanges writes to w a JSON patch of the changes of the tree, one document per line, every
+// time Updates advanced the simulation by interval, starting with a patch adding the whole tree.
+// A patch lists the nodes added and removed with their bounds, and the objects added, removed,
+// moved to another node or changed in bounds, identified by their ID when the tree was created
+// WithIDs. Patches without changes are not written, which makes the output suitable to drive a
+// live viewer through a WebSocket or any other stream. The returned function stops streaming and
+// returns the first error met writing to w, streaming stops at that error too
+func (qt *Quadtree) StreamChanges(w io.Writer, interval time.Duration) func() error {
+	if !qt.ready() {
+		return func() error { return ErrNilQuadtree }
+	}
+	c := qt.m_config
+	s := &changeStream{
+		root:     qt.root(),
+		w:        w,
+		interval: interval,
+		previous: layoutOf(nil),
+		ids:      make(map[PhysicalObject]uint64),
+	}
+	s.publish()
+	c.streams = append(c.streams, s)
+	return func() error {
+		for i, other := range c.streams {
+			if other == s {
+				c.streams = append(c.streams[:i], c.streams[i+1:]...)
+				break
+			}
+		}
+		return s.err
+	}
+}
+
+// advance publishes the changes of the tree once Updates advanced the simulation by the interval
+func (s *changeStream) advance(delta time.Duration) {
+	s.elapsed += delta
+	s.pending += delta
+	if s.err == nil && s.pending >= s.interval {
+		s.pending = 0
+		s.publish()
+	}
+}
+
+// publish writes the changes of the tree since the previous patch
+func (s *changeStream) publish() {
+	current := layoutOf(s.root)
+	patch := streamPatch{Elapsed: int64(s.elapsed)}
+	for _, change := range diffLayouts(s.previous, current) {
+		entry := streamChange{Op: change.Kind.String(), Path: formatPath(change.Path)}
+		switch change.Kind {
+		case NodeAdded:
+			b := current.nodeBounds[entry.Path]
+			entry.Bounds = &b
+		case ObjectRemoved:
+			entry.ID = s.id(change.Object)
+			delete(s.ids, change.Object)
+		case ObjectMoved:
+			entry.From = formatPath(change.From)
+			fallthrough
+		case ObjectAdded:
+			entry.ID = s.id(change.Object)
+			b := current.objectBounds[change.Object]
+			entry.Bounds = &b
+		}
+		patch.Changes = append(patch.Changes, entry)
+	}
+	for _, obj := range current.objects {
+		from, found := s.previous.objectPath[obj]
+		if !found || !samePath(from, current.objectPath[obj]) {
+			continue
+		}
+		if b := current.objectBounds[obj]; b != s.previous.objectBounds[obj] {
+			patch.Changes = append(patch.Changes, streamChange{
+				Op:     "object updated",
+				Path:   formatPath(from),
+				ID:     s.id(obj),
+				Bounds: &b,
+			})
+		}
+	}
+	s.previous = current
+	if len(patch.Changes) > 0 {
+		s.err = json.NewEncoder(s.w).Encode(&patch)
+	}
+}
+
+// id returns the identifier of the object in the patches, the identifier it was first published
+// with so that removed objects the tree already forgot keep it
+func (s *changeStream) id(obj PhysicalObject) uint64 {
+	if id, found := s.ids[obj]; found {
+		return id
+	}
+	id, found := s.root.ID(obj)
+	if !found {
+		s.nextID += 1
+		id = s.nextID
+	}
+	s.ids[obj] = id
+	return id
+}
@@ -0,0 +1,77 @@
+//go:build !tinygo
+// +build !tinygo
+
+package quadtree_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("closed") }
+
+func TestStreamChanges(t *testing.T) {
+	staying := &TestPhysicalObject{3, 3, 1, 1}
+	moving := &TestPhysicalObject{0, 0, 1, 1}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 1, quadtree.WithIDs())
+	qt.Insert(staying)
+	qt.Insert(moving)
+
+	var buf bytes.Buffer
+	stop := qt.StreamChanges(&buf, 2*time.Second)
+	expected := `{"elapsed":0,"changes":[` +
+		`{"op":"node added","path":"/","bounds":{"X":0,"Y":0,"Width":4,"Height":4}},` +
+		`{"op":"node added","path":"/0","bounds":{"X":0,"Y":0,"Width":2,"Height":2}},` +
+		`{"op":"node added","path":"/3","bounds":{"X":2,"Y":2,"Width":2,"Height":2}},` +
+		`{"op":"object added","path":"/0","id":2,"bounds":{"X":0,"Y":0,"Width":1,"Height":1}},` +
+		`{"op":"object added","path":"/3","id":1,"bounds":{"X":3,"Y":3,"Width":1,"Height":1}}]}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expects the first patch to add the whole tree\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	qt.Update(time.Second)
+	if buf.Len() != 0 {
+		t.Errorf("expects no patch before the interval, got %s", buf.String())
+	}
+	moving.x = 0.5
+	qt.Update(time.Second)
+	expected = `{"elapsed":2000000000,"changes":[` +
+		`{"op":"object updated","path":"/0","id":2,"bounds":{"X":0.5,"Y":0,"Width":1,"Height":1}}]}` + "\n"
+	if buf.String() != expected {
+		t.Errorf("expects a patch updating the object\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	qt.Remove(staying)
+	qt.Update(2 * time.Second)
+	if !strings.Contains(buf.String(), `{"op":"object removed","path":"/3","id":1}`) {
+		t.Errorf("expects the removed object with its ID, got %s", buf.String())
+	}
+
+	buf.Reset()
+	if err := stop(); err != nil {
+		t.Errorf("expects no error, got %v", err)
+	}
+	moving.y = 3
+	qt.Update(2 * time.Second)
+	if buf.Len() != 0 {
+		t.Errorf("expects no patch once stopped, got %s", buf.String())
+	}
+}
+
+func TestStreamChangesError(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 4, 4}, 1, 1)
+	qt.Insert(&TestPhysicalObject{0, 0, 1, 1})
+	stop := qt.StreamChanges(failingWriter{}, time.Second)
+	if err := stop(); err == nil || err.Error() != "closed" {
+		t.Errorf("expects the error of the writer, got %v", err)
+	}
+}
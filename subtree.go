@@ -0,0 +1,242 @@
+package quadtree
+
+import (
+	"container/list"
+	"math"
+	"math/rand"
+)
+
+// SubtreeView is a region of a tree, such as a room, queried as a tree of its own. It shares its objects
+// with the tree: queries are clipped to the region and see every object overlapping it, including the
+// objects of ancestor nodes crossing into it, and mutations are forwarded to the root of the tree.
+// The queries taking options run once over the tree, skipping the objects outside of the view
+type SubtreeView struct {
+	tree   *Quadtree // root of the tree
+	bounds Bounds    // region of the view
+	key    NodeKey   // deepest node whose bounds contained the region when the view was made
+}
+
+// Subtree returns a view of the region b of the tree. The view holds no node of the tree, so it stays
+// valid however the tree changes
+func (qt *Quadtree) Subtree(b *Bounds) *SubtreeView {
+	if !qt.ready() {
+		return nil
+	}
+	r := b.MinMax()
+	node := qt.root()
+	key := NodeKey("")
+	for {
+		next := -1
+		for index, child := range node.Nodes {
+			if node.m_ActiveNodes&(1<<uint(index)) != 0 && child.Contains(&r) {
+				next = index
+				break
+			}
+		}
+		if next == -1 {
+			return &SubtreeView{tree: qt.root(), bounds: *b, key: key}
+		}
+		node = node.Nodes[next]
+		key = key.Child(next)
+	}
+}
+
+// Bounds returns the region of the view
+func (v *SubtreeView) Bounds() Bounds {
+	return v.bounds
+}
+
+// Key returns the address of the deepest node whose bounds contained the region when the view was made
+func (v *SubtreeView) Key() NodeKey {
+	return v.key
+}
+
+// Node returns the deepest node of the tree on the path to Key which still exists
+func (v *SubtreeView) Node() *Quadtree {
+	node := v.tree
+	for i := 0; i < len(v.key); i += 1 {
+		index := int(v.key[i] - '0')
+		if node.m_ActiveNodes&(1<<uint(index)) == 0 {
+			break
+		}
+		node = node.Nodes[index]
+	}
+	return node
+}
+
+// overlaps tells whether obj overlaps the region of the view
+func (v *SubtreeView) overlaps(obj PhysicalObject) bool {
+	return v.bounds.Intersects(boundsOf(obj))
+}
+
+// clip returns the part of region inside the view, false if they do not overlap. An object overlaps both
+// region and the view exactly when it overlaps the part of region inside the view
+func (v *SubtreeView) clip(region *Bounds) (*Bounds, bool) {
+	if !v.bounds.Intersects(region) {
+		return nil, false
+	}
+	minX, minY := math.Max(v.bounds.X, region.X), math.Max(v.bounds.Y, region.Y)
+	maxX := math.Min(v.bounds.X+v.bounds.Width, region.X+region.Width)
+	maxY := math.Min(v.bounds.Y+v.bounds.Height, region.Y+region.Height)
+	return &Bounds{minX, minY, maxX - minX, maxY - minY}, true
+}
+
+// within adds to opts the option skipping the objects outside of the view
+func (v *SubtreeView) within(opts []QueryOption) []QueryOption {
+	return append(opts[:len(opts):len(opts)], acceptOnly(v.overlaps))
+}
+
+// Retrieve returns the objects overlapping both region and the view
+func (v *SubtreeView) Retrieve(region *Bounds, opts ...QueryOption) IntersectedObjects {
+	clipped, ok := v.clip(region)
+	if !ok {
+		return nil
+	}
+	return v.tree.Retrieve(clipped, opts...)
+}
+
+// RetrieveTagged is Retrieve restricted to the objects carrying tag
+func (v *SubtreeView) RetrieveTagged(region *Bounds, tag string, opts ...QueryOption) IntersectedObjects {
+	clipped, ok := v.clip(region)
+	if !ok {
+		return nil
+	}
+	return v.tree.RetrieveTagged(clipped, tag, opts...)
+}
+
+// RetrieveClusters is Retrieve returning the deeper objects as clusters, see Quadtree.RetrieveClusters.
+// Clusters only count the objects of the view
+func (v *SubtreeView) RetrieveClusters(region *Bounds, opts ...QueryOption) (IntersectedObjects, []Cluster) {
+	clipped, ok := v.clip(region)
+	if !ok {
+		return nil, nil
+	}
+	return v.tree.RetrieveClusters(clipped, v.within(opts)...)
+}
+
+// Query returns a cursor over the objects Retrieve would return for region
+func (v *SubtreeView) Query(region *Bounds, opts ...QueryOption) *Cursor {
+	if clipped, ok := v.clip(region); ok {
+		return v.tree.Query(clipped, opts...)
+	}
+	// no object overlaps both region and the view
+	return v.tree.Query(region, v.within(opts)...)
+}
+
+// QueryRing returns the objects of the view overlapping the ring, see Quadtree.QueryRing
+func (v *SubtreeView) QueryRing(cx, cy, rInner, rOuter float64, opts ...QueryOption) IntersectedObjects {
+	return v.tree.QueryRing(cx, cy, rInner, rOuter, v.within(opts)...)
+}
+
+// QueryAlongPath returns the objects of the view within radius of the polyline, see Quadtree.QueryAlongPath
+func (v *SubtreeView) QueryAlongPath(points []Point, radius float64, opts ...QueryOption) IntersectedObjects {
+	return v.tree.QueryAlongPath(points, radius, v.within(opts)...)
+}
+
+// NearestInDirection returns the closest object of the view in a direction, see Quadtree.NearestInDirection
+func (v *SubtreeView) NearestInDirection(x, y, dx, dy float64, maxAngle float64, opts ...QueryOption) PhysicalObject {
+	return v.tree.NearestInDirection(x, y, dx, dy, maxAngle, v.within(opts)...)
+}
+
+// Explain runs a Retrieve of region within the view and records its decisions, see Quadtree.Explain
+func (v *SubtreeView) Explain(region *Bounds, opts ...QueryOption) Trace {
+	if clipped, ok := v.clip(region); ok {
+		region = clipped
+	}
+	return v.tree.Explain(region, v.within(opts)...)
+}
+
+// SampleInRegion returns up to n random objects overlapping both region and the view
+func (v *SubtreeView) SampleInRegion(region *Bounds, n int, rng *rand.Rand) []PhysicalObject {
+	clipped, ok := v.clip(region)
+	if !ok {
+		return nil
+	}
+	return v.tree.SampleInRegion(clipped, n, rng)
+}
+
+// AggregateInRegion returns the value of the named aggregator over the objects overlapping both region
+// and the view, nil if no aggregator is registered under name
+func (v *SubtreeView) AggregateInRegion(name string, region *Bounds) interface{} {
+	clipped, ok := v.clip(region)
+	if !ok {
+		c := v.tree.m_config
+		if index := c.aggregatorIndex(name); index >= 0 {
+			return c.aggregators[index].agg.Zero()
+		}
+		return nil
+	}
+	return v.tree.AggregateInRegion(name, clipped)
+}
+
+// MetricInRegion returns the stats of the named metric over the objects overlapping both region and the view
+func (v *SubtreeView) MetricInRegion(name string, region *Bounds) MetricStats {
+	clipped, ok := v.clip(region)
+	if !ok {
+		return emptyMetricStats()
+	}
+	return v.tree.MetricInRegion(name, clipped)
+}
+
+// SumInRegion returns the sum of the named metric over the objects overlapping both region and the view
+func (v *SubtreeView) SumInRegion(name string, region *Bounds) float64 {
+	return v.MetricInRegion(name, region).Sum
+}
+
+// MinInRegion returns the smallest value of the named metric over the objects overlapping both region and
+// the view, false if there is no such object
+func (v *SubtreeView) MinInRegion(name string, region *Bounds) (float64, bool) {
+	stats := v.MetricInRegion(name, region)
+	return stats.Min, stats.Count > 0
+}
+
+// MaxInRegion returns the largest value of the named metric over the objects overlapping both region and
+// the view, false if there is no such object
+func (v *SubtreeView) MaxInRegion(name string, region *Bounds) (float64, bool) {
+	stats := v.MetricInRegion(name, region)
+	return stats.Max, stats.Count > 0
+}
+
+// Walk calls walker with every object overlapping the view
+func (v *SubtreeView) Walk(walker func(PhysicalObject)) {
+	for _, obj := range v.tree.Retrieve(&v.bounds) {
+		walker(obj)
+	}
+}
+
+// Len returns the number of objects overlapping the view
+func (v *SubtreeView) Len() int {
+	return len(v.tree.Retrieve(&v.bounds))
+}
+
+// FindObject returns the node of the tree storing the object, nil if it is not found or lies outside of the view
+func (v *SubtreeView) FindObject(obj PhysicalObject) *Quadtree {
+	if !v.overlaps(obj) {
+		return nil
+	}
+	return v.tree.FindObject(obj)
+}
+
+// Insert inserts the object into the tree, it returns ErrOutOfBounds if the object does not fit in the view
+func (v *SubtreeView) Insert(obj PhysicalObject) error {
+	if !v.bounds.Contains(obj) {
+		return ErrOutOfBounds
+	}
+	return v.tree.Insert(obj)
+}
+
+// Remove removes the object from the tree if it overlaps the view
+func (v *SubtreeView) Remove(obj PhysicalObject) bool {
+	return v.overlaps(obj) && v.tree.Remove(obj)
+}
+
+// GetIntersectedObjects returns the objects of the view intersecting the target
+func (v *SubtreeView) GetIntersectedObjects(target PhysicalObject, opts ...QueryOption) IntersectedObjects {
+	return v.tree.GetIntersectedObjects(target, v.within(opts)...)
+}
+
+// GetIntersection appends to intersections the intersecting pairs of objects of the view, every pair once,
+// found in a single traversal of the tree skipping the objects outside of the view
+func (v *SubtreeView) GetIntersection(intersections *list.List, opts ...QueryOption) *list.List {
+	return v.tree.GetIntersection(intersections, nil, v.within(opts)...)
+}
@@ -0,0 +1,126 @@
+package quadtree_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestSubtree(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	inside := &TestPhysicalObject{0.5, 0.5, 1, 1}
+	neighbour := &TestPhysicalObject{2.5, 0.5, 1, 1}
+	outside := &TestPhysicalObject{6, 6, 1, 1}
+	straddler := &TestPhysicalObject{1.5, 3.5, 1, 1}
+	for _, obj := range []quadtree.PhysicalObject{inside, neighbour, outside, straddler} {
+		qt.Insert(obj)
+	}
+
+	room := qt.Subtree(&quadtree.Bounds{0.25, 0.25, 1.5, 3.5})
+	if room == nil || room.Key() != "0" || room.Node() != qt.Nodes[0] {
+		t.Fatalf("expects the view to lie in the top left quadrant, got %v", room)
+	}
+	if got := room.Retrieve(&quadtree.Bounds{0, 0, 8, 8}); !sameObjects(got, inside, straddler) {
+		t.Errorf("expects queries clipped to the view, with the straddlers of ancestors, got %v", got)
+	}
+	if room.Len() != 2 {
+		t.Errorf("expects 2 objects in the view, got %d", room.Len())
+	}
+	if wide := qt.Subtree(&quadtree.Bounds{1, 1, 4, 4}); wide.Key() != "" {
+		t.Errorf("expects the root for bounds spanning its quadrants, got %v", wide.Key())
+	}
+
+	if err := room.Insert(&TestPhysicalObject{6, 6, 1, 1}); err != quadtree.ErrOutOfBounds {
+		t.Errorf("expects objects outside of the view to be rejected, got %v", err)
+	}
+	added := &TestPhysicalObject{1, 1, 0.5, 0.5}
+	room.Insert(added)
+	if qt.FindObject(added) == nil {
+		t.Error("expects the view to share its objects with the tree")
+	}
+	if found := room.GetIntersectedObjects(added); !sameObjects(found, inside) {
+		t.Errorf("expects added to intersect inside, got %v", found)
+	}
+	if pairs := room.GetIntersection(nil); pairs.Len() != 1 {
+		t.Errorf("expects a single pair in the view, got %d", pairs.Len())
+	}
+	if room.Remove(outside) || qt.FindObject(outside) == nil {
+		t.Error("expects objects outside of the view not to be removed through it")
+	}
+	qt.Remove(inside)
+	qt.Remove(added)
+	if room.FindObject(inside) != nil {
+		t.Error("expects removals from the tree to be seen by the view")
+	}
+	qt.Update(0)
+	if got := room.Retrieve(&quadtree.Bounds{0, 0, 8, 8}); !sameObjects(got, straddler) {
+		t.Errorf("expects the view to survive pruning, got %v", got)
+	}
+}
+
+func TestSubtreeQueries(t *testing.T) {
+	rng := rand.New(rand.NewSource(7))
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 64, 64}, 2, 6)
+	var objects []quadtree.PhysicalObject
+	for i := 0; i < 300; i += 1 {
+		obj := &TestPhysicalObject{rng.Float64() * 62, rng.Float64() * 62, 1 + rng.Float64(), 1 + rng.Float64()}
+		objects = append(objects, obj)
+		qt.Insert(obj)
+	}
+	view := &quadtree.Bounds{8, 8, 20, 20}
+	room := qt.Subtree(view)
+	inView := make(map[quadtree.PhysicalObject]bool)
+	for _, obj := range objects {
+		if view.Intersects(&quadtree.Bounds{obj.X(), obj.Y(), obj.Width(), obj.Height()}) {
+			inView[obj] = true
+		}
+	}
+
+	expected := make(map[[2]quadtree.PhysicalObject]bool)
+	for i, one := range objects {
+		for _, another := range objects[i+1:] {
+			if inView[one] && inView[another] && quadtree.Intersect(one, another) {
+				expected[[2]quadtree.PhysicalObject{one, another}] = true
+			}
+		}
+	}
+	var stats quadtree.QueryStats
+	pairs := room.GetIntersection(nil, quadtree.WithStats(&stats))
+	if pairs.Len() != len(expected) {
+		t.Errorf("expects %d pairs in the view, got %d", len(expected), pairs.Len())
+	}
+	for ele := pairs.Front(); ele != nil; ele = ele.Next() {
+		record := ele.Value.(*quadtree.IntersectionRecord)
+		if !expected[[2]quadtree.PhysicalObject{record.One, record.Another}] && !expected[[2]quadtree.PhysicalObject{record.Another, record.One}] {
+			t.Errorf("expects no pair %v", *record)
+		}
+	}
+	if _, nodes := qt.Size(); stats.NodesVisited > nodes {
+		t.Errorf("expects a single traversal of the %d nodes, visited %d", nodes, stats.NodesVisited)
+	}
+
+	for _, obj := range room.QueryRing(18, 18, 0, 40) {
+		if !inView[obj] {
+			t.Errorf("expects QueryRing to return objects of the view only, got %v", obj)
+		}
+	}
+	if len(room.QueryRing(18, 18, 0, 40)) != len(inView) {
+		t.Errorf("expects a ring covering the view to return its %d objects", len(inView))
+	}
+	if nearest := room.NearestInDirection(0, 0, 1, 1, 0.5); nearest == nil || !inView[nearest] {
+		t.Errorf("expects the nearest object of the view, got %v", nearest)
+	}
+	count, batch := 0, make([]quadtree.PhysicalObject, 16)
+	for cursor, done := room.Query(&quadtree.Bounds{0, 0, 64, 64}), false; !done; {
+		var n int
+		n, done = cursor.Next(batch)
+		count += n
+	}
+	if count != len(inView) {
+		t.Errorf("expects the cursor to yield the %d objects of the view, got %d", len(inView), count)
+	}
+	if found := room.Retrieve(&quadtree.Bounds{40, 40, 10, 10}); len(found) != 0 {
+		t.Errorf("expects nothing outside of the view, got %v", found)
+	}
+}
@@ -0,0 +1,53 @@
+package quadtree
+
+// Tag attaches tags to an object of the tree, tags are dropped when the object is removed
+func (qt *Quadtree) Tag(obj PhysicalObject, tags ...string) {
+	if !qt.ready() {
+		return
+	}
+	c := qt.m_config
+	obj = qt.canonical(obj)
+	if c.tags == nil {
+		c.tags = make(map[PhysicalObject]map[string]bool, c.expectedObjects)
+	}
+	set := c.tags[obj]
+	if set == nil {
+		set = make(map[string]bool)
+		c.tags[obj] = set
+	}
+	for _, tag := range tags {
+		set[tag] = true
+	}
+}
+
+// Untag detaches tags from an object
+func (qt *Quadtree) Untag(obj PhysicalObject, tags ...string) {
+	if !qt.ready() {
+		return
+	}
+	obj = qt.canonical(obj)
+	set := qt.m_config.tags[obj]
+	for _, tag := range tags {
+		delete(set, tag)
+	}
+	if len(set) == 0 {
+		delete(qt.m_config.tags, obj)
+	}
+}
+
+// HasTag tells whether the object carries the tag
+func (qt *Quadtree) HasTag(obj PhysicalObject, tag string) bool {
+	return qt.ready() && qt.m_config.tags[qt.canonical(obj)][tag]
+}
+
+// RetrieveTagged is Retrieve restricted to the objects carrying tag, other objects are skipped
+// without testing their bounds
+func (qt *Quadtree) RetrieveTagged(region *Bounds, tag string, opts ...QueryOption) IntersectedObjects {
+	if !qt.ready() {
+		return nil
+	}
+	tags := qt.m_config.tags
+	return qt.Retrieve(region, append(opts[:len(opts):len(opts)], acceptOnly(func(obj PhysicalObject) bool {
+		return tags[obj][tag]
+	}))...)
+}
@@ -0,0 +1,40 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestRetrieveTagged(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	enemy := &TestPhysicalObject{1, 1, 1, 1}
+	pickup := &TestPhysicalObject{2, 2, 1, 1}
+	farEnemy := &TestPhysicalObject{6, 6, 1, 1}
+	for _, obj := range []quadtree.PhysicalObject{enemy, pickup, farEnemy} {
+		qt.Insert(obj)
+	}
+	qt.Tag(enemy, "enemy", "flying")
+	qt.Tag(farEnemy, "enemy")
+	qt.Tag(pickup, "pickup")
+
+	blast := &quadtree.Bounds{0, 0, 4, 4}
+	var stats quadtree.QueryStats
+	found := qt.RetrieveTagged(blast, "enemy", quadtree.WithStats(&stats))
+	if len(found) != 1 || found[0] != enemy {
+		t.Errorf("expects only the enemy within the blast, got %v", found)
+	}
+	// the node of the far enemy touches the blast, the pickup is skipped
+	if stats.CandidatesTested != 2 {
+		t.Errorf("expects untagged objects to be skipped before testing, got %d candidates tested", stats.CandidatesTested)
+	}
+
+	qt.Untag(enemy, "enemy")
+	if qt.HasTag(enemy, "enemy") || !qt.HasTag(enemy, "flying") {
+		t.Errorf("expects only the enemy tag to be detached")
+	}
+	qt.Remove(pickup)
+	if qt.HasTag(pickup, "pickup") {
+		t.Errorf("expects tags to be dropped with the object")
+	}
+}
@@ -0,0 +1,311 @@
+package quadtree
+
+import (
+	"time"
+)
+
+// updateRegion is a region whose objects are updated at a reduced rate
+type updateRegion struct {
+	bounds Bounds
+	every  int // objects are updated every that many Updates, 0 for never
+}
+
+// intervalBucket identifies the objects updated at the same Updates by SetUpdateInterval
+type intervalBucket struct {
+	every int
+	phase int // objects are due when the Updates so far modulo every equal phase
+}
+
+// throttle holds the regions of SetRegionActive and SetRegionUpdateRate, and the objects of SetUpdateInterval
+type throttle struct {
+	regions   []updateRegion
+	ticks     int                              // Updates of the tree so far
+	pending   map[PhysicalObject]time.Duration // time the throttled objects skipped since their last update
+	elapsed   time.Duration                    // time of the Updates so far
+	intervals map[PhysicalObject]intervalBucket
+	buckets   map[intervalBucket][]PhysicalObject
+	order     []intervalBucket                 // buckets in the order they were created, for a deterministic Update
+	last      map[PhysicalObject]time.Duration // elapsed time at the last update of the objects of the buckets
+	moved     map[PhysicalObject]bool          // objects of the due buckets which moved during current Update
+	due       []PhysicalObject                 // moved objects of the due buckets, in the order they were updated
+	from      map[PhysicalObject]Bounds        // stored bounds of the moved objects of the due buckets before they moved
+	spread    int                              // objects given an interval so far, to spread them across phases
+}
+
+// newThrottle returns the throttle of the tree, creating it on first use
+func (c *config) newThrottle() *throttle {
+	if c.throttle == nil {
+		c.throttle = &throttle{
+			pending:   make(map[PhysicalObject]time.Duration),
+			intervals: make(map[PhysicalObject]intervalBucket),
+			buckets:   make(map[intervalBucket][]PhysicalObject),
+			last:      make(map[PhysicalObject]time.Duration),
+			moved:     make(map[PhysicalObject]bool),
+			from:      make(map[PhysicalObject]Bounds),
+		}
+	}
+	return c.throttle
+}
+
+// SetRegionActive makes the objects whose center lies within b receive every Update if active,
+// or none until the region is set active again. See SetRegionUpdateRate
+func (qt *Quadtree) SetRegionActive(b *Bounds, active bool) {
+	every := 0
+	if active {
+		every = 1
+	}
+	qt.SetRegionUpdateRate(b, every)
+}
+
+// SetRegionUpdateRate makes the objects whose center lies within b receive one Update every given
+// number of Updates of the tree, with the time elapsed since their previous update, or none for 0.
+// The region set last decides for objects within several regions, objects outside of every region
+// are updated every time. Setting a region with the same bounds again replaces its rate
+func (qt *Quadtree) SetRegionUpdateRate(b *Bounds, every int) {
+	if !qt.ready() {
+		return
+	}
+	t := qt.m_config.newThrottle()
+	for i, region := range t.regions {
+		if region.bounds == *b {
+			t.regions = append(t.regions[:i], t.regions[i+1:]...)
+			break
+		}
+	}
+	if every != 1 {
+		t.regions = append(t.regions, updateRegion{bounds: *b, every: every})
+	}
+}
+
+// updateObject calls the Update of the object unless its region throttles it, and tells whether it moved
+func (qt *Quadtree) updateObject(obj PhysicalObject, delta time.Duration) bool {
+	if s := qt.m_config.committing; s != nil {
+		return s.moved[obj] || qt.misplaced(obj)
+	}
+	t := qt.m_config.throttle
+	if t == nil {
+		return obj.Update(delta)
+	}
+	if _, found := t.intervals[obj]; found {
+		return t.moved[obj]
+	}
+	if len(t.regions) == 0 {
+		return obj.Update(delta)
+	}
+	every := 1
+	x, y := center(obj)
+	for i := len(t.regions) - 1; i >= 0; i -= 1 {
+		if t.regions[i].bounds.ContainsPoint(x, y) {
+			every = t.regions[i].every
+			break
+		}
+	}
+	if every == 1 {
+		if skipped, ok := t.pending[obj]; ok {
+			delete(t.pending, obj)
+			delta += skipped
+		}
+		return obj.Update(delta)
+	}
+	if every == 0 {
+		return false
+	}
+	if t.ticks%every != 0 {
+		t.pending[obj] += delta
+		return false
+	}
+	delta += t.pending[obj]
+	delete(t.pending, obj)
+	return obj.Update(delta)
+}
+
+// tick counts an Update of the tree for the throttled regions, and updates the objects of the due
+// buckets of SetUpdateInterval
+func (qt *Quadtree) tick(delta time.Duration) {
+	t := qt.m_config.throttle
+	if t == nil {
+		return
+	}
+	t.ticks += 1
+	t.elapsed += delta
+	for obj := range t.moved {
+		delete(t.moved, obj)
+	}
+	for obj := range t.from {
+		delete(t.from, obj)
+	}
+	t.due = t.due[:0]
+	for _, bucket := range t.order {
+		if t.ticks%bucket.every != bucket.phase {
+			continue
+		}
+		for _, obj := range t.buckets[bucket] {
+			if qt.buried(obj) {
+				continue
+			}
+			from := *qt.m_config.storedBounds(obj)
+			if obj.Update(t.elapsed - t.last[obj]) {
+				t.moved[obj] = true
+				t.due = append(t.due, obj)
+				t.from[obj] = from
+			}
+			t.last[obj] = t.elapsed
+		}
+	}
+}
+
+// relocateDue relocates the objects of the due buckets which moved during the tick of current
+// Update, starting from the node found along their previous bounds rather than from a traversal of
+// the tree. Nodes they leave empty are collapsed at once
+func (qt *Quadtree) relocateDue() {
+	t := qt.m_config.throttle
+	if t == nil || len(t.due) == 0 {
+		return
+	}
+	root := qt.root()
+	for _, obj := range t.due {
+		if !t.moved[obj] {
+			// the object left the tree during the tick
+			continue
+		}
+		delete(t.moved, obj)
+		from := t.from[obj]
+		node := root.locate(&flatObject{from.X, from.Y, from.Width, from.Height}, obj)
+		root.syncHandle(obj)
+		root.logMove(obj)
+		if !validCoordinates(obj) {
+			root.remove(obj)
+			root.dropInvalid(obj)
+			continue
+		}
+		if node == nil || root.m_config.straddlePolicy == StraddleDuplicate {
+			root.remove(obj)
+			root.insert(obj)
+			continue
+		}
+		if q := root.m_config.relocationQueue; q != nil {
+			q.push(node, obj)
+			continue
+		}
+		if node.contains(obj) && (node.m_ActiveNodes == 0 || node.placement(obj) == 0) {
+			// the object still belongs to its node
+			continue
+		}
+		node.relocate(obj)
+		node.collapseUp()
+	}
+	t.due = t.due[:0]
+}
+
+// onlyIntervals tells whether every object of the tree has an interval, so that the due buckets
+// updated by the tick leave nothing for the traversal of Update to do
+func (qt *Quadtree) onlyIntervals() bool {
+	t := qt.m_config.throttle
+	return t != nil && len(t.intervals) > 0 && qt.m_config.committing == nil &&
+		qt.m_config.straddlePolicy != StraddleDuplicate && qt.root().m_objectCount <= len(t.intervals)
+}
+
+// SetUpdateInterval makes the object receive one Update every given number of Updates of the tree,
+// with the time elapsed since its previous update, e.g. to update distant characters less often.
+// Objects are kept in buckets by interval, so that an Update of the tree only calls the objects of
+// the due buckets, and objects of the same interval are spread across Updates. The objects which
+// moved are relocated from their node directly, and the nodes are not traversed at all while every
+// object of the tree has an interval. An interval of 1 or less restores every Update, objects not
+// in the tree are ignored and the interval is dropped when the object leaves the tree
+func (qt *Quadtree) SetUpdateInterval(obj PhysicalObject, every int) {
+	if !qt.ready() {
+		return
+	}
+	t := qt.m_config.newThrottle()
+	obj = qt.canonical(obj)
+	t.dropInterval(obj)
+	if every <= 1 || qt.root().FindObject(obj) == nil {
+		return
+	}
+	bucket := intervalBucket{every: every, phase: t.spread % every}
+	t.spread += 1
+	t.intervals[obj] = bucket
+	if _, found := t.buckets[bucket]; !found {
+		t.order = append(t.order, bucket)
+	}
+	t.buckets[bucket] = append(t.buckets[bucket], obj)
+	t.last[obj] = t.elapsed
+}
+
+// dropInterval removes the object from its bucket
+func (t *throttle) dropInterval(obj PhysicalObject) {
+	bucket, found := t.intervals[obj]
+	if !found {
+		return
+	}
+	objects := t.buckets[bucket]
+	for i, one := range objects {
+		if one == obj {
+			objects = append(objects[:i], objects[i+1:]...)
+			break
+		}
+	}
+	if len(objects) == 0 {
+		delete(t.buckets, bucket)
+		for i, one := range t.order {
+			if one == bucket {
+				t.order = append(t.order[:i], t.order[i+1:]...)
+				break
+			}
+		}
+	} else {
+		t.buckets[bucket] = objects
+	}
+	delete(t.intervals, obj)
+	delete(t.last, obj)
+	delete(t.moved, obj)
+	delete(t.from, obj)
+}
+
+// rekey moves the pending time and the interval of old to obj, see Quadtree.rekey
+func (t *throttle) rekey(old, obj PhysicalObject) {
+	if skipped, ok := t.pending[old]; ok {
+		delete(t.pending, old)
+		t.pending[obj] = skipped
+	}
+	bucket, found := t.intervals[old]
+	if !found {
+		return
+	}
+	for i, one := range t.buckets[bucket] {
+		if one == old {
+			t.buckets[bucket][i] = obj
+		}
+	}
+	delete(t.intervals, old)
+	t.intervals[obj] = bucket
+	t.last[obj] = t.last[old]
+	delete(t.last, old)
+	if t.moved[old] {
+		delete(t.moved, old)
+		t.moved[obj] = true
+	}
+	if from, ok := t.from[old]; ok {
+		delete(t.from, old)
+		t.from[obj] = from
+	}
+}
+
+// dropLeftIntervals drops the intervals of the objects a rebuild of the tree left out
+func (qt *Quadtree) dropLeftIntervals() {
+	t := qt.m_config.throttle
+	if t == nil || len(t.intervals) == 0 {
+		return
+	}
+	kept := make(map[PhysicalObject]bool)
+	qt.root().walk(func(obj PhysicalObject) {
+		kept[obj] = true
+	})
+	for _, bucket := range append([]intervalBucket(nil), t.order...) {
+		for _, obj := range append([]PhysicalObject(nil), t.buckets[bucket]...) {
+			if !kept[obj] {
+				t.dropInterval(obj)
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package quadtree_test
+
+import (
+	"container/list"
+	"testing"
+	"time"
+
+	"github.com/gmlewis/quadtree"
+)
+
+// timedObject records the time it was updated with
+type timedObject struct {
+	TestPhysicalObject
+	updates int
+	elapsed time.Duration
+}
+
+func (po *timedObject) Update(delta time.Duration) bool {
+	po.updates += 1
+	po.elapsed += delta
+	return false
+}
+
+func TestSetRegionUpdateRate(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 4, 4)
+	near := &timedObject{TestPhysicalObject: TestPhysicalObject{1, 1, 1, 1}}
+	far := &timedObject{TestPhysicalObject: TestPhysicalObject{5, 1, 1, 1}}
+	frozen := &timedObject{TestPhysicalObject: TestPhysicalObject{5, 5, 1, 1}}
+	for _, obj := range []quadtree.PhysicalObject{near, far, frozen} {
+		qt.Insert(obj)
+	}
+	qt.SetRegionUpdateRate(&quadtree.Bounds{4, 0, 4, 8}, 3)
+	qt.SetRegionActive(&quadtree.Bounds{4, 4, 4, 4}, false)
+
+	for i := 0; i < 7; i += 1 {
+		qt.Update(time.Millisecond)
+	}
+	if near.updates != 7 || near.elapsed != 7*time.Millisecond {
+		t.Errorf("Active objects expect every Update, got %v updates for %v", near.updates, near.elapsed)
+	}
+	if far.updates != 2 || far.elapsed != 6*time.Millisecond {
+		t.Errorf("Throttled objects expect every third Update with the elapsed time, got %v updates for %v", far.updates, far.elapsed)
+	}
+	if frozen.updates != 0 {
+		t.Errorf("Inactive objects expect no Update, got %v", frozen.updates)
+	}
+
+	qt.SetRegionActive(&quadtree.Bounds{4, 0, 4, 8}, true)
+	qt.Update(time.Millisecond)
+	if far.updates != 3 || far.elapsed != 8*time.Millisecond {
+		t.Errorf("Reactivated objects expect the time they skipped, got %v updates for %v", far.updates, far.elapsed)
+	}
+	if frozen.updates != 0 {
+		t.Errorf("Inactive objects expect no Update, got %v", frozen.updates)
+	}
+}
+
+func TestSetUpdateInterval(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 4)
+	near := &timedObject{TestPhysicalObject: TestPhysicalObject{1, 1, 1, 1}}
+	far := &timedObject{TestPhysicalObject: TestPhysicalObject{5, 1, 1, 1}}
+	other := &timedObject{TestPhysicalObject: TestPhysicalObject{5, 5, 1, 1}}
+	runner := &steeredObject{TestPhysicalObject: TestPhysicalObject{0, 0, 1, 1}}
+	for _, obj := range []quadtree.PhysicalObject{near, far, other, runner} {
+		qt.Insert(obj)
+	}
+	qt.SetUpdateInterval(far, 4)
+	qt.SetUpdateInterval(other, 4)
+	qt.SetUpdateInterval(runner, 2)
+
+	for i := 0; i < 8; i += 1 {
+		qt.Update(time.Millisecond)
+	}
+	if near.updates != 8 {
+		t.Errorf("expects objects without interval to get every Update, got %d", near.updates)
+	}
+	if far.updates != 2 || far.elapsed != 8*time.Millisecond || other.updates != 2 {
+		t.Errorf("expects every fourth Update with the elapsed time, got %d updates for %v and %d", far.updates, far.elapsed, other.updates)
+	}
+
+	// objects of the same interval are spread across Updates
+	qt.Update(time.Millisecond)
+	if far.updates+other.updates != 5 {
+		t.Errorf("expects one of the objects to be due, got %d and %d", far.updates, other.updates)
+	}
+
+	runner.moveTo(6, 6)
+	qt.Update(time.Millisecond)
+	qt.Update(time.Millisecond)
+	if node := qt.FindObject(runner); node == nil || !node.Contains(runner) {
+		t.Errorf("expects the moved object to be relocated once due, got %v", node)
+	}
+
+	qt.SetUpdateInterval(far, 1)
+	before := far.updates
+	qt.Update(time.Millisecond)
+	if far.updates != before+1 {
+		t.Errorf("expects an interval of 1 to restore every Update, got %d", far.updates-before)
+	}
+
+	qt.Remove(other)
+	qt.Insert(other)
+	before = other.updates
+	qt.Update(time.Millisecond)
+	qt.Update(time.Millisecond)
+	if other.updates != before+2 {
+		t.Errorf("expects the interval to be dropped with the object, got %d", other.updates-before)
+	}
+}
+
+func TestSetUpdateIntervalSkipsTraversal(t *testing.T) {
+	visits := 0
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 4, quadtree.WithPrunePolicy(func(node *quadtree.Quadtree, idleTicks int) bool {
+		visits += 1
+		return false
+	}))
+	runner := &steeredObject{TestPhysicalObject: TestPhysicalObject{1, 1, 1, 1}}
+	other := &steeredObject{TestPhysicalObject: TestPhysicalObject{5, 5, 1, 1}}
+	qt.Insert(runner)
+	qt.Insert(other)
+	qt.Preallocate(2)
+	qt.SetUpdateInterval(runner, 2)
+	qt.SetUpdateInterval(other, 2)
+	qt.SetUpdateInterval(&TestPhysicalObject{0, 0, 1, 1}, 2)
+
+	runner.moveTo(5, 1)
+	for i := 0; i < 4; i += 1 {
+		qt.Update(time.Millisecond)
+	}
+	if visits != 0 {
+		t.Errorf("expects no traversal while every object has an interval, got %d visits of empty nodes", visits)
+	}
+	if node := qt.FindObject(runner); node == nil || !node.Contains(runner) || node.Level == 0 {
+		t.Errorf("expects the moved object to be relocated from its node, got %+v", node)
+	}
+	if got := qt.Retrieve(&quadtree.Bounds{4, 0, 4, 4}); !sameObjects(got, runner) {
+		t.Errorf("expects the moved object in its new quadrant, got %v", got)
+	}
+
+	// objects a rebuild leaves out lose their interval
+	objects := list.New()
+	objects.PushBack(other)
+	qt.UpdateTree(objects)
+	qt.Insert(runner)
+	runner.moveTo(1, 5)
+	qt.Update(time.Millisecond)
+	if node := qt.FindObject(runner); node == nil || !node.Contains(runner) {
+		t.Errorf("expects the object inserted again to get every Update, got %+v", node)
+	}
+}
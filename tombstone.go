@@ -0,0 +1,82 @@
+package quadtree
+
+// MarkRemoved flags the object as removed without changing the structure of the tree, so that it
+// is safe to call while iterating. Queries, Walk and Update skip the object from then on, until
+// CompactTombstones removes it for good or it is inserted again. Objects not in the tree are ignored
+func (qt *Quadtree) MarkRemoved(obj PhysicalObject) {
+	if !qt.ready() {
+		return
+	}
+	c := qt.m_config
+	obj = qt.canonical(obj)
+	if qt.root().FindObject(obj) == nil {
+		// not in the tree, or already flagged
+		return
+	}
+	if c.tombstones == nil {
+		c.tombstones = make(map[PhysicalObject]bool)
+	}
+	c.tombstones[obj] = true
+	c.aggregateEpoch += 1
+	c.expireBorrowed()
+	qt.logRemove(obj)
+	qt.notifyRemoved(obj, RemovedMarked)
+}
+
+// buried tells whether the object is flagged as removed
+func (qt *Quadtree) buried(obj PhysicalObject) bool {
+	return qt.m_config.tombstones != nil && qt.m_config.tombstones[obj]
+}
+
+// exhume removes a flagged object for good before it is inserted again
+func (qt *Quadtree) exhume(obj PhysicalObject) {
+	if len(qt.m_config.tombstones) == 0 {
+		return
+	}
+	if obj = qt.canonical(obj); qt.buried(obj) {
+		qt.root().remove(obj)
+		delete(qt.m_config.tombstones, obj)
+		qt.forget(obj)
+	}
+}
+
+// CompactTombstones removes every object flagged by MarkRemoved from the tree in a single traversal,
+// and returns how many were flagged
+func (qt *Quadtree) CompactTombstones() int {
+	if !qt.ready() || len(qt.m_config.tombstones) == 0 {
+		return 0
+	}
+	root := qt.root()
+	root.purge()
+	tombstones := root.m_config.tombstones
+	root.m_config.tombstones = nil
+	for obj := range tombstones {
+		root.forget(obj)
+	}
+	return len(tombstones)
+}
+
+// purge removes the flagged objects of the subtree
+func (qt *Quadtree) purge() {
+	for ele := qt.m_Objects.Front(); ele != nil; {
+		next := ele.Next()
+		if qt.buried(ele.Value.(PhysicalObject)) {
+			qt.m_Objects.Remove(ele)
+			qt.touch(-1)
+		}
+		ele = next
+	}
+
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			qt.Nodes[index].purge()
+			if qt.m_config.eagerCollapse {
+				qt.collapseChild(index)
+			}
+		}
+		flags >>= 1
+		index += 1
+	}
+}
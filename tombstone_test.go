@@ -0,0 +1,66 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestTombstones(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	dead := &TestPhysicalObject{1, 1, 1, 1}
+	alive := &TestPhysicalObject{1.5, 1.5, 1, 1}
+	qt.Insert(dead)
+	qt.Insert(alive)
+
+	qt.Walk(func(obj quadtree.PhysicalObject) {
+		if obj == dead {
+			qt.MarkRemoved(obj)
+		}
+	})
+	if found := qt.Retrieve(&quadtree.Bounds{0, 0, 4, 4}); len(found) != 1 || found[0] != alive {
+		t.Errorf("expects the removed object to be skipped by queries, got %v", found)
+	}
+	if qt.FindObject(dead) != nil || len(qt.GetIntersectedObjects(alive)) != 0 || qt.GetIntersection(nil, nil).Len() != 0 {
+		t.Errorf("expects the removed object to be skipped by lookups and intersections")
+	}
+	if objects, _ := qt.Size(); objects != 2 {
+		t.Errorf("expects the removed object to stay stored until compaction, got %d objects", objects)
+	}
+
+	if purged := qt.CompactTombstones(); purged != 1 {
+		t.Errorf("expects 1 tombstone to be purged, got %d", purged)
+	}
+	if objects, _ := qt.Size(); objects != 1 {
+		t.Errorf("expects 1 object left after compaction, got %d", objects)
+	}
+
+	qt.MarkRemoved(alive)
+	qt.Insert(alive)
+	if objects, _ := qt.Size(); objects != 1 || qt.FindObject(alive) == nil {
+		t.Errorf("expects inserting a removed object to bring it back once, got %d objects", objects)
+	}
+}
+
+func TestMarkRemovedIgnoresUnknownObjects(t *testing.T) {
+	var removed []quadtree.PhysicalObject
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithOnRemoved(func(obj quadtree.PhysicalObject, reason quadtree.RemoveReason) {
+		removed = append(removed, obj)
+	}))
+	stored := &TestPhysicalObject{1, 1, 1, 1}
+	qt.Insert(stored)
+
+	qt.MarkRemoved(&TestPhysicalObject{5, 5, 1, 1})
+	if len(removed) != 0 {
+		t.Errorf("expects no callback for an object never in the tree, got %v", removed)
+	}
+	if purged := qt.CompactTombstones(); purged != 0 {
+		t.Errorf("expects no tombstone for an object never in the tree, got %d", purged)
+	}
+
+	qt.MarkRemoved(stored)
+	qt.MarkRemoved(stored)
+	if len(removed) != 1 || removed[0] != stored {
+		t.Errorf("expects a single callback for the stored object, got %v", removed)
+	}
+}
@@ -0,0 +1,40 @@
+package quadtree
+
+import (
+	"errors"
+)
+
+var (
+	// ErrObjectNotFound is returned by Transfer for objects missing from the source tree
+	ErrObjectNotFound = errors.New("quadtree: object not in the tree")
+	// ErrOutOfBounds is returned by Transfer for objects the destination tree does not contain
+	ErrOutOfBounds = errors.New("quadtree: object out of the bounds of the tree")
+)
+
+// Transfer moves the object from one tree to another, e.g. between the trees of neighbouring zones.
+// The object is inserted into to before being removed from from, so that it is indexed by one of the
+// trees at all times, and it stays in from if any check fails: it must be stored in from and lie
+// within the bounds of to. Transferring an object to the tree it is in does nothing. The trees report
+// the move to their options as they would a Remove and an Insert
+func Transfer(obj PhysicalObject, from, to *Quadtree) error {
+	if !from.ready() || !to.ready() {
+		return ErrNilQuadtree
+	}
+	if from.root().FindObject(obj) == nil {
+		return ErrObjectNotFound
+	}
+	if from.root() == to.root() {
+		return nil
+	}
+	if !validCoordinates(obj) {
+		return ErrInvalidCoordinates
+	}
+	if !to.root().Bounds.Contains(obj) {
+		return ErrOutOfBounds
+	}
+	if err := to.root().Insert(obj); err != nil {
+		return err
+	}
+	from.root().Remove(obj)
+	return nil
+}
@@ -0,0 +1,44 @@
+package quadtree_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestTransfer(t *testing.T) {
+	west := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 3)
+	east := quadtree.NewQuadtree(&quadtree.Bounds{8, 0, 8, 8}, 1, 3)
+	obj := &TestPhysicalObject{6, 1, 1, 1}
+	west.Insert(obj)
+
+	if err := quadtree.Transfer(obj, west, east); err != quadtree.ErrOutOfBounds {
+		t.Errorf("expects ErrOutOfBounds, got %v", err)
+	}
+	if west.FindObject(obj) == nil {
+		t.Error("expects the object to stay in the source tree after a failure")
+	}
+
+	obj.x = 9
+	if err := quadtree.Transfer(obj, west, east); err != nil {
+		t.Fatal(err)
+	}
+	if west.FindObject(obj) != nil || east.FindObject(obj) == nil {
+		t.Error("expects the object to move to the destination tree")
+	}
+	if err := quadtree.Transfer(obj, west, east); err != quadtree.ErrObjectNotFound {
+		t.Errorf("expects ErrObjectNotFound, got %v", err)
+	}
+	if err := quadtree.Transfer(obj, east, east); err != nil || east.FindObject(obj) == nil {
+		t.Errorf("expects a transfer within a tree to do nothing, got %v", err)
+	}
+
+	obj.x = math.NaN()
+	if err := quadtree.Transfer(obj, east, west); err != quadtree.ErrInvalidCoordinates {
+		t.Errorf("expects ErrInvalidCoordinates, got %v", err)
+	}
+	if err := quadtree.Transfer(obj, nil, west); err != quadtree.ErrNilQuadtree {
+		t.Errorf("expects ErrNilQuadtree, got %v", err)
+	}
+}
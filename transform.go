@@ -0,0 +1,300 @@
+package quadtree
+
+import (
+	"container/list"
+	"time"
+)
+
+// Transform is an affine map from world to tree coordinates, scaling then translating every axis:
+// tree = world*Scale + Offset. A negative scale mirrors its axis, such as a Y axis growing upward
+type Transform struct {
+	ScaleX, ScaleY   float64
+	OffsetX, OffsetY float64
+}
+
+// NormalizingTransform returns the transform mapping world onto the unit square, its corner at (0, 0)
+// and its opposite corner at (1, 1)
+func NormalizingTransform(world *Bounds) Transform {
+	return Transform{
+		ScaleX:  1 / world.Width,
+		ScaleY:  1 / world.Height,
+		OffsetX: -world.X / world.Width,
+		OffsetY: -world.Y / world.Height,
+	}
+}
+
+// span maps the span from v to v + size by scale and offset
+func span(v, size, scale, offset float64) (float64, float64) {
+	if scale < 0 {
+		return (v+size)*scale + offset, -size * scale
+	}
+	return v*scale + offset, size * scale
+}
+
+// Apply maps world bounds to tree coordinates
+func (t Transform) Apply(b *Bounds) Bounds {
+	x, width := span(b.X, b.Width, t.ScaleX, t.OffsetX)
+	y, height := span(b.Y, b.Height, t.ScaleY, t.OffsetY)
+	return Bounds{x, y, width, height}
+}
+
+// Invert maps bounds in tree coordinates back to world coordinates
+func (t Transform) Invert(b *Bounds) Bounds {
+	x, width := span(b.X, b.Width, 1/t.ScaleX, -t.OffsetX/t.ScaleX)
+	y, height := span(b.Y, b.Height, 1/t.ScaleY, -t.OffsetY/t.ScaleY)
+	return Bounds{x, y, width, height}
+}
+
+// proxy stands in the tree for an object of a TransformedQuadtree, in tree coordinates
+type proxy struct {
+	obj       PhysicalObject
+	transform *Transform
+}
+
+func (p *proxy) X() float64 {
+	x, _ := span(p.obj.X(), p.obj.Width(), p.transform.ScaleX, p.transform.OffsetX)
+	return x
+}
+
+func (p *proxy) Y() float64 {
+	y, _ := span(p.obj.Y(), p.obj.Height(), p.transform.ScaleY, p.transform.OffsetY)
+	return y
+}
+
+func (p *proxy) Width() float64 {
+	_, width := span(p.obj.X(), p.obj.Width(), p.transform.ScaleX, p.transform.OffsetX)
+	return width
+}
+
+func (p *proxy) Height() float64 {
+	_, height := span(p.obj.Y(), p.obj.Height(), p.transform.ScaleY, p.transform.OffsetY)
+	return height
+}
+
+func (p *proxy) Update(delta time.Duration) bool {
+	return p.obj.Update(delta)
+}
+
+// TransformedQuadtree takes objects and regions in world units and stores them in a Quadtree in tree
+// coordinates, such as the unit square of NormalizingTransform or a Y axis mirrored by a negative
+// scale, mapping them through the transform on every call. The tree stores proxies of the objects: the
+// methods of TransformedQuadtree and WithOnRemoved see the objects of the world, while the other
+// options taking callbacks and the methods of Tree see the proxies, World returns the object behind one
+type TransformedQuadtree struct {
+	tree      *Quadtree
+	transform *Transform
+	proxies   map[PhysicalObject]*proxy
+}
+
+// NewTransformedQuadtree returns a tree covering world, stored in the unit square
+func NewTransformedQuadtree(world *Bounds, maxObjectsBeforeSplit, maxLevelsToSplit int, opts ...Option) *TransformedQuadtree {
+	return NewTransformedQuadtreeWith(NormalizingTransform(world), world, maxObjectsBeforeSplit, maxLevelsToSplit, opts...)
+}
+
+// NewTransformedQuadtreeWith returns a tree covering world, stored in the coordinates given by transform
+func NewTransformedQuadtreeWith(transform Transform, world *Bounds, maxObjectsBeforeSplit, maxLevelsToSplit int, opts ...Option) *TransformedQuadtree {
+	tq := &TransformedQuadtree{
+		transform: &transform,
+		proxies:   make(map[PhysicalObject]*proxy),
+	}
+	bounds := transform.Apply(world)
+	tq.tree = NewQuadtree(&bounds, maxObjectsBeforeSplit, maxLevelsToSplit, opts...)
+	// proxies of the objects the tree drops by itself are released too
+	c := tq.tree.m_config
+	onRemoved := c.onRemoved
+	c.onRemoved = func(obj PhysicalObject, reason RemoveReason) {
+		world := tq.World(obj)
+		delete(tq.proxies, world)
+		if onRemoved != nil {
+			onRemoved(world, reason)
+		}
+	}
+	return tq
+}
+
+// Tree returns the tree in tree coordinates
+func (tq *TransformedQuadtree) Tree() *Quadtree {
+	return tq.tree
+}
+
+// Transform returns the transform from world to tree coordinates
+func (tq *TransformedQuadtree) Transform() Transform {
+	return *tq.transform
+}
+
+// World returns the object of the world behind a proxy stored in the tree, obj itself if it is not a proxy
+func (tq *TransformedQuadtree) World(obj PhysicalObject) PhysicalObject {
+	if p, ok := obj.(*proxy); ok {
+		return p.obj
+	}
+	return obj
+}
+
+// worlds replaces the proxies of objects by their objects of the world
+func (tq *TransformedQuadtree) worlds(objects IntersectedObjects) IntersectedObjects {
+	for i, obj := range objects {
+		objects[i] = tq.World(obj)
+	}
+	return objects
+}
+
+// Insert adds an object whose dimensions are in world units
+func (tq *TransformedQuadtree) Insert(obj PhysicalObject) error {
+	p := tq.proxies[obj]
+	if p == nil {
+		p = &proxy{obj: obj, transform: tq.transform}
+	}
+	if err := tq.tree.Insert(p); err != nil {
+		return err
+	}
+	tq.proxies[obj] = p
+	return nil
+}
+
+// Remove removes an object from the tree
+func (tq *TransformedQuadtree) Remove(obj PhysicalObject) bool {
+	p := tq.proxies[obj]
+	return p != nil && tq.tree.Remove(p)
+}
+
+// ID returns the ID the tree assigned to the object, false if it has none
+func (tq *TransformedQuadtree) ID(obj PhysicalObject) (uint64, bool) {
+	p := tq.proxies[obj]
+	if p == nil {
+		return 0, false
+	}
+	return tq.tree.ID(p)
+}
+
+// GetByID returns the object with the given ID, nil if there is none
+func (tq *TransformedQuadtree) GetByID(id uint64) PhysicalObject {
+	if obj := tq.tree.GetByID(id); obj != nil {
+		return tq.World(obj)
+	}
+	return nil
+}
+
+// RemoveByID removes the object with the given ID, and tells whether there was one
+func (tq *TransformedQuadtree) RemoveByID(id uint64) bool {
+	return tq.tree.RemoveByID(id)
+}
+
+// Tag attaches tags to an object of the tree, tags are dropped when the object is removed
+func (tq *TransformedQuadtree) Tag(obj PhysicalObject, tags ...string) {
+	if p := tq.proxies[obj]; p != nil {
+		tq.tree.Tag(p, tags...)
+	}
+}
+
+// Untag detaches tags from an object
+func (tq *TransformedQuadtree) Untag(obj PhysicalObject, tags ...string) {
+	if p := tq.proxies[obj]; p != nil {
+		tq.tree.Untag(p, tags...)
+	}
+}
+
+// HasTag tells whether the object carries the tag
+func (tq *TransformedQuadtree) HasTag(obj PhysicalObject, tag string) bool {
+	p := tq.proxies[obj]
+	return p != nil && tq.tree.HasTag(p, tag)
+}
+
+// RetrieveTagged returns the objects carrying tag overlapping a region in world units
+func (tq *TransformedQuadtree) RetrieveTagged(region *Bounds, tag string, opts ...QueryOption) IntersectedObjects {
+	b := tq.transform.Apply(region)
+	return tq.worlds(tq.tree.RetrieveTagged(&b, tag, opts...))
+}
+
+// InsertHandle inserts the object like Insert and returns its handle, see Quadtree.InsertHandle
+func (tq *TransformedQuadtree) InsertHandle(obj PhysicalObject) (Handle, error) {
+	p := tq.proxies[obj]
+	if p == nil {
+		p = &proxy{obj: obj, transform: tq.transform}
+	}
+	h, err := tq.tree.InsertHandle(p)
+	if err != nil {
+		return h, err
+	}
+	tq.proxies[obj] = p
+	return h, nil
+}
+
+// HandleObject returns the object of the handle, nil if the handle is not live
+func (tq *TransformedQuadtree) HandleObject(h Handle) PhysicalObject {
+	if obj := tq.tree.HandleObject(h); obj != nil {
+		return tq.World(obj)
+	}
+	return nil
+}
+
+// HandleBounds returns the bounds in world units recorded for the handle, as of the last Update or MoveHandle
+func (tq *TransformedQuadtree) HandleBounds(h Handle) (Bounds, bool) {
+	b, ok := tq.tree.HandleBounds(h)
+	if !ok {
+		return b, false
+	}
+	return tq.transform.Invert(&b), true
+}
+
+// MoveHandle tells the tree the object of the handle moved, see Quadtree.MoveHandle
+func (tq *TransformedQuadtree) MoveHandle(h Handle) bool {
+	return tq.tree.MoveHandle(h)
+}
+
+// RemoveHandle removes the object of the handle from the tree and frees the handle
+func (tq *TransformedQuadtree) RemoveHandle(h Handle) bool {
+	return tq.tree.RemoveHandle(h)
+}
+
+// Update updates the objects and moves them to the nodes of their new positions
+func (tq *TransformedQuadtree) Update(delta time.Duration) {
+	tq.tree.Update(delta)
+}
+
+// Walk calls walker with every object of the tree
+func (tq *TransformedQuadtree) Walk(walker func(PhysicalObject)) {
+	tq.tree.Walk(func(obj PhysicalObject) {
+		walker(tq.World(obj))
+	})
+}
+
+// Retrieve returns the objects overlapping a region in world units
+func (tq *TransformedQuadtree) Retrieve(region *Bounds, opts ...QueryOption) IntersectedObjects {
+	b := tq.transform.Apply(region)
+	return tq.worlds(tq.tree.Retrieve(&b, opts...))
+}
+
+// GetIntersectedObjects returns the objects intersecting an object of the tree
+func (tq *TransformedQuadtree) GetIntersectedObjects(obj PhysicalObject) IntersectedObjects {
+	p := tq.proxies[obj]
+	if p == nil {
+		return nil
+	}
+	return tq.worlds(tq.tree.GetIntersectedObjects(p))
+}
+
+// GetIntersection appends to intersections the intersecting pairs of objects of the tree, as
+// Quadtree.GetIntersection does, with the objects of the world. Objects of potentialObjects are
+// objects of the world too
+func (tq *TransformedQuadtree) GetIntersection(intersections *list.List, potentialObjects *list.List) *list.List {
+	if intersections == nil {
+		intersections = &list.List{}
+	}
+	var potential *list.List
+	if potentialObjects != nil {
+		potential = &list.List{}
+		for ele := potentialObjects.Front(); ele != nil; ele = ele.Next() {
+			obj := ele.Value.(PhysicalObject)
+			if p := tq.proxies[obj]; p != nil {
+				potential.PushBack(p)
+			} else {
+				potential.PushBack(&proxy{obj: obj, transform: tq.transform})
+			}
+		}
+	}
+	for ele := tq.tree.GetIntersection(nil, potential).Front(); ele != nil; ele = ele.Next() {
+		record := ele.Value.(*IntersectionRecord)
+		intersections.PushBack(&IntersectionRecord{One: tq.World(record.One), Another: tq.World(record.Another)})
+	}
+	return intersections
+}
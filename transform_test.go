@@ -0,0 +1,108 @@
+package quadtree_test
+
+import (
+	"container/list"
+	"math"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestTransformedQuadtree(t *testing.T) {
+	world := &quadtree.Bounds{1e7, -3e7, 4096, 4096}
+	var removed []quadtree.PhysicalObject
+	tq := quadtree.NewTransformedQuadtree(world, 1, 12, quadtree.WithOnRemoved(func(obj quadtree.PhysicalObject, _ quadtree.RemoveReason) {
+		removed = append(removed, obj)
+	}))
+	if b := *tq.Tree().Bounds; b != (quadtree.Bounds{0, 0, 1, 1}) {
+		t.Errorf("expects the tree to cover the unit square, got %v", b)
+	}
+
+	a := &TestPhysicalObject{1e7 + 1, -3e7 + 1, 1, 1}
+	b := &TestPhysicalObject{1e7 + 1.5, -3e7 + 1.5, 1, 1}
+	far := &TestPhysicalObject{1e7 + 4000, -3e7 + 4000, 2, 2}
+	for _, obj := range []*TestPhysicalObject{a, b, far} {
+		if err := tq.Insert(obj); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if found := tq.Retrieve(&quadtree.Bounds{1e7, -3e7, 3, 3}); !sameObjects(found, a, b) {
+		t.Errorf("expects a region in world units to find the near objects, got %v", found)
+	}
+	if found := tq.GetIntersectedObjects(a); !sameObjects(found, b) {
+		t.Errorf("expects a to intersect b, got %v", found)
+	}
+	if pairs := tq.GetIntersection(nil, nil); pairs.Len() != 1 || !sameObjects(pairObjects(pairs.Front()), a, b) {
+		t.Errorf("expects a single pair of a and b, got %v pairs", pairs.Len())
+	}
+	var walked []quadtree.PhysicalObject
+	tq.Walk(func(obj quadtree.PhysicalObject) { walked = append(walked, obj) })
+	if !sameObjects(walked, a, b, far) {
+		t.Errorf("expects Walk to visit the objects of the world, got %v", walked)
+	}
+
+	if !tq.Remove(far) || tq.Remove(far) {
+		t.Errorf("expects far to be removed once")
+	}
+	if len(removed) != 1 || removed[0] != far {
+		t.Errorf("expects the removal of far to be reported, got %v", removed)
+	}
+	far.x -= 3000
+	tq.Insert(far)
+	tq.Update(0)
+	if found := tq.Retrieve(&quadtree.Bounds{1e7 + 999, -3e7 + 3999, 2, 2}); !sameObjects(found, far) {
+		t.Errorf("expects far to be found at its new position, got %v", found)
+	}
+}
+
+func pairObjects(ele *list.Element) []quadtree.PhysicalObject {
+	record := ele.Value.(*quadtree.IntersectionRecord)
+	return []quadtree.PhysicalObject{record.One, record.Another}
+}
+
+func TestTransformedQuadtreeKeys(t *testing.T) {
+	world := &quadtree.Bounds{1e7, -3e7, 4096, 4096}
+	tq := quadtree.NewTransformedQuadtree(world, 1, 12, quadtree.WithIDs())
+	a := &TestPhysicalObject{1e7 + 1, -3e7 + 1, 1, 1}
+	b := &TestPhysicalObject{1e7 + 1.5, -3e7 + 1.5, 1, 1}
+	tq.Insert(a)
+	h, err := tq.InsertHandle(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, ok := tq.ID(a)
+	if !ok || tq.GetByID(id) != a {
+		t.Errorf("expects IDs to be keyed by the objects of the world, got %v %v", id, ok)
+	}
+	tq.Tag(a, "player")
+	if !tq.HasTag(a, "player") || !sameObjects(tq.RetrieveTagged(world, "player"), a) {
+		t.Errorf("expects tags to be keyed by the objects of the world")
+	}
+	if got, ok := tq.HandleBounds(h); !ok || tq.HandleObject(h) != b || math.Abs(got.X-b.x) > 1e-6 || math.Abs(got.Width-1) > 1e-6 {
+		t.Errorf("expects handles to report the objects and bounds of the world, got %v", got)
+	}
+
+	potential := list.New()
+	potential.PushBack(&TestPhysicalObject{1e7 + 1.2, -3e7 + 1.2, 1, 1})
+	if pairs := tq.GetIntersection(nil, potential); pairs.Len() != 3 {
+		t.Errorf("expects the potential objects of the world to be tested too, got %v pairs", pairs.Len())
+	}
+
+	if !tq.RemoveByID(id) || tq.HasTag(a, "player") || !tq.RemoveHandle(h) {
+		t.Errorf("expects the objects to be removed along with their tags")
+	}
+}
+
+func TestTransformMirror(t *testing.T) {
+	// Y grows upward in the world and downward in the tree
+	transform := quadtree.Transform{ScaleX: 2, ScaleY: -2, OffsetX: 1, OffsetY: 10}
+	world := quadtree.Bounds{1, 1, 2, 3}
+	tree := transform.Apply(&world)
+	if tree != (quadtree.Bounds{3, 2, 4, 6}) {
+		t.Errorf("expects the bounds to be mirrored, got %v", tree)
+	}
+	if back := transform.Invert(&tree); back != world {
+		t.Errorf("expects Invert to undo Apply, got %v", back)
+	}
+}
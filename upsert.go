@@ -0,0 +1,95 @@
+package quadtree
+
+// InsertOrMove relocates the object if it is already in the tree, and inserts it otherwise.
+// Objects with NaN or infinite coordinates are handled according to the InvalidCoordinatesPolicy of the tree
+func (qt *Quadtree) InsertOrMove(obj PhysicalObject) error {
+	if !qt.ready() {
+		return ErrNilQuadtree
+	}
+	root := qt.root()
+	if root.m_config.straddlePolicy == StraddleDuplicate {
+		if root.removeCopies(obj) != nil {
+			root.logMove(obj)
+		}
+		return root.Insert(obj)
+	}
+
+	var node *Quadtree
+	if store := root.m_config.handles; store != nil {
+		if h, ok := store.handles[root.canonical(obj)]; ok {
+			slot := store.bounds[4*h : 4*h+4]
+			node = root.locate(&flatObject{slot[0], slot[1], slot[2], slot[3]}, obj)
+			store.record(h, obj)
+		}
+	}
+	if node == nil {
+		// objects which did not leave their node are found along their bounds
+		node = root.locate(obj, obj)
+	}
+	if node != nil {
+		root.logMove(obj)
+		err := node.relocate(obj)
+		root.enforceCapacity()
+		return err
+	}
+	node, stored := root.take(obj)
+	if node == nil {
+		return root.Insert(obj)
+	}
+	root.logMove(obj)
+	root.rekey(stored, obj)
+	err := node.place(obj)
+	root.enforceCapacity()
+	return err
+}
+
+// take removes the object equal to target from the subtree in a single traversal, and returns the
+// node which stored it along with the stored object, nil if it is not in the subtree
+func (qt *Quadtree) take(target PhysicalObject) (*Quadtree, PhysicalObject) {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if one := ele.Value.(PhysicalObject); qt.same(one, target) && !qt.buried(one) {
+			qt.m_Objects.Remove(ele)
+			qt.touch(-1)
+			return qt, one
+		}
+	}
+	flags := qt.m_ActiveNodes
+	index := 0
+	for flags > 0 {
+		if flags&1 == 1 {
+			if node, one := qt.Nodes[index].take(target); node != nil {
+				return node, one
+			}
+		}
+		flags >>= 1
+		index += 1
+	}
+	return nil, nil
+}
+
+// relocate moves obj, stored in current node, to the node it belongs to now. An equal object stored
+// in its place is replaced by obj, which takes over its ID, tags and handle
+func (qt *Quadtree) relocate(obj PhysicalObject) error {
+	for ele := qt.m_Objects.Front(); ele != nil; ele = ele.Next() {
+		if stored := ele.Value.(PhysicalObject); qt.same(stored, obj) {
+			qt.m_Objects.Remove(ele)
+			qt.touch(-1)
+			qt.rekey(stored, obj)
+			break
+		}
+	}
+	return qt.place(obj)
+}
+
+// place inserts obj, just removed from current node, into the closest node containing it
+func (qt *Quadtree) place(obj PhysicalObject) error {
+	if !validCoordinates(obj) {
+		return qt.handleInvalid(obj)
+	}
+	container := qt
+	for !container.contains(obj) && container.m_parent != nil {
+		container = container.m_parent
+	}
+	container.insert(obj)
+	return nil
+}
@@ -0,0 +1,55 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestInsertOrMove(t *testing.T) {
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	entity := &TestPhysicalObject{1, 1, 1, 1}
+	qt.Insert(&TestPhysicalObject{6, 6, 1, 1})
+
+	if err := qt.InsertOrMove(entity); err != nil || qt.FindObject(entity) != qt.Nodes[0] {
+		t.Fatalf("expects the entity to be inserted into the top left quadrant, got %v", err)
+	}
+	entity.x, entity.y = 6, 1
+	if err := qt.InsertOrMove(entity); err != nil || qt.FindObject(entity) != qt.Nodes[1] {
+		t.Errorf("expects the entity to move into the top right quadrant, got %v", err)
+	}
+	count := 0
+	qt.Walk(func(quadtree.PhysicalObject) { count += 1 })
+	if count != 2 {
+		t.Errorf("expects the entity to be stored once, got %d objects", count)
+	}
+}
+
+func TestInsertOrMoveEqual(t *testing.T) {
+	sameEntity := func(a, b quadtree.PhysicalObject) bool {
+		ea, ok := a.(*entityView)
+		eb, ok2 := b.(*entityView)
+		return ok && ok2 && ea.id == eb.id
+	}
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithEqual(sameEntity), quadtree.WithIDs())
+	stored := &entityView{TestPhysicalObject{1, 1, 1, 1}, 7}
+	qt.Insert(stored)
+	qt.Insert(&entityView{TestPhysicalObject{6, 6, 1, 1}, 8})
+	qt.Tag(stored, "player")
+	id, _ := qt.ID(stored)
+
+	// a new view of the entity in another quadrant replaces the stored one
+	view := &entityView{TestPhysicalObject{6, 1, 1, 1}, 7}
+	if err := qt.InsertOrMove(view); err != nil || qt.FindObject(view) != qt.Nodes[1] {
+		t.Fatalf("expects the entity to move into the top right quadrant, got %v", err)
+	}
+	if got, ok := qt.ID(view); !ok || got != id || qt.GetByID(id) != view {
+		t.Errorf("expects the new view to take over the ID %d, got %d", id, got)
+	}
+	if !qt.HasTag(view, "player") {
+		t.Errorf("expects the new view to take over the tags")
+	}
+	if objects, _ := qt.Size(); objects != 2 {
+		t.Errorf("expects the entity to be stored once, got %d objects", objects)
+	}
+}
@@ -0,0 +1,191 @@
+// Package valuetree is a quadtree storing small items by value in node slices, rather than as
+// PhysicalObject interfaces, so that indexing many particles costs no allocation per item.
+// Items are motionless, moving one is a Remove followed by an Insert
+package valuetree
+
+import (
+	"github.com/gmlewis/quadtree"
+)
+
+// Item is an axis aligned box stored by value, ID identifies it to the caller
+type Item struct {
+	X, Y, Width, Height float64
+	ID                  int
+}
+
+// bounds returns the bounding box of the item
+func (item *Item) bounds() *quadtree.Bounds {
+	return &quadtree.Bounds{X: item.X, Y: item.Y, Width: item.Width, Height: item.Height}
+}
+
+// node of the tree, children are indexes into the nodes of the tree, 0 for none since the root is never a child
+type node struct {
+	bounds   quadtree.Bounds
+	level    int
+	items    []Item
+	children [4]int32
+}
+
+// Tree is a quadtree of items stored by value, its nodes live in a single slice
+type Tree struct {
+	nodes      []node
+	maxObjects int
+	maxLevels  int
+	count      int
+}
+
+// New creates an empty tree, nodes split when holding more than maxObjects items, up to maxLevels levels deep
+func New(bounds *quadtree.Bounds, maxObjects, maxLevels int) *Tree {
+	return &Tree{
+		nodes:      []node{{bounds: *bounds}},
+		maxObjects: maxObjects,
+		maxLevels:  maxLevels,
+	}
+}
+
+// Len returns the number of items in the tree
+func (t *Tree) Len() int {
+	return t.count
+}
+
+// Reset removes every item, keeping the allocated storage
+func (t *Tree) Reset() {
+	root := t.nodes[0]
+	root.items = root.items[:0]
+	root.children = [4]int32{}
+	t.nodes = append(t.nodes[:0], root)
+	t.count = 0
+}
+
+// quadrant returns the index of the quadrant of n fully containing the box, -1 if there is none
+func (n *node) quadrant(b *quadtree.Bounds) int {
+	mx, my := n.bounds.Center()
+	top := b.Y >= n.bounds.Y && b.Y+b.Height <= my
+	bottom := b.Y >= my && b.Y+b.Height <= n.bounds.Y+n.bounds.Height
+	left := b.X >= n.bounds.X && b.X+b.Width <= mx
+	right := b.X >= mx && b.X+b.Width <= n.bounds.X+n.bounds.Width
+	switch {
+	case top && left:
+		return 0
+	case top && right:
+		return 1
+	case bottom && left:
+		return 2
+	case bottom && right:
+		return 3
+	}
+	return -1
+}
+
+// quadrantBounds returns the bounds of the quadrant of n at index
+func (n *node) quadrantBounds(index int) quadtree.Bounds {
+	w, h := n.bounds.Width/2, n.bounds.Height/2
+	b := quadtree.Bounds{X: n.bounds.X, Y: n.bounds.Y, Width: w, Height: h}
+	if index&1 != 0 {
+		b.X += w
+	}
+	if index&2 != 0 {
+		b.Y += h
+	}
+	return b
+}
+
+// child returns the node index of the quadrant of the node at index, creating it if needed
+func (t *Tree) child(index, quadrant int) int {
+	if c := t.nodes[index].children[quadrant]; c != 0 {
+		return int(c)
+	}
+	t.nodes = append(t.nodes, node{
+		bounds: t.nodes[index].quadrantBounds(quadrant),
+		level:  t.nodes[index].level + 1,
+	})
+	c := len(t.nodes) - 1
+	t.nodes[index].children[quadrant] = int32(c)
+	return c
+}
+
+// Insert adds the item to the tree
+func (t *Tree) Insert(item Item) {
+	t.count += 1
+	index := 0
+	for {
+		n := &t.nodes[index]
+		if n.children == [4]int32{} {
+			n.items = append(n.items, item)
+			if len(n.items) > t.maxObjects && n.level < t.maxLevels {
+				t.split(index)
+			}
+			return
+		}
+		quadrant := n.quadrant(item.bounds())
+		if quadrant == -1 {
+			n.items = append(n.items, item)
+			return
+		}
+		index = t.child(index, quadrant)
+	}
+}
+
+// split moves the items of the leaf at index fitting in one of its quadrants down into that quadrant
+func (t *Tree) split(index int) {
+	items := t.nodes[index].items
+	kept := items[:0]
+	var moved []Item
+	for _, item := range items {
+		if t.nodes[index].quadrant(item.bounds()) == -1 {
+			kept = append(kept, item)
+		} else {
+			moved = append(moved, item)
+		}
+	}
+	t.nodes[index].items = kept
+	for _, item := range moved {
+		c := t.child(index, t.nodes[index].quadrant(item.bounds()))
+		t.nodes[c].items = append(t.nodes[c].items, item)
+	}
+	for quadrant := 0; quadrant < 4; quadrant += 1 {
+		if c := int(t.nodes[index].children[quadrant]); c != 0 && len(t.nodes[c].items) > t.maxObjects && t.nodes[c].level < t.maxLevels {
+			t.split(c)
+		}
+	}
+}
+
+// Remove removes the item with the same ID as item, which must have the bounds it was inserted with
+func (t *Tree) Remove(item Item) bool {
+	index := 0
+	for {
+		n := &t.nodes[index]
+		for i := range n.items {
+			if n.items[i].ID == item.ID {
+				n.items = append(n.items[:i], n.items[i+1:]...)
+				t.count -= 1
+				return true
+			}
+		}
+		quadrant := n.quadrant(item.bounds())
+		if quadrant == -1 || n.children[quadrant] == 0 {
+			return false
+		}
+		index = int(n.children[quadrant])
+	}
+}
+
+// Retrieve appends to dst the items overlapping region, touching borders count as overlap
+func (t *Tree) Retrieve(region *quadtree.Bounds, dst []Item) []Item {
+	return t.retrieve(0, region, dst)
+}
+
+func (t *Tree) retrieve(index int, region *quadtree.Bounds, dst []Item) []Item {
+	n := &t.nodes[index]
+	for i := range n.items {
+		if region.Intersects(n.items[i].bounds()) {
+			dst = append(dst, n.items[i])
+		}
+	}
+	for _, c := range n.children {
+		if c != 0 && t.nodes[c].bounds.Intersects(region) {
+			dst = t.retrieve(int(c), region, dst)
+		}
+	}
+	return dst
+}
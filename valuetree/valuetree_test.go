@@ -0,0 +1,55 @@
+package valuetree
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tree := New(&quadtree.Bounds{X: 0, Y: 0, Width: 100, Height: 100}, 4, 8)
+	var items []Item
+	for id := 0; id < 500; id += 1 {
+		item := Item{X: rng.Float64() * 98, Y: rng.Float64() * 98, Width: rng.Float64() * 2, Height: rng.Float64() * 2, ID: id}
+		items = append(items, item)
+		tree.Insert(item)
+	}
+	for _, item := range items[:100] {
+		if !tree.Remove(item) {
+			t.Fatalf("expects item %d to be removed", item.ID)
+		}
+	}
+	items = items[100:]
+	if tree.Len() != len(items) {
+		t.Errorf("expects %d items, got %d", len(items), tree.Len())
+	}
+
+	region := &quadtree.Bounds{X: 20, Y: 30, Width: 25, Height: 15}
+	var expected []int
+	for _, item := range items {
+		if region.Intersects(item.bounds()) {
+			expected = append(expected, item.ID)
+		}
+	}
+	var got []int
+	for _, item := range tree.Retrieve(region, nil) {
+		got = append(got, item.ID)
+	}
+	sort.Ints(got)
+	if len(got) != len(expected) {
+		t.Fatalf("expects items %v, got %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("expects items %v, got %v", expected, got)
+		}
+	}
+
+	tree.Reset()
+	if tree.Len() != 0 || len(tree.Retrieve(region, nil)) != 0 {
+		t.Errorf("expects an empty tree after Reset")
+	}
+}
@@ -0,0 +1,238 @@
+package quadtree
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// LogVersion is the version of the log format written WithLog
+const LogVersion = 1
+
+// ErrLogVersion is returned by Replay for logs written by a newer release
+var ErrLogVersion = errors.New("quadtree: unsupported log version")
+
+// opLog writes the mutations of a tree to an append-only log, objects are referred to by log IDs
+type opLog struct {
+	w       io.Writer
+	encode  func(PhysicalObject) string
+	last    uint64
+	ids     map[PhysicalObject]uint64
+	started bool  // whether the version line was written
+	err     error // first write error
+}
+
+// WithLog makes the tree record every mutation to w, one line per mutation, after a line giving the
+// LogVersion of the format:
+//
+//	version VERSION
+//	insert ID OBJECT
+//	move ID OBJECT
+//	remove ID
+//	reset
+//
+// where OBJECT is the object as returned by encode, which must not contain line breaks.
+// The log can be read back by Replay to reconstruct the tree
+func WithLog(w io.Writer, encode func(PhysicalObject) string) Option {
+	return func(c *config) {
+		c.log = &opLog{
+			w:      w,
+			encode: encode,
+			ids:    make(map[PhysicalObject]uint64),
+		}
+	}
+}
+
+// LogError returns the first error met while writing the log, nil if there was none
+func (qt *Quadtree) LogError() error {
+	if !qt.ready() || qt.m_config.log == nil {
+		return nil
+	}
+	return qt.m_config.log.err
+}
+
+func (l *opLog) write(op string, id uint64, obj PhysicalObject) {
+	line := op + " " + strconv.FormatUint(id, 10)
+	if obj != nil {
+		line += " " + l.encode(obj)
+	}
+	l.writeLine(line)
+}
+
+// writeLine writes a line to the log, preceded by the version line if it is the first one
+func (l *opLog) writeLine(line string) {
+	if l.err != nil {
+		return
+	}
+	if !l.started {
+		l.started = true
+		line = "version " + strconv.Itoa(LogVersion) + "\n" + line
+	}
+	_, l.err = io.WriteString(l.w, line+"\n")
+}
+
+// logInsert records an object entering the tree, unless it is already in the log, history or recording
+func (qt *Quadtree) logInsert(obj PhysicalObject) {
+	if p := qt.m_config.pairs; p != nil {
+		p.changed(obj)
+	}
+	if h := qt.m_config.history; h != nil {
+		h.inserted(obj)
+	}
+	if rec := qt.m_config.recorder; rec != nil {
+		rec.inserted(obj)
+	}
+	l := qt.m_config.log
+	if l == nil {
+		return
+	}
+	if _, ok := l.ids[obj]; ok {
+		return
+	}
+	l.last += 1
+	l.ids[obj] = l.last
+	l.write("insert", l.last, obj)
+}
+
+// logMove records an object of the tree which moved
+func (qt *Quadtree) logMove(obj PhysicalObject) {
+	qt.m_config.expireBorrowed()
+	if r := qt.m_config.results; r != nil {
+		r.moves += 1
+	}
+	if p := qt.m_config.pairs; p != nil {
+		p.changed(obj)
+	}
+	if h := qt.m_config.history; h != nil {
+		h.moved(obj)
+	}
+	if rec := qt.m_config.recorder; rec != nil {
+		rec.moved(obj)
+	}
+	l := qt.m_config.log
+	if l == nil {
+		return
+	}
+	if id, ok := l.ids[obj]; ok {
+		l.write("move", id, obj)
+	}
+}
+
+// logRemove records an object leaving the tree
+func (qt *Quadtree) logRemove(obj PhysicalObject) {
+	if p := qt.m_config.pairs; p != nil {
+		p.dropped(obj)
+	}
+	if h := qt.m_config.history; h != nil {
+		h.removed(obj)
+	}
+	if rec := qt.m_config.recorder; rec != nil {
+		rec.removed(obj)
+	}
+	l := qt.m_config.log
+	if l == nil {
+		return
+	}
+	if id, ok := l.ids[obj]; ok {
+		delete(l.ids, obj)
+		l.write("remove", id, nil)
+	}
+}
+
+// logRebuild records the objects of a rebuilt tree as a reset followed by their insertion, and clears the history
+func (qt *Quadtree) logRebuild() {
+	if q := qt.m_config.relocationQueue; q != nil {
+		q.reset()
+	}
+	if p := qt.m_config.pairs; p != nil {
+		p.rebuilt(qt.root())
+	}
+	if h := qt.m_config.history; h != nil {
+		h.reset()
+		h.replaying = true
+		qt.root().Walk(h.inserted)
+		h.replaying = false
+	}
+	if rec := qt.m_config.recorder; rec != nil {
+		rec.rebuilt(qt.root())
+	}
+	l := qt.m_config.log
+	if l == nil {
+		return
+	}
+	l.ids = make(map[PhysicalObject]uint64)
+	l.writeLine("reset")
+	qt.root().Walk(qt.logInsert)
+}
+
+// Replay reads a log written by a tree created WithLog, and applies its mutations to the tree.
+// decode turns the OBJECT part of the records back into objects. Logs of a newer LogVersion are
+// rejected with ErrLogVersion, logs written before the format was versioned are read as version 1
+func (qt *Quadtree) Replay(r io.Reader, decode func(string) (PhysicalObject, error)) error {
+	if !qt.ready() {
+		return ErrNilQuadtree
+	}
+	root := qt.root()
+	objects := make(map[uint64]PhysicalObject)
+
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber += 1 {
+		fields := strings.SplitN(scanner.Text(), " ", 3)
+		if fields[0] == "" {
+			continue
+		}
+		if fields[0] == "reset" {
+			for _, obj := range objects {
+				root.RemoveAll(obj)
+			}
+			objects = make(map[uint64]PhysicalObject)
+			continue
+		}
+		if len(fields) < 2 {
+			return fmt.Errorf("quadtree: log line %d: missing object ID", lineNumber)
+		}
+		if fields[0] == "version" {
+			version, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return fmt.Errorf("quadtree: log line %d: %v", lineNumber, err)
+			}
+			if version < 1 || version > LogVersion {
+				return ErrLogVersion
+			}
+			continue
+		}
+		id, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("quadtree: log line %d: %v", lineNumber, err)
+		}
+
+		switch fields[0] {
+		case "remove":
+			if obj, ok := objects[id]; ok {
+				root.RemoveAll(obj)
+				delete(objects, id)
+			}
+		case "insert", "move":
+			if len(fields) < 3 {
+				return fmt.Errorf("quadtree: log line %d: missing object", lineNumber)
+			}
+			obj, err := decode(fields[2])
+			if err != nil {
+				return fmt.Errorf("quadtree: log line %d: %v", lineNumber, err)
+			}
+			if old, ok := objects[id]; ok {
+				root.RemoveAll(old)
+			}
+			objects[id] = obj
+			if err := root.Insert(obj); err != nil && err != ErrDuplicateObject {
+				return fmt.Errorf("quadtree: log line %d: %v", lineNumber, err)
+			}
+		default:
+			return fmt.Errorf("quadtree: log line %d: unknown record %q", lineNumber, fields[0])
+		}
+	}
+	return scanner.Err()
+}
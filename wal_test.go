@@ -0,0 +1,66 @@
+package quadtree_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func encodeObject(obj quadtree.PhysicalObject) string {
+	return fmt.Sprint(obj.X(), obj.Y(), obj.Width(), obj.Height())
+}
+
+func decodeObject(s string) (quadtree.PhysicalObject, error) {
+	obj := &TestPhysicalObject{}
+	_, err := fmt.Sscan(s, &obj.x, &obj.y, &obj.width, &obj.height)
+	return obj, err
+}
+
+func TestLogReplay(t *testing.T) {
+	var log bytes.Buffer
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10, quadtree.WithLog(&log, encodeObject))
+	a := &TestPhysicalObject{1, 1, 1, 1}
+	b := &TestPhysicalObject{6, 6, 1, 1}
+	c := &TestPhysicalObject{6, 1, 1, 1}
+	qt.Insert(a)
+	qt.Insert(b)
+	qt.Insert(c)
+	qt.Remove(b)
+	a.x, a.y = 1, 6
+	qt.InsertOrMove(a)
+	if err := qt.LogError(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "version 1\ninsert 1 1 1 1 1\ninsert 2 6 6 1 1\ninsert 3 6 1 1 1\nremove 2\nmove 1 1 6 1 1\n"
+	if log.String() != want {
+		t.Errorf("expects log\n%s\ngot\n%s", want, log.String())
+	}
+
+	restored := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	if err := restored.Replay(strings.NewReader(log.String()), decodeObject); err != nil {
+		t.Fatal(err)
+	}
+	if restored.String() != qt.String() {
+		t.Errorf("expects the replayed tree\n%s\nto match\n%s", restored.String(), qt.String())
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	qt := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	for _, log := range []string{"insert x 1 1 1 1", "insert 1", "jump 1", "insert 1 a b c d", "version x"} {
+		if err := qt.Replay(strings.NewReader(log), decodeObject); err == nil {
+			t.Errorf("expects an error replaying %q", log)
+		}
+	}
+	newer := fmt.Sprintf("version %d\ninsert 1 1 1 1 1\n", quadtree.LogVersion+1)
+	if err := qt.Replay(strings.NewReader(newer), decodeObject); err != quadtree.ErrLogVersion {
+		t.Errorf("expects ErrLogVersion replaying a newer log, got %v", err)
+	}
+	if err := qt.Replay(strings.NewReader("insert 1 1 1 1 1\n"), decodeObject); err != nil {
+		t.Errorf("expects logs without a version to be read as version 1, got %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package quadtree
+
+// EventKind tells how an object changed relative to a watched region
+type EventKind int
+
+const (
+	// ObjectEntered reports an object that started overlapping the region
+	ObjectEntered EventKind = iota
+	// ObjectMovedWithin reports an object that moved while overlapping the region
+	ObjectMovedWithin
+	// ObjectLeft reports an object that stopped overlapping the region, or was removed from the tree
+	ObjectLeft
+)
+
+// String returns the name of the event kind
+func (kind EventKind) String() string {
+	switch kind {
+	case ObjectEntered:
+		return "entered"
+	case ObjectMovedWithin:
+		return "moved"
+	case ObjectLeft:
+		return "left"
+	}
+	return "unknown"
+}
+
+// Event reports a change of an object relative to a watched region
+type Event struct {
+	Kind   EventKind
+	Object PhysicalObject
+}
+
+// watcher remembers where the objects overlapping its region were at the previous Update, and in
+// which order the tree visited them
+type watcher struct {
+	region  *Bounds
+	fn      func(Event)
+	inside  map[PhysicalObject]Bounds
+	order   []PhysicalObject
+	stopped bool
+}
+
+// Watch calls fn during every Update for each object entering, moving within or leaving region.
+// Objects already overlapping region when Watch is called do not report entering.
+// Watchers are called in the order they were registered, objects leaving are reported first, in the order
+// the tree visited them at the previous Update, then objects entering or moving in the current visit order.
+// The returned function stops watching, it can be called from fn
+func (qt *Quadtree) Watch(region *Bounds, fn func(Event)) func() {
+	if !qt.ready() {
+		return func() {}
+	}
+	root := qt.root()
+	w := &watcher{region: region, fn: fn}
+	w.inside, w.order = root.overlapping(region)
+	root.m_watchers = append(root.m_watchers, w)
+	return func() {
+		if w.stopped {
+			return
+		}
+		w.stopped = true
+		// copy rather than shift, notifyWatchers may be ranging over the current slice
+		watchers := make([]*watcher, 0, len(root.m_watchers))
+		for _, other := range root.m_watchers {
+			if other != w {
+				watchers = append(watchers, other)
+			}
+		}
+		root.m_watchers = watchers
+	}
+}
+
+// overlapping returns the bounds of every object of the tree overlapping region, and the objects in visit order
+func (qt *Quadtree) overlapping(region *Bounds) (map[PhysicalObject]Bounds, []PhysicalObject) {
+	inside := make(map[PhysicalObject]Bounds)
+	var order []PhysicalObject
+	qt.visitRegion(region, func(obj PhysicalObject) {
+		if b := boundsOf(obj); region.Intersects(b) {
+			inside[obj] = *b
+			order = append(order, obj)
+		}
+	})
+	return inside, order
+}
+
+// notifyWatchers compares the objects overlapping every watched region with the previous Update
+func (qt *Quadtree) notifyWatchers() {
+	for _, w := range qt.m_watchers {
+		if w.stopped {
+			continue
+		}
+		inside, order := qt.overlapping(w.region)
+		previous := w.inside
+		// commit before calling fn, so that the events are not reported again if fn stops or re-enters
+		w.inside, w.order, order = inside, order, w.order
+		for _, obj := range order {
+			if _, ok := inside[obj]; !ok && !w.stopped {
+				w.fn(Event{Kind: ObjectLeft, Object: obj})
+			}
+		}
+		for _, obj := range w.order {
+			if w.stopped {
+				break
+			}
+			if b, ok := previous[obj]; !ok {
+				w.fn(Event{Kind: ObjectEntered, Object: obj})
+			} else if b != inside[obj] {
+				w.fn(Event{Kind: ObjectMovedWithin, Object: obj})
+			}
+		}
+	}
+}
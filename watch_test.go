@@ -0,0 +1,124 @@
+package quadtree_test
+
+import (
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestWatch(t *testing.T) {
+	resident := &TestPhysicalObject{1, 1, 1, 1}
+	visitor := &TestPhysicalObject{6, 6, 1, 1}
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 10)
+	qt.UpdateTree(listOf(resident, visitor))
+
+	var events []quadtree.Event
+	stop := qt.Watch(&quadtree.Bounds{0, 0, 4, 4}, func(e quadtree.Event) {
+		events = append(events, e)
+	})
+	expect := func(step string, expected ...quadtree.Event) {
+		t.Helper()
+		if len(events) != len(expected) {
+			t.Fatalf("%s: expects events %v, got %v", step, expected, events)
+		}
+		for i := range expected {
+			if events[i] != expected[i] {
+				t.Errorf("%s: expects events %v, got %v", step, expected, events)
+			}
+		}
+		events = nil
+	}
+
+	qt.Update(0)
+	expect("idle")
+
+	visitor.x, visitor.y = 2, 2
+	qt.Update(0)
+	expect("enter", quadtree.Event{Kind: quadtree.ObjectEntered, Object: visitor})
+
+	visitor.x = 3
+	qt.Update(0)
+	expect("move", quadtree.Event{Kind: quadtree.ObjectMovedWithin, Object: visitor})
+
+	qt.Remove(resident)
+	visitor.x = 5
+	qt.Update(0)
+	if len(events) != 2 || events[0].Kind != quadtree.ObjectLeft || events[1].Kind != quadtree.ObjectLeft {
+		t.Errorf("expects both objects to leave, got %v", events)
+	}
+	events = nil
+
+	stop()
+	visitor.x = 2
+	qt.Update(0)
+	expect("stopped")
+}
+
+func TestWatchOrder(t *testing.T) {
+	var objects []*TestPhysicalObject
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 10, 1)
+	for i := 0; i < 6; i += 1 {
+		obj := &TestPhysicalObject{6, 6, 1, 1}
+		objects = append(objects, obj)
+		qt.Insert(obj)
+	}
+
+	var got []quadtree.PhysicalObject
+	qt.Watch(&quadtree.Bounds{0, 0, 4, 4}, func(e quadtree.Event) {
+		got = append(got, e.Object)
+	})
+	for round := 0; round < 10; round += 1 {
+		for _, obj := range objects {
+			obj.x, obj.y = 1, 1
+		}
+		qt.Update(0)
+		for _, obj := range objects {
+			obj.x, obj.y = 6, 6
+		}
+		qt.Update(0)
+		if len(got) != 2*len(objects) {
+			t.Fatalf("expects every object to enter and leave, got %v", got)
+		}
+		for i, obj := range got {
+			if obj != objects[i%len(objects)] {
+				t.Fatalf("round %d: expects events in insertion order, got %v", round, got)
+			}
+		}
+		got = nil
+	}
+}
+
+func TestUnwatchFromCallback(t *testing.T) {
+	visitor := &TestPhysicalObject{6, 6, 1, 1}
+	other := &TestPhysicalObject{6, 6, 1, 1}
+	qt := quadtree.CreateQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 10, 1)
+	qt.Insert(visitor)
+	qt.Insert(other)
+
+	region := &quadtree.Bounds{0, 0, 4, 4}
+	var first, second int
+	var stop func()
+	stop = qt.Watch(region, func(e quadtree.Event) {
+		first += 1
+		stop()
+	})
+	qt.Watch(region, func(e quadtree.Event) {
+		second += 1
+	})
+
+	visitor.x, other.x = 1, 1
+	visitor.y, other.y = 1, 1
+	qt.Update(0)
+	if first != 1 {
+		t.Errorf("expects a watcher stopped by its callback to get no more events, got %d", first)
+	}
+	if second != 2 {
+		t.Errorf("expects the next watcher to keep its events, got %d", second)
+	}
+
+	visitor.x = 6
+	qt.Update(0)
+	if first != 1 || second != 3 {
+		t.Errorf("expects only the remaining watcher to be called, got %d and %d", first, second)
+	}
+}
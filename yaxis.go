@@ -0,0 +1,56 @@
+package quadtree
+
+// YAxis is the direction in which Y grows. It only changes how quadrants are named and drawn: the
+// child at index 0 always holds the smallest X and Y, 1 the largest X, 2 the largest Y and 3 both
+type YAxis int
+
+const (
+	// YDown is screen space, Y grows downward and the child at index 0 is the top left quadrant
+	YDown YAxis = iota
+	// YUp is math and physics space, Y grows upward and the child at index 0 is the bottom left quadrant
+	YUp
+)
+
+// quadrantNames are the names of the child quadrants with YDown, and YUp
+var quadrantNames = [2][4]string{
+	{"top left", "top right", "bottom left", "bottom right"},
+	{"bottom left", "bottom right", "top left", "top right"},
+}
+
+// WithYAxis declares the direction in which Y grows, YDown by default. With YUp, the renderers draw
+// larger Y higher up and QuadrantName names the child quadrants accordingly
+func WithYAxis(axis YAxis) Option {
+	return func(c *config) {
+		c.yAxis = axis
+	}
+}
+
+// YAxis returns the direction in which Y grows in the tree
+func (qt *Quadtree) YAxis() YAxis {
+	if !qt.ready() {
+		return YDown
+	}
+	return qt.m_config.yAxis
+}
+
+// QuadrantName returns the name of the child quadrant at index, such as "top left", following the
+// direction of the Y axis of the tree
+func (qt *Quadtree) QuadrantName(index int) string {
+	if index < 0 || index > 3 {
+		return ""
+	}
+	if qt.YAxis() == YUp {
+		return quadrantNames[1][index]
+	}
+	return quadrantNames[0][index]
+}
+
+// screenSpan returns the span from y to y + height as drawn in view from its top edge down,
+// mirrored within view with YUp
+func (c *config) screenSpan(view *Bounds, y, height float64) (top, bottom float64) {
+	if c.yAxis == YUp {
+		mirror := 2*view.Y + view.Height
+		return mirror - y - height, mirror - y
+	}
+	return y, y + height
+}
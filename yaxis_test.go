@@ -0,0 +1,58 @@
+package quadtree_test
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/gmlewis/quadtree"
+)
+
+func TestYAxis(t *testing.T) {
+	down := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 1)
+	up := quadtree.NewQuadtree(&quadtree.Bounds{0, 0, 8, 8}, 1, 1, quadtree.WithYAxis(quadtree.YUp))
+	if down.YAxis() != quadtree.YDown || up.YAxis() != quadtree.YUp {
+		t.Errorf("expects YDown by default and YUp when set, got %v and %v", down.YAxis(), up.YAxis())
+	}
+	if down.QuadrantName(0) != "top left" || up.QuadrantName(0) != "bottom left" || up.QuadrantName(3) != "top right" {
+		t.Errorf("expects quadrants to be named after the Y axis, got %q, %q and %q",
+			down.QuadrantName(0), up.QuadrantName(0), up.QuadrantName(3))
+	}
+	if up.QuadrantName(4) != "" {
+		t.Errorf("expects no name for an invalid index")
+	}
+
+	low := &TestPhysicalObject{1, 1, 1, 1}
+	high := &TestPhysicalObject{5, 5, 2, 1}
+	up.Insert(low)
+	up.Insert(high)
+	// child 0 holds the smallest Y, drawn at the bottom with YUp
+	if up.Nodes[0].FindObject(low) == nil {
+		t.Errorf("expects the quadrant indexes to be independent of the Y axis")
+	}
+	var buf bytes.Buffer
+	if err := up.RenderASCII(&buf, 17, 9); err != nil {
+		t.Fatal(err)
+	}
+	expected := `+-------+-------+
+|       |       |
+|       | ##### |
+|       | ##### |
++-------+-------+
+|       |       |
+| ###   |       |
+| ###   |       |
++-------+-------+
+`
+	if buf.String() != expected {
+		t.Errorf("Quadtree expects to be rendered as:\n%s\nBut rendered as:\n%s", expected, buf.String())
+	}
+
+	img := up.RasterizeMinimap(8, 8, func(quadtree.PhysicalObject) color.Color { return color.White })
+	if _, _, _, a := img.At(1, 6).RGBA(); a == 0 {
+		t.Errorf("expects the low object to be painted near the bottom of the minimap")
+	}
+	if _, _, _, a := img.At(1, 1).RGBA(); a != 0 {
+		t.Errorf("expects nothing painted near the top left of the minimap")
+	}
+}